@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/glints-dev/mcp-netsuite/pkg/mcpserver"
+	"github.com/glints-dev/mcp-netsuite/pkg/netsuite"
+)
+
+// checkStep is a single step of the setup diagnostic.
+type checkStep struct {
+	Name string
+	Run  func() (string, error)
+}
+
+// runCheck validates the configured credentials end to end and writes a
+// pass/fail report. It returns false if any step failed. Later steps are
+// skipped once a step fails, since they all depend on the previous ones.
+func runCheck(config mcpserver.Config, out io.Writer) bool {
+	var client *netsuite.Client
+
+	steps := []checkStep{
+		{
+			Name: "Configuration",
+			Run: func() (string, error) {
+				var missing []string
+				options := config.NetSuiteOptions
+				if options.AccountID == "" {
+					missing = append(missing, "NETSUITE_ACCOUNT_ID")
+				}
+				if options.ClientID == "" {
+					missing = append(missing, "NETSUITE_CLIENT_ID")
+				}
+				if options.CertificateID == "" {
+					missing = append(missing, "NETSUITE_CERTIFICATE_ID")
+				}
+				if len(options.PrivateKeyBytes) == 0 {
+					missing = append(missing, "NETSUITE_PRIVATE_KEY_PATH")
+				}
+
+				if len(missing) > 0 {
+					return "", fmt.Errorf("missing %s", strings.Join(missing, ", "))
+				}
+
+				return fmt.Sprintf("account %s", options.AccountID), nil
+			},
+		},
+		{
+			Name: "Private key",
+			Run: func() (string, error) {
+				var err error
+				client, err = netsuite.NewClient(config.NetSuiteOptions)
+				if err != nil {
+					return "", err
+				}
+
+				return "parsed and signed the client assertion", nil
+			},
+		},
+		{
+			Name: "Token exchange",
+			Run: func() (string, error) {
+				token, err := client.Token()
+				if err != nil {
+					return "", err
+				}
+
+				return fmt.Sprintf("token expires at %s", token.Expiry.Format("2006-01-02 15:04:05 MST")), nil
+			},
+		},
+		{
+			Name: "Identity",
+			Run: func() (string, error) {
+				identity, err := client.WhoAmI(context.Background())
+				if err != nil {
+					return "", err
+				}
+
+				return fmt.Sprintf("connected as %s", identity), nil
+			},
+		},
+		{
+			Name: "Record types",
+			Run: func() (string, error) {
+				recordTypes, err := client.RecordTypes()
+				if err != nil {
+					return "", err
+				}
+
+				sample := recordTypes
+				if len(sample) > 5 {
+					sample = sample[:5]
+				}
+
+				return fmt.Sprintf("%d available, e.g. %s", len(recordTypes), strings.Join(sample, ", ")), nil
+			},
+		},
+	}
+
+	passed := true
+	for _, step := range steps {
+		if !passed {
+			fmt.Fprintf(out, "[SKIP] %s\n", step.Name)
+			continue
+		}
+
+		detail, err := step.Run()
+		if err != nil {
+			fmt.Fprintf(out, "[FAIL] %s: %v\n", step.Name, err)
+			passed = false
+			continue
+		}
+
+		fmt.Fprintf(out, "[PASS] %s: %s\n", step.Name, detail)
+	}
+
+	return passed
+}
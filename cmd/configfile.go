@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// configFile is the layout of the file given with -config. Top-level keys
+// mirror the environment variables and apply to every profile, while each
+// profile holds the keys of one account and overrides the top-level ones.
+//
+//	{
+//	  "profile": "production",
+//	  "NETSUITE_RECORD_TYPES": "customer,item",
+//	  "profiles": {
+//	    "production": {"NETSUITE_ACCOUNT_ID": "1234567", ...},
+//	    "sandbox": {"NETSUITE_ACCOUNT_ID": "1234567_SB1", ...}
+//	  }
+//	}
+type configFile struct {
+	Profile  string
+	Values   map[string]string
+	Profiles map[string]map[string]string
+}
+
+func (f *configFile) UnmarshalJSON(data []byte) error {
+	var parsedData map[string]json.RawMessage
+	if err := json.Unmarshal(data, &parsedData); err != nil {
+		return err
+	}
+
+	f.Values = make(map[string]string)
+	for key, valueJSON := range parsedData {
+		switch key {
+		case "profile":
+			if err := json.Unmarshal(valueJSON, &f.Profile); err != nil {
+				return fmt.Errorf("invalid \"profile\": %w", err)
+			}
+		case "profiles":
+			var profiles map[string]map[string]json.RawMessage
+			if err := json.Unmarshal(valueJSON, &profiles); err != nil {
+				return fmt.Errorf("invalid \"profiles\": %w", err)
+			}
+
+			f.Profiles = make(map[string]map[string]string)
+			for name, profile := range profiles {
+				f.Profiles[name] = make(map[string]string)
+				for profileKey, profileValueJSON := range profile {
+					profileValue, err := configValue(profileValueJSON)
+					if err != nil {
+						return fmt.Errorf("invalid \"%s\" in profile \"%s\": %w", profileKey, name, err)
+					}
+					f.Profiles[name][profileKey] = profileValue
+				}
+			}
+		default:
+			value, err := configValue(valueJSON)
+			if err != nil {
+				return fmt.Errorf("invalid \"%s\": %w", key, err)
+			}
+			f.Values[key] = value
+		}
+	}
+
+	return nil
+}
+
+// configValue accepts strings as well as numbers and booleans, which are
+// converted to the text an environment variable would hold.
+func configValue(valueJSON json.RawMessage) (string, error) {
+	var value interface{}
+	if err := json.Unmarshal(valueJSON, &value); err != nil {
+		return "", err
+	}
+
+	switch value := value.(type) {
+	case string:
+		return value, nil
+	case float64, bool:
+		return string(valueJSON), nil
+	case []interface{}:
+		// Lists such as record types are joined like their variables
+		parts := make([]string, 0, len(value))
+		for _, part := range value {
+			parts = append(parts, fmt.Sprint(part))
+		}
+		return strings.Join(parts, ","), nil
+	default:
+		return "", fmt.Errorf("expected a string, number, boolean, or list")
+	}
+}
+
+// loadConfigFile reads the configuration file and returns the values of the
+// selected profile merged over the top-level ones. An empty profile selects
+// the file's default profile, if any.
+func loadConfigFile(path string, profile string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsedFile configFile
+	if err := json.Unmarshal(data, &parsedFile); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	values := parsedFile.Values
+
+	if profile == "" {
+		profile = parsedFile.Profile
+	}
+
+	if profile != "" {
+		profileValues, ok := parsedFile.Profiles[profile]
+		if !ok {
+			names := make([]string, 0, len(parsedFile.Profiles))
+			for name := range parsedFile.Profiles {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			return nil, fmt.Errorf("profile \"%s\" not found, available profiles: %s", profile, strings.Join(names, ", "))
+		}
+
+		for key, value := range profileValues {
+			values[key] = value
+		}
+	}
+
+	return values, nil
+}
@@ -3,49 +3,116 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/glints-dev/mcp-netsuite/pkg/mcpserver"
 	"github.com/glints-dev/mcp-netsuite/pkg/netsuite"
-	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
-// Config holds all configuration for the MCP server
-type Config struct {
-	NetSuiteOptions netsuite.ClientOptions
-	RecordTypes     []string
-}
+// rateLimitPrefix prefixes the configuration variables holding the rate limit
+// of a tool, e.g. RATE_LIMIT_netsuite_run_suiteql=10/min.
+const rateLimitPrefix = "RATE_LIMIT_"
 
-// loadConfig reads configuration from environment variables and files
-func loadConfig() (Config, error) {
+// loadConfig reads configuration from environment variables and files. The
+// getenv function looks up the value of a configuration variable, and keys
+// lists the names of the variables that are set.
+func loadConfig(getenv func(string) string, keys []string) (mcpserver.Config, error) {
 	// Read private key from file
-	privateKeyPath := os.Getenv("NETSUITE_PRIVATE_KEY_PATH")
+	privateKeyPath := getenv("NETSUITE_PRIVATE_KEY_PATH")
 	var privateKeyBytes []byte
 	var err error
 
 	if privateKeyPath != "" {
 		privateKeyBytes, err = os.ReadFile(privateKeyPath)
 		if err != nil {
-			return Config{}, err
+			return mcpserver.Config{}, err
+		}
+	}
+
+	disableTransientQueries, _ := strconv.ParseBool(getenv("NETSUITE_DISABLE_TRANSIENT_QUERIES"))
+	traceRequests, _ := strconv.ParseBool(getenv("NETSUITE_TRACE"))
+	maxConcurrency, _ := strconv.Atoi(getenv("NETSUITE_MAX_CONCURRENCY"))
+	maxIdleConnsPerHost, _ := strconv.Atoi(getenv("NETSUITE_MAX_IDLE_CONNS_PER_HOST"))
+	idleConnTimeout, _ := time.ParseDuration(getenv("NETSUITE_IDLE_CONN_TIMEOUT"))
+	disableHTTP2, _ := strconv.ParseBool(getenv("NETSUITE_DISABLE_HTTP2"))
+	circuitBreakerThreshold, _ := strconv.Atoi(getenv("NETSUITE_CIRCUIT_BREAKER_THRESHOLD"))
+	circuitBreakerCooldown, _ := time.ParseDuration(getenv("NETSUITE_CIRCUIT_BREAKER_COOLDOWN"))
+	metadataCacheSize, _ := strconv.Atoi(getenv("NETSUITE_METADATA_CACHE_SIZE"))
+	metadataCacheTTL, _ := time.ParseDuration(getenv("NETSUITE_METADATA_CACHE_TTL"))
+	suiteQLRetries, _ := strconv.Atoi(getenv("NETSUITE_SUITEQL_RETRIES"))
+	defaultLimit, _ := strconv.Atoi(getenv("NETSUITE_DEFAULT_LIMIT"))
+	requestBudget, _ := strconv.Atoi(getenv("NETSUITE_REQUEST_BUDGET"))
+	requestBudgetWindow, _ := time.ParseDuration(getenv("NETSUITE_REQUEST_BUDGET_WINDOW"))
+	maxFileBytes, _ := strconv.Atoi(getenv("NETSUITE_MAX_FILE_BYTES"))
+	subsidiary, _ := strconv.Atoi(getenv("NETSUITE_SUBSIDIARY"))
+
+	// Extra headers are given as a JSON object of header names to values
+	var headers http.Header
+	if headersJSON := getenv("NETSUITE_HEADERS"); headersJSON != "" {
+		var headerValues map[string]string
+		if err := json.Unmarshal([]byte(headersJSON), &headerValues); err != nil {
+			return mcpserver.Config{}, fmt.Errorf("failed to parse NETSUITE_HEADERS: %w", err)
+		}
+
+		headers = make(http.Header, len(headerValues))
+		for name, value := range headerValues {
+			headers.Set(name, value)
 		}
 	}
 
 	// Read environment variables into ClientOptions
 	options := netsuite.ClientOptions{
-		AccountID:          os.Getenv("NETSUITE_ACCOUNT_ID"),
-		ClientID:           os.Getenv("NETSUITE_CLIENT_ID"),
-		ClientSecret:       os.Getenv("NETSUITE_CLIENT_SECRET"),
-		CertificateID:      os.Getenv("NETSUITE_CERTIFICATE_ID"),
+		AccountID:          getenv("NETSUITE_ACCOUNT_ID"),
+		ClientID:           getenv("NETSUITE_CLIENT_ID"),
+		ClientSecret:       getenv("NETSUITE_CLIENT_SECRET"),
+		CertificateID:      getenv("NETSUITE_CERTIFICATE_ID"),
 		PrivateKeyBytes:    privateKeyBytes,
-		PrivateKeyPassword: os.Getenv("NETSUITE_PRIVATE_KEY_PASSWORD"),
+		PrivateKeyPassword: getenv("NETSUITE_PRIVATE_KEY_PASSWORD"),
+		SigningAlgorithm:   netsuite.SigningAlgorithm(getenv("NETSUITE_SIGNING_ALGORITHM")),
+
+		APIHostOverride: getenv("NETSUITE_API_HOST_OVERRIDE"),
+
+		DisableTransientQueries: disableTransientQueries,
+
+		CassettePath: getenv("NETSUITE_CASSETTE_PATH"),
+		CassetteMode: netsuite.CassetteMode(getenv("NETSUITE_CASSETTE_MODE")),
+
+		TraceRequests:  traceRequests,
+		MaxConcurrency: maxConcurrency,
+
+		MaxIdleConnsPerHost: maxIdleConnsPerHost,
+		IdleConnTimeout:     idleConnTimeout,
+		DisableHTTP2:        disableHTTP2,
+
+		CircuitBreakerThreshold: circuitBreakerThreshold,
+		CircuitBreakerCooldown:  circuitBreakerCooldown,
+
+		MetadataCacheSize: metadataCacheSize,
+		MetadataCacheTTL:  metadataCacheTTL,
+
+		SuiteQLRetries: suiteQLRetries,
+		DefaultLimit:   defaultLimit,
+
+		RequestBudget:       requestBudget,
+		RequestBudgetWindow: requestBudgetWindow,
+
+		MaxFileBytes: maxFileBytes,
+		Subsidiary:   subsidiary,
+
+		Headers: headers,
 	}
 
 	// Read record types from environment variable
 	var recordTypes []string
-	recordTypesEnv := os.Getenv("NETSUITE_RECORD_TYPES")
+	recordTypesEnv := getenv("NETSUITE_RECORD_TYPES")
 	if recordTypesEnv != "" {
 		// Split by comma and trim whitespace
 		parts := strings.Split(recordTypesEnv, ",")
@@ -57,275 +124,175 @@ func loadConfig() (Config, error) {
 		}
 	}
 
-	config := Config{
-		NetSuiteOptions: options,
-		RecordTypes:     recordTypes,
-	}
+	// Pretty-printed tool results are opt-in since they cost more tokens
+	prettyOutput, _ := strconv.ParseBool(getenv("NETSUITE_PRETTY_OUTPUT"))
 
-	return config, nil
-}
+	// Tools writing to NetSuite are opt-in
+	enableMutations, _ := strconv.ParseBool(getenv("NETSUITE_ENABLE_MUTATIONS"))
 
-func main() {
-	// Load configuration
-	config, err := loadConfig()
-	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
-	}
+	// Requiring explicit column lists is opt-in, for cost control
+	forbidSelectStar, _ := strconv.ParseBool(getenv("NETSUITE_FORBID_SELECT_STAR"))
 
-	// Create NetSuite client
-	client, err := netsuite.NewClient(config.NetSuiteOptions)
-	if err != nil {
-		log.Fatalf("Failed to create NetSuite client: %v", err)
-	}
-
-	// Create MCP server
-	s := server.NewMCPServer(
-		"NetSuite MCP Server",
-		"1.0.0",
-		server.WithToolCapabilities(true),
-		server.WithInstructions(`This is a NetSuite MCP Server that provides access to NetSuite data through two main tools:
-
-IMPORTANT WORKFLOW:
-1. ALWAYS use 'netsuite_get_metadata' FIRST to understand the schema of NetSuite record types before querying
-2. Review the returned metadata to verify field names, data types, and structure
-3. Then use 'netsuite_run_suiteql' with the verified field names from the metadata
-
-TOOL USAGE GUIDELINES:
-
-netsuite_get_metadata:
-- Use this tool to get the schema/structure of NetSuite record types
-- Common record types: customer, item, transaction, salesorder, purchaseorder, invoice, employee, vendor
-- This helps you understand what fields are available and their correct names
-- Always call this before writing SuiteQL queries for unfamiliar record types
-
-netsuite_run_suiteql:
-- Use this tool to execute SuiteQL queries against NetSuite
-- MUST be preceded by netsuite_get_metadata to verify field names and structure
-- Use proper NetSuite field names (often different from UI labels)
-- Table names are typically lowercase (e.g., 'customer', 'item', 'transaction')
-- Include LIMIT clauses to avoid retrieving too much data
-- Be mindful of NetSuite's query performance considerations
-
-Example workflow:
-1. Call netsuite_get_metadata with record_type="customer" 
-2. Review the returned fields and their types
-3. Construct your SuiteQL query using the verified field names
-4. Execute the query with netsuite_run_suiteql`),
-	)
-
-	// Add NetSuite metadata tool
-	metadataTool := mcp.NewTool("netsuite_get_metadata",
-		mcp.WithDescription("Get metadata (schema) for a NetSuite record type"),
-		mcp.WithString("record_type",
-			mcp.Required(),
-			mcp.Description("The NetSuite record type to get metadata for (e.g., 'customer', 'item', 'transaction')"),
-		),
-		mcp.WithArray("included_fields",
-			mcp.Description("Optional list of specific fields to include in the metadata. If not provided, all available fields will be returned."),
-		),
-	)
-
-	// Add tool handler
-	s.AddTool(metadataTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		return handleGetMetadata(client, request)
-	})
-
-	// Add NetSuite SuiteQL tool
-	suiteQLTool := mcp.NewTool("netsuite_run_suiteql",
-		mcp.WithDescription("Execute a SuiteQL query against NetSuite and return the results"),
-		mcp.WithString("query",
-			mcp.Required(),
-			mcp.Description("The SuiteQL query to execute (e.g., 'SELECT id, companyname FROM customer LIMIT 10')"),
-		),
-		mcp.WithNumber("limit",
-			mcp.Description("Maximum number of results to return (default: 100, max: 1000)"),
-		),
-		mcp.WithNumber("offset",
-			mcp.Description("Number of records to skip for pagination (default: 0)"),
-		),
-	)
-
-	// Add SuiteQL tool handler
-	s.AddTool(suiteQLTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		return handleRunSuiteQL(client, request)
-	})
-
-	// Start the stdio server
-	if err := server.ServeStdio(s); err != nil {
-		log.Fatalf("Server error: %v", err)
-	}
-}
+	// Read per-tool rate limits
+	rateLimits := make(map[string]mcpserver.RateLimit)
+	for _, key := range keys {
+		tool, ok := strings.CutPrefix(key, rateLimitPrefix)
+		if !ok || tool == "" {
+			continue
+		}
 
-// handleGetMetadata handles the netsuite_get_metadata tool request
-func handleGetMetadata(client *netsuite.Client, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	// Get record type from arguments
-	recordType, err := request.RequireString("record_type")
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Invalid record_type parameter: %v", err)), nil
+		rateLimit, err := mcpserver.ParseRateLimit(getenv(key))
+		if err != nil {
+			return mcpserver.Config{}, fmt.Errorf("failed to parse %s: %w", key, err)
+		}
+		rateLimits[tool] = rateLimit
 	}
 
-	// Get optional included fields
-	var includedFields []string
-	args := request.GetArguments()
-	if fieldsArg, exists := args["included_fields"]; exists {
-		if fieldsArray, ok := fieldsArg.([]interface{}); ok {
-			for _, field := range fieldsArray {
-				if fieldStr, ok := field.(string); ok {
-					includedFields = append(includedFields, fieldStr)
-				}
-			}
+	// Read column aliases as a JSON object of record types to objects of
+	// column names to aliases
+	var columnAliases map[string]map[string]string
+	if aliasesJSON := getenv("NETSUITE_COLUMN_ALIASES"); aliasesJSON != "" {
+		if err := json.Unmarshal([]byte(aliasesJSON), &columnAliases); err != nil {
+			return mcpserver.Config{}, fmt.Errorf("failed to parse NETSUITE_COLUMN_ALIASES: %w", err)
 		}
 	}
 
-	// Get metadata from NetSuite
-	metadata, err := client.Metadata(recordType, includedFields)
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get metadata for record type '%s': %v", recordType, err)), nil
-	}
+	// Read additional report templates from a JSON file mapping names to
+	// templates
+	var reports map[string]mcpserver.ReportTemplate
+	if reportsPath := getenv("NETSUITE_REPORTS_PATH"); reportsPath != "" {
+		reportsJSON, err := os.ReadFile(reportsPath)
+		if err != nil {
+			return mcpserver.Config{}, fmt.Errorf("failed to read NETSUITE_REPORTS_PATH: %w", err)
+		}
 
-	// Create a structured response
-	response := map[string]interface{}{
-		"record_type":      recordType,
-		"included_fields":  includedFields,
-		"metadata_schema":  metadata,
-		"metadata_summary": generateMetadataSummary(metadata),
+		if err := json.Unmarshal(reportsJSON, &reports); err != nil {
+			return mcpserver.Config{}, fmt.Errorf("failed to parse NETSUITE_REPORTS_PATH: %w", err)
+		}
 	}
 
-	responseJSON, err := json.MarshalIndent(response, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response to JSON: %v", err)), nil
+	config := mcpserver.Config{
+		NetSuiteOptions:  options,
+		RecordTypes:      recordTypes,
+		PrettyOutput:     prettyOutput,
+		ExportDir:        getenv("NETSUITE_EXPORT_DIR"),
+		EnableMutations:  enableMutations,
+		ForbidSelectStar: forbidSelectStar,
+		RateLimits:       rateLimits,
+		ColumnAliases:    columnAliases,
+		Reports:          reports,
 	}
 
-	return mcp.NewToolResultText(string(responseJSON)), nil
+	return config, nil
 }
 
-// generateMetadataSummary creates a human-readable summary of the metadata
-func generateMetadataSummary(metadata interface{}) map[string]interface{} {
-	summary := map[string]interface{}{
-		"description": "NetSuite record metadata schema",
+func main() {
+	check := flag.Bool("check", false, "Validate the NetSuite credentials and exit")
+	configPath := flag.String("config", "", "Path to a JSON configuration file whose keys mirror the environment variables")
+	profile := flag.String("profile", os.Getenv("NETSUITE_PROFILE"), "Account profile to use from the configuration file")
+	warm := flag.Bool("warm", false, "Connect to NetSuite at startup so that the first tool call is fast")
+	preload := flag.Bool("preload", false, "Fetch the metadata of NETSUITE_RECORD_TYPES at startup so that the first tool call for them is fast")
+	flag.Parse()
+
+	var keys []string
+	for _, variable := range os.Environ() {
+		key, _, _ := strings.Cut(variable, "=")
+		keys = append(keys, key)
 	}
 
-	// Try to extract useful information from the metadata structure
-	if metadataMap, ok := metadata.(map[string]interface{}); ok {
-		if properties, exists := metadataMap["properties"]; exists {
-			if propsMap, ok := properties.(map[string]interface{}); ok {
-				fieldCount := len(propsMap)
-				summary["total_fields"] = fieldCount
-
-				// List first few field names as examples
-				fieldNames := make([]string, 0, 10)
-				count := 0
-				for fieldName := range propsMap {
-					if count >= 10 {
-						break
-					}
-					fieldNames = append(fieldNames, fieldName)
-					count++
-				}
-				summary["sample_fields"] = fieldNames
-				if fieldCount > 10 {
-					summary["note"] = fmt.Sprintf("Showing first 10 fields out of %d total fields", fieldCount)
-				}
-			}
+	// Environment variables take precedence over the configuration file
+	getenv := os.Getenv
+	if *configPath != "" {
+		fileValues, err := loadConfigFile(*configPath, *profile)
+		if err != nil {
+			log.Fatalf("Failed to load configuration file: %v", err)
 		}
 
-		if schemaType, exists := metadataMap["type"]; exists {
-			summary["schema_type"] = schemaType
+		for key := range fileValues {
+			keys = append(keys, key)
 		}
-	}
 
-	return summary
-}
+		getenv = func(key string) string {
+			if value, ok := os.LookupEnv(key); ok {
+				return value
+			}
+			return fileValues[key]
+		}
+	}
 
-// handleRunSuiteQL handles the netsuite_run_suiteql tool request
-func handleRunSuiteQL(client *netsuite.Client, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	// Get query from arguments
-	query, err := request.RequireString("query")
+	// Load configuration
+	config, err := loadConfig(getenv, keys)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Invalid query parameter: %v", err)), nil
+		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
-	// Get optional limit and offset from arguments
-	args := request.GetArguments()
-	limit := 100 // default limit
-	offset := 0  // default offset
-
-	if limitArg, exists := args["limit"]; exists {
-		if limitFloat, ok := limitArg.(float64); ok {
-			limit = int(limitFloat)
-			// Validate limit (max 1000 as mentioned in description)
-			if limit > 1000 {
-				limit = 1000
-			}
+	// Run the setup diagnostic instead of serving
+	if *check {
+		if !runCheck(config, os.Stdout) {
+			os.Exit(1)
 		}
+		return
 	}
 
-	if offsetArg, exists := args["offset"]; exists {
-		if offsetFloat, ok := offsetArg.(float64); ok {
-			offset = int(offsetFloat)
-		}
+	// Create NetSuite client
+	client, err := netsuite.NewClient(config.NetSuiteOptions)
+	if err != nil {
+		log.Fatalf("Failed to create NetSuite client: %v", err)
 	}
 
-	// Execute SuiteQL query
-	results, err := client.SuiteQL(query, limit, offset)
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to execute SuiteQL query: %v", err)), nil
+	// Fetch a token and open a connection in the background, which also
+	// reports unusable credentials before the first tool call
+	if *warm {
+		go warmUp(client)
 	}
 
-	// Create a structured response
-	response := map[string]interface{}{
-		"query":        query,
-		"limit":        limit,
-		"offset":       offset,
-		"count":        results.Count,
-		"totalResults": results.TotalResults,
-		"hasMore":      results.HasMore,
-		"items":        results.Items,
-		"summary":      generateSuiteQLSummary(results),
+	// Fill the metadata cache in the background; failures only mean that
+	// the record type is fetched on first use instead
+	if *preload {
+		go preloadMetadata(client, config.RecordTypes)
 	}
 
-	responseJSON, err := json.MarshalIndent(response, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response to JSON: %v", err)), nil
+	// Create MCP server with the built-in tools
+	s := mcpserver.NewServer(client, config)
+
+	// Start the stdio server
+	if err := server.ServeStdio(s); err != nil {
+		log.Fatalf("Server error: %v", err)
 	}
+}
+
+// warmUpTimeout bounds the startup connection to NetSuite.
+const warmUpTimeout = 30 * time.Second
+
+// warmUp pings NetSuite and logs the outcome.
+func warmUp(client *netsuite.Client) {
+	ctx, cancel := context.WithTimeout(context.Background(), warmUpTimeout)
+	defer cancel()
 
-	return mcp.NewToolResultText(string(responseJSON)), nil
+	start := time.Now()
+	if err := client.Ping(ctx); err != nil {
+		log.Printf("Failed to warm up the NetSuite connection: %v", err)
+		return
+	}
+
+	log.Printf("Warmed up the NetSuite connection in %s", time.Since(start).Round(time.Millisecond))
 }
 
-// generateSuiteQLSummary creates a human-readable summary of the SuiteQL results
-func generateSuiteQLSummary(results *netsuite.SuiteQLResponse) map[string]interface{} {
-	summary := map[string]interface{}{
-		"description": "NetSuite SuiteQL query results",
-		"count":       results.Count,
-		"offset":      results.Offset,
-		"total":       results.TotalResults,
-		"hasMore":     results.HasMore,
+// preloadMetadata caches the metadata of the record types and logs the
+// outcome, with a warning per record type that could not be fetched.
+func preloadMetadata(client *netsuite.Client, recordTypes []string) {
+	if len(recordTypes) == 0 {
+		log.Printf("Preloading metadata has no effect without NETSUITE_RECORD_TYPES")
+		return
 	}
 
-	// Try to extract useful information from the first result item
-	if len(results.Items) > 0 {
-		// Parse the first item to see what fields are available
-		var firstItemMap map[string]interface{}
-		if err := json.Unmarshal(results.Items[0], &firstItemMap); err == nil {
-			fieldCount := len(firstItemMap)
-			summary["total_fields"] = fieldCount
-
-			// List first few field names as examples
-			fieldNames := make([]string, 0, 10)
-			count := 0
-			for fieldName := range firstItemMap {
-				if count >= 10 {
-					break
-				}
-				fieldNames = append(fieldNames, fieldName)
-				count++
-			}
-			summary["sample_fields"] = fieldNames
-			if fieldCount > 10 {
-				summary["note"] = fmt.Sprintf("Showing first 10 fields out of %d total fields", fieldCount)
-			}
+	start := time.Now()
+	failures := client.PreloadMetadata(recordTypes)
+	for _, recordType := range recordTypes {
+		if err, ok := failures[recordType]; ok {
+			log.Printf("Failed to preload metadata for record type '%s': %v", recordType, err)
 		}
 	}
 
-	return summary
+	log.Printf("Preloaded metadata for %d of %d record types in %s", len(recordTypes)-len(failures), len(recordTypes), time.Since(start).Round(time.Millisecond))
 }
@@ -0,0 +1,296 @@
+package jsonschematree
+
+import (
+	"fmt"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// CodegenVersion is the version of the shape produced by Codegen. It changes
+// only when the shape does, so that code generators can check it.
+const CodegenVersion = 1
+
+// Field types of CodegenField, besides the JSON Schema primitive types.
+const (
+	// CodegenReference is the type of a field holding a reference to another
+	// record, which is set by internal ID.
+	CodegenReference = "reference"
+
+	// CodegenUnknown is the type of a field whose type cannot be told, such
+	// as one whose alternatives have different types.
+	CodegenUnknown = "unknown"
+)
+
+// CodegenType is a normalized description of a schema meant for generating
+// types in other languages. Unlike the schema itself, it has no references,
+// compositions, or type unions to interpret: every field has exactly one type.
+type CodegenType struct {
+	Version int            `json:"version"`
+	Name    string         `json:"name"`
+	Hash    string         `json:"hash"`
+	Fields  []CodegenField `json:"fields"`
+}
+
+// CodegenField describes a field of a CodegenType, or the items of an array
+// field, in which case it has no name.
+type CodegenField struct {
+	Name string `json:"name,omitempty"`
+
+	// Type is one of string, integer, number, boolean, object, array,
+	// reference, or unknown.
+	Type   string `json:"type"`
+	Format string `json:"format,omitempty"`
+
+	Required bool `json:"required"`
+	Nullable bool `json:"nullable"`
+
+	Description string        `json:"description,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty"`
+	MaxLength   *int          `json:"maxLength,omitempty"`
+
+	// Reference is the record type a reference field points at, when known.
+	Reference string `json:"reference,omitempty"`
+
+	// Fields holds the fields of an object field, and Items the items of an
+	// array field.
+	Fields []CodegenField `json:"fields,omitempty"`
+	Items  *CodegenField  `json:"items,omitempty"`
+}
+
+// Codegen normalizes the schema of a type for code generators. References are
+// resolved with the resolver, except references to other records, which
+// become reference fields. One of several alternatives is picked by taking
+// the first one that is not null, allOf compositions are merged, and types are
+// inferred from properties, items, or enumerations when missing. Fields keep
+// their declaration order. References back to a schema being normalized
+// further up the tree become unknown fields, so that cyclic schemas produce a
+// finite result.
+func Codegen(s *Schema, name string, resolver ReferenceResolver) (*CodegenType, error) {
+	normalizer := &codegenNormalizer{resolver: resolver, ancestors: map[string]bool{}}
+
+	// The schema of a record has the id and refName of a reference itself,
+	// so its properties are normalized directly
+	fields, err := normalizer.properties(s)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := s.Hash()
+	if err != nil {
+		return nil, err
+	}
+
+	return &CodegenType{
+		Version: CodegenVersion,
+		Name:    name,
+		Hash:    hash,
+		Fields:  fields,
+	}, nil
+}
+
+type codegenNormalizer struct {
+	resolver  ReferenceResolver
+	ancestors map[string]bool
+}
+
+func (n *codegenNormalizer) field(s *Schema) (CodegenField, error) {
+	if s == nil {
+		return CodegenField{Type: CodegenUnknown}, nil
+	}
+
+	if s.Ref != "" {
+		if n.ancestors[s.Ref] {
+			return CodegenField{Type: CodegenUnknown, Description: s.Description}, nil
+		}
+
+		target, err := n.resolver.Resolve(s.Ref)
+		if err != nil {
+			return CodegenField{}, fmt.Errorf("failed to resolve ref \"%s\" using resolver: %w", s.Ref, err)
+		}
+
+		// Other records are set by internal ID rather than embedded
+		if target.IsReference() {
+			return CodegenField{
+				Type:        CodegenReference,
+				Nullable:    true,
+				Description: s.Description,
+				Reference:   RefTarget(s.Ref),
+			}, nil
+		}
+
+		n.ancestors[s.Ref] = true
+		defer delete(n.ancestors, s.Ref)
+
+		field, err := n.field(target)
+		if err != nil {
+			return CodegenField{}, err
+		}
+		if s.Description != "" {
+			field.Description = s.Description
+		}
+		return field, nil
+	}
+
+	if s.IsReference() {
+		return CodegenField{
+			Type:        CodegenReference,
+			Nullable:    true,
+			Description: s.Description,
+		}, nil
+	}
+
+	switch {
+	case len(s.OneOf) > 0:
+		return n.alternative(s, s.OneOf)
+	case len(s.AnyOf) > 0:
+		return n.alternative(s, s.AnyOf)
+	case len(s.AllOf) > 0:
+		return n.merged(s)
+	}
+
+	field := CodegenField{
+		Type:        codegenType(s),
+		Format:      s.Format,
+		Nullable:    isNullable(s),
+		Description: s.Description,
+		Enum:        s.Enum,
+		MaxLength:   s.MaxLength,
+	}
+	if field.Description == "" {
+		field.Description = s.Title
+	}
+
+	switch field.Type {
+	case gojsonschema.TYPE_OBJECT:
+		fields, err := n.properties(s)
+		if err != nil {
+			return CodegenField{}, err
+		}
+		field.Fields = fields
+	case gojsonschema.TYPE_ARRAY:
+		items, err := n.field(s.Items)
+		if err != nil {
+			return CodegenField{}, err
+		}
+		field.Items = &items
+	}
+
+	return field, nil
+}
+
+// properties normalizes the properties of an object in declaration order.
+func (n *codegenNormalizer) properties(s *Schema) ([]CodegenField, error) {
+	required := make(map[string]struct{}, len(s.Required))
+	for _, property := range s.Required {
+		required[property] = struct{}{}
+	}
+
+	fields := make([]CodegenField, 0, len(s.Properties))
+	for _, property := range s.PropertyNames() {
+		field, err := n.field(s.Properties[property])
+		if err != nil {
+			return nil, err
+		}
+
+		field.Name = property
+		_, field.Required = required[property]
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// alternative normalizes the first alternative that is not null, which is
+// nullable if any alternative is null.
+func (n *codegenNormalizer) alternative(s *Schema, alternatives []*Schema) (CodegenField, error) {
+	var chosen *Schema
+	nullable := false
+	for _, alternative := range alternatives {
+		if alternative != nil && alternative.Ref == "" && alternative.BaseType() == "null" {
+			nullable = true
+			continue
+		}
+		if chosen == nil {
+			chosen = alternative
+		}
+	}
+
+	field, err := n.field(chosen)
+	if err != nil {
+		return CodegenField{}, err
+	}
+
+	field.Nullable = field.Nullable || nullable
+	if s.Description != "" {
+		field.Description = s.Description
+	}
+
+	return field, nil
+}
+
+// merged normalizes an allOf composition into a single object.
+func (n *codegenNormalizer) merged(s *Schema) (CodegenField, error) {
+	field := CodegenField{
+		Type:        gojsonschema.TYPE_OBJECT,
+		Description: s.Description,
+		Fields:      []CodegenField{},
+	}
+
+	seen := make(map[string]int)
+	for _, part := range s.AllOf {
+		normalized, err := n.field(part)
+		if err != nil {
+			return CodegenField{}, err
+		}
+
+		for _, partField := range normalized.Fields {
+			if i, ok := seen[partField.Name]; ok {
+				field.Fields[i] = partField
+				continue
+			}
+			seen[partField.Name] = len(field.Fields)
+			field.Fields = append(field.Fields, partField)
+		}
+	}
+
+	return field, nil
+}
+
+// codegenType returns the single type of a schema, inferring it when the
+// schema does not declare one.
+func codegenType(s *Schema) string {
+	if baseType := s.BaseType(); baseType != "" && baseType != "null" {
+		return baseType
+	}
+	if len(s.Type) > 1 {
+		return CodegenUnknown
+	}
+
+	switch {
+	case len(s.Properties) > 0:
+		return gojsonschema.TYPE_OBJECT
+	case s.Items != nil:
+		return gojsonschema.TYPE_ARRAY
+	case len(s.Enum) > 0:
+		switch s.Enum[0].(type) {
+		case string:
+			return gojsonschema.TYPE_STRING
+		case bool:
+			return gojsonschema.TYPE_BOOLEAN
+		case float64:
+			return gojsonschema.TYPE_NUMBER
+		}
+	}
+
+	return CodegenUnknown
+}
+
+func isNullable(s *Schema) bool {
+	for _, schemaType := range s.Type {
+		if schemaType == "null" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,113 @@
+package jsonschematree
+
+import (
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// maxExampleDepth is the deepest level of nested objects filled in by
+// Example. Deeper objects are left empty, which also stops recursive
+// schemas.
+const maxExampleDepth = 3
+
+// exampleString is the value of example strings without a format, cut to
+// their maximum length.
+const exampleString = "example"
+
+// exampleFormats are the example values of string formats.
+var exampleFormats = map[string]string{
+	"date":      "2024-01-31",
+	"date-time": "2024-01-31T09:00:00Z",
+	"time":      "09:00:00",
+	"email":     "name@example.com",
+	"uri":       "https://www.example.com",
+	"uuid":      "00000000-0000-0000-0000-000000000000",
+}
+
+// Example returns a representative value of the schema to use as a template,
+// filling in every property. Enumerations use their first value, strings
+// follow their format and maximum length, arrays hold a single item, and
+// references to other records hold a placeholder internal ID.
+func Example(s *Schema) interface{} {
+	return example(s, false, 0)
+}
+
+// RequiredExample is like Example, but only fills in required properties.
+func RequiredExample(s *Schema) interface{} {
+	return example(s, true, 0)
+}
+
+func example(s *Schema, requiredOnly bool, depth int) interface{} {
+	if s == nil {
+		return nil
+	}
+
+	if len(s.Enum) > 0 {
+		return s.Enum[0]
+	}
+
+	// NetSuite sets references to other records by internal ID
+	if s.IsReference() {
+		return map[string]interface{}{"id": "1"}
+	}
+
+	switch {
+	case len(s.OneOf) > 0:
+		return example(s.OneOf[0], requiredOnly, depth)
+	case len(s.AnyOf) > 0:
+		return example(s.AnyOf[0], requiredOnly, depth)
+	case len(s.AllOf) > 0:
+		merged := map[string]interface{}{}
+		for _, part := range s.AllOf {
+			if object, ok := example(part, requiredOnly, depth).(map[string]interface{}); ok {
+				for name, value := range object {
+					merged[name] = value
+				}
+			}
+		}
+		return merged
+	}
+
+	switch s.BaseType() {
+	case gojsonschema.TYPE_OBJECT:
+		object := map[string]interface{}{}
+		if depth >= maxExampleDepth {
+			return object
+		}
+
+		required := make(map[string]struct{}, len(s.Required))
+		for _, property := range s.Required {
+			required[property] = struct{}{}
+		}
+
+		for _, property := range s.PropertyNames() {
+			if _, isRequired := required[property]; requiredOnly && !isRequired {
+				continue
+			}
+			object[property] = example(s.Properties[property], requiredOnly, depth+1)
+		}
+		return object
+	case gojsonschema.TYPE_ARRAY:
+		if s.Items == nil || depth >= maxExampleDepth {
+			return []interface{}{}
+		}
+		return []interface{}{example(s.Items, requiredOnly, depth+1)}
+	case gojsonschema.TYPE_STRING:
+		if value, ok := exampleFormats[strings.ToLower(s.Format)]; ok {
+			return value
+		}
+		if s.MaxLength != nil && *s.MaxLength < len(exampleString) {
+			return exampleString[:max(*s.MaxLength, 0)]
+		}
+		return exampleString
+	case gojsonschema.TYPE_INTEGER:
+		return 1
+	case gojsonschema.TYPE_NUMBER:
+		return 1.5
+	case gojsonschema.TYPE_BOOLEAN:
+		return false
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package jsonschematree
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Hash returns a hex-encoded SHA-256 hash of the schema's canonical form, so
+// that schemas which only differ in the order of their types, required
+// properties, or object keys hash the same. It is meant for detecting schema
+// changes, not for security. It fails for schemas holding values that cannot
+// be marshalled, such as a NaN in an enumeration of a schema built in code.
+func (s *Schema) Hash() (string, error) {
+	// Properties and maps are marshalled with sorted keys, so only slices
+	// need sorting
+	canonicalJSON, err := json.Marshal(s.canonical())
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal schema: %w", err)
+	}
+
+	sum := sha256.Sum256(canonicalJSON)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// canonical returns a copy of the schema with its types and required
+// properties sorted. Compositions keep their order.
+func (s *Schema) canonical() *Schema {
+	if s == nil {
+		return nil
+	}
+
+	canonical := *s
+
+	canonical.Type = append(schemaType{}, s.Type...)
+	sort.Strings(canonical.Type)
+
+	if s.Required != nil {
+		canonical.Required = append([]string{}, s.Required...)
+		sort.Strings(canonical.Required)
+	}
+
+	// Without a declared order, properties are marshalled sorted by name
+	canonical.PropertyOrder = nil
+	if s.Properties != nil {
+		canonical.Properties = make(map[string]*Schema, len(s.Properties))
+		for name, property := range s.Properties {
+			canonical.Properties[name] = property.canonical()
+		}
+	}
+
+	canonical.Items = s.Items.canonical()
+	canonical.OneOf = canonicalAll(s.OneOf)
+	canonical.AnyOf = canonicalAll(s.AnyOf)
+	canonical.AllOf = canonicalAll(s.AllOf)
+
+	return &canonical
+}
+
+func canonicalAll(schemas []*Schema) []*Schema {
+	if schemas == nil {
+		return nil
+	}
+
+	canonical := make([]*Schema, len(schemas))
+	for i, schema := range schemas {
+		canonical[i] = schema.canonical()
+	}
+
+	return canonical
+}
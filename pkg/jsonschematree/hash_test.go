@@ -0,0 +1,111 @@
+package jsonschematree
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestSchemaHash(t *testing.T) {
+	tests := []struct {
+		name      string
+		a         string
+		b         string
+		wantEqual bool
+	}{
+		{
+			name:      "property order",
+			a:         `{"type": "object", "properties": {"id": {"type": "string"}, "name": {"type": "string"}}}`,
+			b:         `{"type": "object", "properties": {"name": {"type": "string"}, "id": {"type": "string"}}}`,
+			wantEqual: true,
+		},
+		{
+			name:      "nested property order",
+			a:         `{"type": "object", "properties": {"entity": {"type": "object", "properties": {"id": {"type": "string"}, "refName": {"type": "string"}}}}}`,
+			b:         `{"type": "object", "properties": {"entity": {"type": "object", "properties": {"refName": {"type": "string"}, "id": {"type": "string"}}}}}`,
+			wantEqual: true,
+		},
+		{
+			name:      "required order",
+			a:         `{"type": "object", "required": ["id", "name"]}`,
+			b:         `{"type": "object", "required": ["name", "id"]}`,
+			wantEqual: true,
+		},
+		{
+			name:      "type order",
+			a:         `{"type": ["string", "null"]}`,
+			b:         `{"type": ["null", "string"]}`,
+			wantEqual: true,
+		},
+		{
+			name: "different property type",
+			a:    `{"type": "object", "properties": {"id": {"type": "string"}}}`,
+			b:    `{"type": "object", "properties": {"id": {"type": "integer"}}}`,
+		},
+		{
+			name: "different property name",
+			a:    `{"type": "object", "properties": {"id": {"type": "string"}}}`,
+			b:    `{"type": "object", "properties": {"externalId": {"type": "string"}}}`,
+		},
+		{
+			name: "composition order",
+			a:    `{"oneOf": [{"type": "string"}, {"type": "integer"}]}`,
+			b:    `{"oneOf": [{"type": "integer"}, {"type": "string"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var a, b Schema
+			if err := json.Unmarshal([]byte(tt.a), &a); err != nil {
+				t.Fatalf("json.Unmarshal(a) error = %v", err)
+			}
+			if err := json.Unmarshal([]byte(tt.b), &b); err != nil {
+				t.Fatalf("json.Unmarshal(b) error = %v", err)
+			}
+
+			hashA, err := a.Hash()
+			if err != nil {
+				t.Fatalf("a.Hash() error = %v", err)
+			}
+			hashB, err := b.Hash()
+			if err != nil {
+				t.Fatalf("b.Hash() error = %v", err)
+			}
+
+			if (hashA == hashB) != tt.wantEqual {
+				t.Errorf("a.Hash() = %s, b.Hash() = %s, want equal %v", hashA, hashB, tt.wantEqual)
+			}
+		})
+	}
+}
+
+func TestSchemaHashError(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema *Schema
+	}{
+		{
+			name:   "NaN enumeration value",
+			schema: &Schema{Type: schemaType{"number"}, Enum: []interface{}{math.NaN()}},
+		},
+		{
+			name: "unmarshallable nested enumeration value",
+			schema: &Schema{
+				Type: schemaType{"object"},
+				Properties: map[string]*Schema{
+					"status": {Enum: []interface{}{make(chan int)}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := tt.schema.Hash()
+			if err == nil {
+				t.Errorf("Hash() = %s, want error", hash)
+			}
+		})
+	}
+}
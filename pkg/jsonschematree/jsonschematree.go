@@ -1,9 +1,12 @@
 package jsonschematree
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/xeipuuv/gojsonschema"
 )
@@ -14,12 +17,35 @@ type Schema struct {
 	Items      *Schema            `json:"items,omitempty"`
 	Format     string             `json:"format,omitempty"`
 
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Required    []string `json:"required,omitempty"`
+	MaxLength   *int     `json:"maxLength,omitempty"`
+
+	Enum []interface{} `json:"enum,omitempty"`
+
 	OneOf []*Schema `json:"oneOf,omitempty"`
+	AnyOf []*Schema `json:"anyOf,omitempty"`
+	AllOf []*Schema `json:"allOf,omitempty"`
 
 	ID  string `json:"$id,omitempty"`
 	Ref string `json:"$ref,omitempty"`
+
+	// PropertyOrder holds the names of the properties in the order they were
+	// declared in, when the schema was unmarshalled. Use PropertyNames to
+	// iterate over the properties.
+	PropertyOrder []string `json:"-"`
+
+	// Extensions holds the vendor extensions of the schema, the keywords
+	// starting with "x-", as they were unmarshalled.
+	Extensions map[string]json.RawMessage `json:"-"`
 }
 
+// ReferenceTypeExtension is the extension naming the record type a schema
+// describes, which tells the target of a reference apart from the name of the
+// schema it points at.
+const ReferenceTypeExtension = "x-ns-referenceType"
+
 type schemaType []string
 
 func (t schemaType) MarshalJSON() ([]byte, error) {
@@ -57,9 +83,14 @@ func (s *Schema) UnmarshalJSON(data []byte) error {
 		}
 
 		switch propertyType := propertyType.(type) {
-		case []string:
+		case []interface{}:
 			for _, jsonType := range propertyType {
-				propertyTypeSet[jsonType] = struct{}{}
+				jsonTypeString, ok := jsonType.(string)
+				if !ok {
+					return errors.New("unexpected type for element of property \"type\"")
+				}
+
+				propertyTypeSet[jsonTypeString] = struct{}{}
 			}
 		case string:
 			propertyTypeSet[propertyType] = struct{}{}
@@ -84,6 +115,7 @@ func (s *Schema) UnmarshalJSON(data []byte) error {
 	for propertyType := range propertyTypeSet {
 		propertyTypes = append(propertyTypes, propertyType)
 	}
+	sort.Strings(propertyTypes)
 	s.Type = propertyTypes
 
 	// Construct the Properties field.
@@ -94,6 +126,11 @@ func (s *Schema) UnmarshalJSON(data []byte) error {
 			return fmt.Errorf("failed to unmarshal JSON: %w", err)
 		}
 
+		propertyOrder, err := objectKeys(propertiesJSON)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal JSON: %w", err)
+		}
+
 		parsedProperties := make(map[string]*Schema)
 		for key, value := range properties {
 			var valueAsSchema Schema
@@ -106,6 +143,7 @@ func (s *Schema) UnmarshalJSON(data []byte) error {
 
 		if len(parsedProperties) != 0 {
 			s.Properties = parsedProperties
+			s.PropertyOrder = propertyOrder
 		}
 	}
 
@@ -131,6 +169,61 @@ func (s *Schema) UnmarshalJSON(data []byte) error {
 		s.Format = format
 	}
 
+	// Construct the Title field.
+	titleJSON, ok := parsedData["title"]
+	if ok {
+		var title string
+		if err := json.Unmarshal(titleJSON, &title); err != nil {
+			return fmt.Errorf("failed to unmarshal JSON: %w", err)
+		}
+
+		s.Title = title
+	}
+
+	// Construct the Description field.
+	descriptionJSON, ok := parsedData["description"]
+	if ok {
+		var description string
+		if err := json.Unmarshal(descriptionJSON, &description); err != nil {
+			return fmt.Errorf("failed to unmarshal JSON: %w", err)
+		}
+
+		s.Description = description
+	}
+
+	// Construct the Required field.
+	requiredJSON, ok := parsedData["required"]
+	if ok {
+		var required []string
+		if err := json.Unmarshal(requiredJSON, &required); err != nil {
+			return fmt.Errorf("failed to unmarshal JSON: %w", err)
+		}
+
+		s.Required = required
+	}
+
+	// Construct the MaxLength field.
+	maxLengthJSON, ok := parsedData["maxLength"]
+	if ok {
+		var maxLength int
+		if err := json.Unmarshal(maxLengthJSON, &maxLength); err != nil {
+			return fmt.Errorf("failed to unmarshal JSON: %w", err)
+		}
+
+		s.MaxLength = &maxLength
+	}
+
+	// Construct the Enum field.
+	enumJSON, ok := parsedData["enum"]
+	if ok {
+		var enum []interface{}
+		if err := json.Unmarshal(enumJSON, &enum); err != nil {
+			return fmt.Errorf("failed to unmarshal JSON: %w", err)
+		}
+
+		s.Enum = enum
+	}
+
 	// Construct the OneOf field.
 	oneOfJSON, ok := parsedData["oneOf"]
 	if ok {
@@ -142,9 +235,97 @@ func (s *Schema) UnmarshalJSON(data []byte) error {
 		s.OneOf = oneOf
 	}
 
+	// Construct the AnyOf field.
+	anyOfJSON, ok := parsedData["anyOf"]
+	if ok {
+		var anyOf []*Schema
+		if err := json.Unmarshal(anyOfJSON, &anyOf); err != nil {
+			return fmt.Errorf("failed to unmarshal JSON: %w", err)
+		}
+
+		s.AnyOf = anyOf
+	}
+
+	// Keep the vendor extensions as they are
+	for key, value := range parsedData {
+		if strings.HasPrefix(key, "x-") {
+			if s.Extensions == nil {
+				s.Extensions = make(map[string]json.RawMessage)
+			}
+			s.Extensions[key] = value
+		}
+	}
+
+	// Construct the AllOf field.
+	allOfJSON, ok := parsedData["allOf"]
+	if ok {
+		var allOf []*Schema
+		if err := json.Unmarshal(allOfJSON, &allOf); err != nil {
+			return fmt.Errorf("failed to unmarshal JSON: %w", err)
+		}
+
+		s.AllOf = allOf
+	}
+
 	return nil
 }
 
+// PropertyNames returns the names of the properties in the order they were
+// declared in. Properties without a declared position, such as ones added
+// after unmarshalling, follow in alphabetical order.
+func (s *Schema) PropertyNames() []string {
+	names := make([]string, 0, len(s.Properties))
+	seen := make(map[string]struct{}, len(s.Properties))
+	for _, name := range s.PropertyOrder {
+		if _, ok := s.Properties[name]; !ok {
+			continue
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+
+	var undeclared []string
+	for name := range s.Properties {
+		if _, ok := seen[name]; !ok {
+			undeclared = append(undeclared, name)
+		}
+	}
+	sort.Strings(undeclared)
+
+	return append(names, undeclared...)
+}
+
+// objectKeys returns the keys of a JSON object in the order they appear in.
+func objectKeys(data []byte) ([]string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	if token, err := decoder.Token(); err != nil {
+		return nil, err
+	} else if token != json.Delim('{') {
+		return nil, errors.New("expected a JSON object")
+	}
+
+	var keys []string
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, token.(string))
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+	}
+
+	return keys, nil
+}
+
 func (s *Schema) BaseType() string {
 	if len(s.Type) == 0 {
 		return ""
@@ -166,6 +347,31 @@ func (s *Schema) BaseType() string {
 	}
 }
 
+// IsDecimal reports whether the schema describes a monetary or decimal value,
+// which must not be handled as a float64 to avoid rounding errors.
+func (s *Schema) IsDecimal() bool {
+	switch s.Format {
+	case "double", "float", "decimal", "currency":
+		return true
+	}
+
+	return s.BaseType() == gojsonschema.TYPE_NUMBER
+}
+
+// IsReference reports whether the schema describes a reference to another
+// record, such as a select field. NetSuite describes these either through
+// "$ref" or as an object holding the "id" and "refName" of the record.
+func (s *Schema) IsReference() bool {
+	if s.Ref != "" {
+		return true
+	}
+
+	_, hasID := s.Properties["id"]
+	_, hasRefName := s.Properties["refName"]
+
+	return hasID && hasRefName
+}
+
 // ResolveReferences resolves all external references in this schema.
 func (s *Schema) ResolveReferences(resolver ReferenceResolver) error {
 	return s.Walk(&referenceResolverWalker{
@@ -213,8 +419,106 @@ func (s *Schema) resolveReference(resolver ReferenceResolver) error {
 	return nil
 }
 
+// Resolved returns a copy of the schema in which every reference is replaced
+// by the schema it refers to. Unlike ResolveReferences, the schema itself is
+// left untouched, and a reference to a schema that is already being resolved
+// further up the tree is kept as is, so that cyclic references produce a
+// finite tree.
+func (s *Schema) Resolved(resolver ReferenceResolver) (*Schema, error) {
+	return s.resolved(resolver, map[string]bool{})
+}
+
+func (s *Schema) resolved(resolver ReferenceResolver, ancestors map[string]bool) (*Schema, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	if s.Ref != "" {
+		if ancestors[s.Ref] {
+			copied := *s
+			return &copied, nil
+		}
+
+		refSchema, err := resolver.Resolve(s.Ref)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to resolve ref \"%s\" using resolver: %w",
+				s.Ref,
+				err,
+			)
+		}
+
+		ancestors[s.Ref] = true
+		defer delete(ancestors, s.Ref)
+
+		return refSchema.resolved(resolver, ancestors)
+	}
+
+	copied := *s
+
+	if s.Properties != nil {
+		copied.Properties = make(map[string]*Schema, len(s.Properties))
+		for property, schema := range s.Properties {
+			resolvedSchema, err := schema.resolved(resolver, ancestors)
+			if err != nil {
+				return nil, err
+			}
+
+			copied.Properties[property] = resolvedSchema
+		}
+	}
+
+	items, err := s.Items.resolved(resolver, ancestors)
+	if err != nil {
+		return nil, err
+	}
+	copied.Items = items
+
+	copied.OneOf, err = resolvedAll(s.OneOf, resolver, ancestors)
+	if err != nil {
+		return nil, err
+	}
+
+	copied.AnyOf, err = resolvedAll(s.AnyOf, resolver, ancestors)
+	if err != nil {
+		return nil, err
+	}
+
+	copied.AllOf, err = resolvedAll(s.AllOf, resolver, ancestors)
+	if err != nil {
+		return nil, err
+	}
+
+	return &copied, nil
+}
+
+// resolvedAll returns resolved copies of a list of sub-schemas.
+func resolvedAll(schemas []*Schema, resolver ReferenceResolver, ancestors map[string]bool) ([]*Schema, error) {
+	if schemas == nil {
+		return nil, nil
+	}
+
+	copied := make([]*Schema, 0, len(schemas))
+	for _, schema := range schemas {
+		resolvedSchema, err := schema.resolved(resolver, ancestors)
+		if err != nil {
+			return nil, err
+		}
+
+		copied = append(copied, resolvedSchema)
+	}
+
+	return copied, nil
+}
+
 // Walk walks through each sub-schema found within the schema, executing the
-// walker for each sub-schema. It allows the sub-schema to be mutated.
+// walker for each sub-schema. It allows the sub-schema to be mutated. The
+// walker is run on every property, on the items of array properties, and on
+// each member of the oneOf, anyOf, and allOf of a property, descending into
+// the properties of all of them. The items of a composition member that is
+// itself an array are not visited. References are not followed, so a property
+// holding only a $ref is visited but has nothing to descend into, unless the
+// walker resolves it in place.
 func (s *Schema) Walk(walker SchemaWalker) error {
 	stack := NewStack()
 	stack.Push(&stackItem{
@@ -238,30 +542,46 @@ func (s *Schema) Walk(walker SchemaWalker) error {
 				)
 			}
 
-			if len(schema.OneOf) > 0 {
-				for _, alternative := range schema.OneOf {
-					if err := walker.Walk(alternative); err != nil {
-						return fmt.Errorf(
-							"failed to resolve json schema reference: %w",
-							err,
-						)
+			if len(schema.OneOf) > 0 || len(schema.AnyOf) > 0 || len(schema.AllOf) > 0 {
+				compositions := []struct {
+					keyword    string
+					subschemas []*Schema
+				}{
+					{"oneOf", schema.OneOf},
+					{"anyOf", schema.AnyOf},
+					{"allOf", schema.AllOf},
+				}
+				for _, composition := range compositions {
+					for _, subschema := range composition.subschemas {
+						if err := walker.Walk(subschema); err != nil {
+							return fmt.Errorf(
+								"failed to resolve json schema reference: %w",
+								err,
+							)
+						}
+
+						stack.Push(&stackItem{
+							Node: subschema,
+							Path: append(item.Path, "properties", property, composition.keyword),
+						})
 					}
-
-					stack.Push(&stackItem{
-						Node: alternative,
-						Path: append(item.Path, "properties", property, "oneOf"),
-					})
 				}
 
 				continue
 			}
 
+			// Properties without a type, such as unresolved references, have
+			// nothing to descend into unless they declare properties.
 			propertyType := schema.BaseType()
 			if propertyType == "" {
-				return fmt.Errorf(
-					"key \"type\" not found on property \"%s\"",
-					property,
-				)
+				if schema.Properties != nil {
+					stack.Push(&stackItem{
+						Node: schema,
+						Path: append(item.Path, "properties", property),
+					})
+				}
+
+				continue
 			}
 
 			if propertyType == gojsonschema.TYPE_ARRAY {
@@ -297,6 +617,188 @@ func (s *Schema) Walk(walker SchemaWalker) error {
 	return nil
 }
 
+// Reference describes a property that points at another schema through
+// "$ref".
+type Reference struct {
+	Path   string `json:"path"`
+	Target string `json:"target"`
+}
+
+// ReferenceType returns the record type named by the ReferenceTypeExtension
+// of the schema, or an empty string if it has none.
+func (s *Schema) ReferenceType() string {
+	var recordType string
+	if err := json.Unmarshal(s.Extensions[ReferenceTypeExtension], &recordType); err != nil {
+		return ""
+	}
+
+	return recordType
+}
+
+// References returns every property within the schema that refers to another
+// schema, sorted by path. Each reference is resolved with the resolver to
+// read its target record type from the ReferenceTypeExtension of the schema
+// it points at, falling back to the name of that schema. References within
+// the schemas pointed at are not followed, so cyclic references are not a
+// concern. Without a resolver, targets are taken from the references alone.
+func (s *Schema) References(resolver ReferenceResolver) ([]Reference, error) {
+	references := []Reference{}
+
+	target := func(ref string) (string, error) {
+		if resolver == nil {
+			return RefTarget(ref), nil
+		}
+
+		resolved, err := resolver.Resolve(ref)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve ref \"%s\" using resolver: %w", ref, err)
+		}
+		if recordType := resolved.ReferenceType(); recordType != "" {
+			return recordType, nil
+		}
+
+		return RefTarget(ref), nil
+	}
+
+	stack := NewStack()
+	stack.Push(&stackItem{
+		Node: s,
+		Path: []string{},
+	})
+
+	for !stack.Empty() {
+		item := stack.Pop()
+
+		for property, schema := range item.Node.Properties {
+			path := append(append([]string{}, item.Path...), property)
+
+			if schema.Ref != "" {
+				recordType, err := target(schema.Ref)
+				if err != nil {
+					return nil, err
+				}
+				references = append(references, Reference{
+					Path:   strings.Join(path, "."),
+					Target: recordType,
+				})
+				continue
+			}
+
+			for _, alternatives := range [][]*Schema{schema.OneOf, schema.AnyOf} {
+				for _, alternative := range alternatives {
+					stack.Push(&stackItem{Node: alternative, Path: path})
+				}
+			}
+
+			if schema.Items != nil {
+				itemsPath := append(append([]string{}, item.Path...), property+"[]")
+				if schema.Items.Ref != "" {
+					recordType, err := target(schema.Items.Ref)
+					if err != nil {
+						return nil, err
+					}
+					references = append(references, Reference{
+						Path:   strings.Join(itemsPath, "."),
+						Target: recordType,
+					})
+				} else {
+					stack.Push(&stackItem{Node: schema.Items, Path: itemsPath})
+				}
+			}
+
+			if schema.Properties != nil {
+				stack.Push(&stackItem{Node: schema, Path: path})
+			}
+		}
+	}
+
+	sort.Slice(references, func(i, j int) bool {
+		return references[i].Path < references[j].Path
+	})
+
+	return references, nil
+}
+
+// FieldInfo describes a single field of a flattened schema.
+type FieldInfo struct {
+	Path        string `json:"path"`
+	Type        string `json:"type,omitempty"`
+	Format      string `json:"format,omitempty"`
+	Required    bool   `json:"required"`
+	Description string `json:"description,omitempty"`
+	Ref         string `json:"ref,omitempty"`
+}
+
+// Flatten returns every field within the schema with its dotted path, in
+// declaration order, each field followed by the fields nested in it. Items of
+// arrays are addressed with a "[]" suffix, e.g. "addressBook[].id". A path
+// declared by several alternatives of a composition is only returned once, as
+// the first alternative declares it.
+func Flatten(s *Schema) []FieldInfo {
+	flattener := &flattener{seen: make(map[string]struct{})}
+	flattener.flatten(s, nil)
+
+	return flattener.fields
+}
+
+type flattener struct {
+	fields []FieldInfo
+	seen   map[string]struct{}
+}
+
+func (f *flattener) flatten(s *Schema, path []string) {
+	required := make(map[string]struct{}, len(s.Required))
+	for _, property := range s.Required {
+		required[property] = struct{}{}
+	}
+
+	for _, property := range s.PropertyNames() {
+		schema := s.Properties[property]
+		propertyPath := append(append([]string{}, path...), property)
+		joinedPath := strings.Join(propertyPath, ".")
+		_, isRequired := required[property]
+
+		if _, ok := f.seen[joinedPath]; !ok {
+			f.seen[joinedPath] = struct{}{}
+			f.fields = append(f.fields, FieldInfo{
+				Path:        joinedPath,
+				Type:        schema.BaseType(),
+				Format:      schema.Format,
+				Required:    isRequired,
+				Description: schema.Description,
+				Ref:         schema.Ref,
+			})
+		}
+
+		if schema.Properties != nil {
+			f.flatten(schema, propertyPath)
+		}
+
+		if schema.Items != nil {
+			f.flatten(schema.Items, append(append([]string{}, path...), property+"[]"))
+		}
+
+		for _, alternatives := range [][]*Schema{schema.OneOf, schema.AnyOf} {
+			for _, alternative := range alternatives {
+				f.flatten(alternative, propertyPath)
+			}
+		}
+	}
+}
+
+// RefTarget returns the name of the schema a reference points at, such as
+// "customer" for "#/components/schemas/customer". For other references, it is
+// the last segment of the reference.
+func RefTarget(ref string) string {
+	if parsed, err := ParseRef(ref); err == nil {
+		if name, _, ok := parsed.ComponentSchema(); ok {
+			return name
+		}
+	}
+
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
 type SchemaWalker interface {
 	Walk(schema *Schema) error
 }
@@ -0,0 +1,206 @@
+package jsonschematree
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSchemaUnmarshalJSONType(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		want     []string
+		wantJSON string
+		wantErr  bool
+	}{
+		{name: "single type", data: `{"type": "string"}`, want: []string{"string"}, wantJSON: `"string"`},
+		{name: "nullable array", data: `{"type": ["string", "null"]}`, want: []string{"null", "string"}, wantJSON: `["null","string"]`},
+		{name: "array order", data: `{"type": ["null", "string"]}`, want: []string{"null", "string"}, wantJSON: `["null","string"]`},
+		{name: "duplicates", data: `{"type": ["string", "string"]}`, want: []string{"string"}, wantJSON: `"string"`},
+		{name: "nullable keyword", data: `{"type": "string", "nullable": true}`, want: []string{"null", "string"}, wantJSON: `["null","string"]`},
+		{name: "nullable array and keyword", data: `{"type": ["string", "null"], "nullable": true}`, want: []string{"null", "string"}, wantJSON: `["null","string"]`},
+		{name: "no type", data: `{}`, want: []string{}, wantJSON: `[]`},
+		{name: "non-string element", data: `{"type": ["string", 1]}`, wantErr: true},
+		{name: "object type", data: `{"type": {}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schema Schema
+			err := json.Unmarshal([]byte(tt.data), &schema)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if !reflect.DeepEqual([]string(schema.Type), tt.want) {
+				t.Errorf("Type = %q, want %q", schema.Type, tt.want)
+			}
+
+			typeJSON, err := json.Marshal(schema.Type)
+			if err != nil {
+				t.Fatalf("MarshalJSON() error = %v", err)
+			}
+			if string(typeJSON) != tt.wantJSON {
+				t.Errorf("MarshalJSON() = %s, want %s", typeJSON, tt.wantJSON)
+			}
+		})
+	}
+}
+
+// recordingWalker records the description of every schema it walks.
+type recordingWalker struct {
+	visited []string
+}
+
+func (w *recordingWalker) Walk(schema *Schema) error {
+	w.visited = append(w.visited, schema.Description)
+	return nil
+}
+
+func TestSchemaWalk(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   []string
+	}{
+		{
+			name: "ref-only property",
+			schema: `{"type": "object", "properties": {
+				"subsidiary": {"$ref": "#/components/schemas/subsidiary", "description": "subsidiary"}
+			}}`,
+			want: []string{"subsidiary"},
+		},
+		{
+			name: "allOf-only property",
+			schema: `{"type": "object", "properties": {
+				"entity": {"description": "entity", "allOf": [
+					{"$ref": "#/components/schemas/customer", "description": "entity.allOf.ref"},
+					{"type": "object", "description": "entity.allOf.object", "properties": {
+						"id": {"type": "string", "description": "entity.allOf.object.id"}
+					}}
+				]}
+			}}`,
+			want: []string{"entity", "entity.allOf.ref", "entity.allOf.object", "entity.allOf.object.id"},
+		},
+		{
+			name: "anyOf and oneOf properties",
+			schema: `{"type": "object", "properties": {
+				"amount": {"description": "amount", "anyOf": [
+					{"type": "number", "description": "amount.anyOf.number"},
+					{"type": "object", "description": "amount.anyOf.object", "properties": {
+						"value": {"type": "number", "description": "amount.anyOf.object.value"}
+					}}
+				]},
+				"status": {"description": "status", "oneOf": [
+					{"type": "string", "description": "status.oneOf.string"},
+					{"type": "object", "description": "status.oneOf.object", "properties": {
+						"id": {"type": "string", "description": "status.oneOf.object.id"}
+					}}
+				]}
+			}}`,
+			want: []string{
+				"amount", "amount.anyOf.number", "amount.anyOf.object", "amount.anyOf.object.value",
+				"status", "status.oneOf.string", "status.oneOf.object", "status.oneOf.object.id",
+			},
+		},
+		{
+			name: "array property",
+			schema: `{"type": "object", "properties": {
+				"items": {"type": "array", "description": "items", "items": {"type": "object", "description": "items.items", "properties": {
+					"line": {"type": "integer", "description": "items.items.line"}
+				}}}
+			}}`,
+			want: []string{"items", "items.items", "items.items.line"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schema Schema
+			if err := json.Unmarshal([]byte(tt.schema), &schema); err != nil {
+				t.Fatalf("failed to unmarshal schema: %v", err)
+			}
+
+			walker := &recordingWalker{}
+			if err := schema.Walk(walker); err != nil {
+				t.Fatalf("Walk() error = %v", err)
+			}
+
+			sort.Strings(walker.visited)
+			want := append([]string{}, tt.want...)
+			sort.Strings(want)
+			if !reflect.DeepEqual(walker.visited, want) {
+				t.Errorf("Walk() visited %q, want %q", walker.visited, want)
+			}
+		})
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   []FieldInfo
+	}{
+		{
+			name:   "declaration order",
+			schema: `{"type": "object", "properties": {"tranId": {"type": "string"}, "entity": {"type": "string"}, "amount": {"type": "number"}}, "required": ["entity"]}`,
+			want: []FieldInfo{
+				{Path: "tranId", Type: "string"},
+				{Path: "entity", Type: "string", Required: true},
+				{Path: "amount", Type: "number"},
+			},
+		},
+		{
+			name:   "nested fields follow their parent",
+			schema: `{"type": "object", "properties": {"subsidiary": {"type": "object", "properties": {"refName": {"type": "string"}, "id": {"type": "string"}}}, "memo": {"type": "string"}}}`,
+			want: []FieldInfo{
+				{Path: "subsidiary", Type: "object"},
+				{Path: "subsidiary.refName", Type: "string"},
+				{Path: "subsidiary.id", Type: "string"},
+				{Path: "memo", Type: "string"},
+			},
+		},
+		{
+			name:   "array items",
+			schema: `{"type": "object", "properties": {"addressBook": {"type": "array", "items": {"type": "object", "properties": {"label": {"type": "string"}}}}, "email": {"type": "string", "format": "email"}}}`,
+			want: []FieldInfo{
+				{Path: "addressBook", Type: "array"},
+				{Path: "addressBook[].label", Type: "string"},
+				{Path: "email", Type: "string", Format: "email"},
+			},
+		},
+		{
+			name:   "alternatives declaring the same path",
+			schema: `{"type": "object", "properties": {"entity": {"oneOf": [{"type": "object", "properties": {"id": {"type": "string", "description": "first"}}}, {"type": "object", "properties": {"id": {"type": "integer"}, "type": {"type": "string"}}}]}}}`,
+			want: []FieldInfo{
+				{Path: "entity"},
+				{Path: "entity.id", Type: "string", Description: "first"},
+				{Path: "entity.type", Type: "string"},
+			},
+		},
+		{
+			name:   "reference",
+			schema: `{"type": "object", "properties": {"currency": {"$ref": "#/components/schemas/currency"}}}`,
+			want:   []FieldInfo{{Path: "currency", Ref: "#/components/schemas/currency"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schema Schema
+			if err := json.Unmarshal([]byte(tt.schema), &schema); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+
+			if got := Flatten(&schema); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Flatten() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package jsonschematree
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SchemaRef is a parsed "$ref". Document is empty for references within the
+// same document, and Pointer holds the unescaped tokens of the JSON Pointer
+// fragment, e.g. ["components", "schemas", "customer"] for
+// "#/components/schemas/customer".
+type SchemaRef struct {
+	Document string
+	Pointer  []string
+}
+
+// ParseRef splits a "$ref" into its document and JSON Pointer fragment.
+func ParseRef(ref string) (SchemaRef, error) {
+	document, fragment, _ := strings.Cut(ref, "#")
+
+	parsed := SchemaRef{Document: document}
+	if fragment == "" {
+		return parsed, nil
+	}
+
+	if !strings.HasPrefix(fragment, "/") {
+		return SchemaRef{}, fmt.Errorf("invalid JSON pointer \"%s\"", fragment)
+	}
+
+	for _, token := range strings.Split(fragment[1:], "/") {
+		// "~1" must be replaced before "~0", as "~01" stands for "~1"
+		token = strings.ReplaceAll(token, "~1", "/")
+		token = strings.ReplaceAll(token, "~0", "~")
+		parsed.Pointer = append(parsed.Pointer, token)
+	}
+
+	return parsed, nil
+}
+
+// ComponentSchema returns the name of the schema under "#/components/schemas"
+// the reference points into, and the remaining pointer tokens within it.
+func (ref SchemaRef) ComponentSchema() (string, []string, bool) {
+	if len(ref.Pointer) < 3 || ref.Pointer[0] != "components" || ref.Pointer[1] != "schemas" {
+		return "", nil, false
+	}
+
+	return ref.Pointer[2], ref.Pointer[3:], true
+}
+
+// At returns the sub-schema the pointer tokens lead to, following the
+// "properties", "items", "oneOf", "anyOf", and "allOf" keywords.
+func (s *Schema) At(pointer []string) (*Schema, error) {
+	current := s
+	for i := 0; i < len(pointer); i++ {
+		keyword := pointer[i]
+
+		if keyword == "items" {
+			if current.Items == nil {
+				return nil, fmt.Errorf("schema at /%s has no items", strings.Join(pointer[:i], "/"))
+			}
+			current = current.Items
+			continue
+		}
+
+		if i+1 >= len(pointer) {
+			return nil, fmt.Errorf("incomplete JSON pointer /%s", strings.Join(pointer, "/"))
+		}
+		i++
+
+		switch keyword {
+		case "properties":
+			property, ok := current.Properties[pointer[i]]
+			if !ok {
+				return nil, fmt.Errorf("property \"%s\" not found at /%s", pointer[i], strings.Join(pointer[:i-1], "/"))
+			}
+			current = property
+		case "oneOf", "anyOf", "allOf":
+			alternatives := map[string][]*Schema{
+				"oneOf": current.OneOf,
+				"anyOf": current.AnyOf,
+				"allOf": current.AllOf,
+			}[keyword]
+
+			index, err := strconv.Atoi(pointer[i])
+			if err != nil || index < 0 || index >= len(alternatives) {
+				return nil, fmt.Errorf("%s index \"%s\" out of range at /%s", keyword, pointer[i], strings.Join(pointer[:i-1], "/"))
+			}
+			current = alternatives[index]
+		default:
+			return nil, fmt.Errorf("unsupported JSON pointer keyword \"%s\"", keyword)
+		}
+	}
+
+	return current, nil
+}
@@ -0,0 +1,135 @@
+package jsonschematree
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseRef(t *testing.T) {
+	tests := []struct {
+		name    string
+		ref     string
+		want    SchemaRef
+		wantErr bool
+	}{
+		{
+			name: "component schema",
+			ref:  "#/components/schemas/customer",
+			want: SchemaRef{Pointer: []string{"components", "schemas", "customer"}},
+		},
+		{
+			name: "document and pointer",
+			ref:  "https://example.com/metadata-catalog/customer#/properties/subsidiary",
+			want: SchemaRef{Document: "https://example.com/metadata-catalog/customer", Pointer: []string{"properties", "subsidiary"}},
+		},
+		{
+			name: "document only",
+			ref:  "https://example.com/metadata-catalog/customer",
+			want: SchemaRef{Document: "https://example.com/metadata-catalog/customer"},
+		},
+		{
+			name: "escaped slash",
+			ref:  "#/properties/a~1b",
+			want: SchemaRef{Pointer: []string{"properties", "a/b"}},
+		},
+		{
+			name: "escaped tilde",
+			ref:  "#/properties/a~0b",
+			want: SchemaRef{Pointer: []string{"properties", "a~b"}},
+		},
+		{
+			name: "escaped tilde followed by one",
+			ref:  "#/properties/a~01",
+			want: SchemaRef{Pointer: []string{"properties", "a~1"}},
+		},
+		{
+			name: "array index",
+			ref:  "#/properties/status/oneOf/1",
+			want: SchemaRef{Pointer: []string{"properties", "status", "oneOf", "1"}},
+		},
+		{
+			name: "empty segment",
+			ref:  "#/properties/",
+			want: SchemaRef{Pointer: []string{"properties", ""}},
+		},
+		{
+			name:    "pointer without leading slash",
+			ref:     "#components/schemas/customer",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRef(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRef(%q) error = %v, wantErr %v", tt.ref, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRef(%q) = %+v, want %+v", tt.ref, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchemaAt(t *testing.T) {
+	var schema Schema
+	if err := json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"a/b": {"type": "string", "description": "slash"},
+			"a~b": {"type": "string", "description": "tilde"},
+			"status": {"oneOf": [
+				{"type": "string", "description": "status.oneOf.0"},
+				{"type": "object", "description": "status.oneOf.1", "properties": {
+					"id": {"type": "string", "description": "status.oneOf.1.id"}
+				}}
+			]},
+			"lines": {"type": "array", "items": {"type": "object", "description": "lines.items"}}
+		}
+	}`), &schema); err != nil {
+		t.Fatalf("failed to unmarshal schema: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{name: "escaped slash", ref: "#/properties/a~1b", want: "slash"},
+		{name: "escaped tilde", ref: "#/properties/a~0b", want: "tilde"},
+		{name: "array index", ref: "#/properties/status/oneOf/0", want: "status.oneOf.0"},
+		{name: "property of array index", ref: "#/properties/status/oneOf/1/properties/id", want: "status.oneOf.1.id"},
+		{name: "items", ref: "#/properties/lines/items", want: "lines.items"},
+		{name: "root", ref: "#", want: ""},
+		{name: "missing property", ref: "#/properties/missing", wantErr: true},
+		{name: "missing segment", ref: "#/properties", wantErr: true},
+		{name: "index out of range", ref: "#/properties/status/oneOf/2", wantErr: true},
+		{name: "negative index", ref: "#/properties/status/oneOf/-1", wantErr: true},
+		{name: "non-numeric index", ref: "#/properties/status/oneOf/first", wantErr: true},
+		{name: "no items", ref: "#/properties/status/items", wantErr: true},
+		{name: "unsupported keyword", ref: "#/definitions/customer", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := ParseRef(tt.ref)
+			if err != nil {
+				t.Fatalf("ParseRef(%q) error = %v", tt.ref, err)
+			}
+
+			got, err := schema.At(ref.Pointer)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("At(%q) error = %v, wantErr %v", ref.Pointer, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Description != tt.want {
+				t.Errorf("At(%q) = schema %q, want %q", ref.Pointer, got.Description, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package jsonschematree
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// MarshalJSON encodes the schema with its properties in declaration order.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := s.WriteJSON(&buffer); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// WriteJSON writes the schema as JSON to w, producing the same output as
+// json.Marshal. Unlike json.Marshal, it does not hold the encoding of the
+// whole schema in memory, which matters for schemas as large as the one of
+// the transaction record type.
+func (s *Schema) WriteJSON(w io.Writer) error {
+	buffered := bufio.NewWriter(w)
+	encoder := &streamEncoder{w: buffered}
+	encoder.schema(s)
+	if encoder.err != nil {
+		return encoder.err
+	}
+
+	return buffered.Flush()
+}
+
+// streamEncoder writes JSON, keeping the first error so that every write can
+// be chained without checking it.
+type streamEncoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (e *streamEncoder) raw(s string) {
+	if e.err == nil {
+		_, e.err = e.w.WriteString(s)
+	}
+}
+
+// value writes a leaf value with json.Marshal, which escapes strings the same
+// way the rest of the schema is escaped.
+func (e *streamEncoder) value(v interface{}) {
+	if e.err != nil {
+		return
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		e.err = err
+		return
+	}
+	_, e.err = e.w.Write(data)
+}
+
+// field writes the key of an object member, preceded by a comma unless it is
+// the first member.
+func (e *streamEncoder) field(first *bool, key string) {
+	if !*first {
+		e.raw(",")
+	}
+	*first = false
+	e.value(key)
+	e.raw(":")
+}
+
+// schema mirrors the struct tags of Schema, in field order.
+func (e *streamEncoder) schema(s *Schema) {
+	if s == nil {
+		e.raw("null")
+		return
+	}
+
+	first := true
+	e.raw("{")
+
+	e.field(&first, "type")
+	e.value(s.Type)
+
+	if len(s.Properties) > 0 {
+		e.field(&first, "properties")
+		e.properties(s)
+	}
+
+	if s.Items != nil {
+		e.field(&first, "items")
+		e.schema(s.Items)
+	}
+
+	if s.Format != "" {
+		e.field(&first, "format")
+		e.value(s.Format)
+	}
+
+	if s.Title != "" {
+		e.field(&first, "title")
+		e.value(s.Title)
+	}
+
+	if s.Description != "" {
+		e.field(&first, "description")
+		e.value(s.Description)
+	}
+
+	if len(s.Required) > 0 {
+		e.field(&first, "required")
+		e.value(s.Required)
+	}
+
+	if s.MaxLength != nil {
+		e.field(&first, "maxLength")
+		e.raw(strconv.Itoa(*s.MaxLength))
+	}
+
+	if len(s.Enum) > 0 {
+		e.field(&first, "enum")
+		e.value(s.Enum)
+	}
+
+	for _, composition := range []struct {
+		key     string
+		schemas []*Schema
+	}{
+		{"oneOf", s.OneOf},
+		{"anyOf", s.AnyOf},
+		{"allOf", s.AllOf},
+	} {
+		if len(composition.schemas) == 0 {
+			continue
+		}
+
+		e.field(&first, composition.key)
+		e.raw("[")
+		for i, schema := range composition.schemas {
+			if i > 0 {
+				e.raw(",")
+			}
+			e.schema(schema)
+		}
+		e.raw("]")
+	}
+
+	if s.ID != "" {
+		e.field(&first, "$id")
+		e.value(s.ID)
+	}
+
+	if s.Ref != "" {
+		e.field(&first, "$ref")
+		e.value(s.Ref)
+	}
+
+	e.raw("}")
+}
+
+// properties writes the properties in declaration order.
+func (e *streamEncoder) properties(s *Schema) {
+	first := true
+	e.raw("{")
+	for _, name := range s.PropertyNames() {
+		e.field(&first, name)
+		e.schema(s.Properties[name])
+	}
+	e.raw("}")
+}
@@ -0,0 +1,202 @@
+package jsonschematree
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Violation describes a value that does not conform to its schema.
+type Violation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// Validate checks a decoded JSON value against the schema and returns every
+// violation found, sorted by path. Unresolved references are not checked.
+func (s *Schema) Validate(value interface{}) []Violation {
+	violations := append([]Violation{}, s.validate(value, "")...)
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Path < violations[j].Path
+	})
+
+	return violations
+}
+
+// validate checks the value against every member of allOf, the oneOf and
+// anyOf alternatives, and the schema's own constraints, all of which must
+// hold.
+func (s *Schema) validate(value interface{}, path string) []Violation {
+	if s.Ref != "" {
+		return nil
+	}
+
+	var violations []Violation
+	for _, member := range s.AllOf {
+		if member != nil {
+			violations = append(violations, member.validate(value, path)...)
+		}
+	}
+
+	if len(s.OneOf) > 0 {
+		violations = append(violations, s.validateOneOf(value, path)...)
+	}
+
+	if len(s.AnyOf) > 0 {
+		violations = append(violations, s.validateAnyOf(value, path)...)
+	}
+
+	valueType := jsonType(value)
+	if !s.allowsType(valueType) {
+		return append(violations, Violation{
+			Path:    path,
+			Message: fmt.Sprintf("expected %s, got %s", strings.Join(s.Type, " or "), valueType),
+		})
+	}
+
+	if len(s.Enum) > 0 && !s.allowsValue(value) {
+		violations = append(violations, Violation{
+			Path:    path,
+			Message: fmt.Sprintf("value is not one of the allowed values %v", s.Enum),
+		})
+	}
+
+	switch value := value.(type) {
+	case string:
+		if s.MaxLength != nil && utf8.RuneCountInString(value) > *s.MaxLength {
+			violations = append(violations, Violation{
+				Path:    path,
+				Message: fmt.Sprintf("string is longer than %d characters", *s.MaxLength),
+			})
+		}
+	case map[string]interface{}:
+		for _, property := range s.Required {
+			if _, ok := value[property]; !ok {
+				violations = append(violations, Violation{
+					Path:    joinPath(path, property),
+					Message: "required field is missing",
+				})
+			}
+		}
+
+		for property, propertyValue := range value {
+			if propertySchema, ok := s.Properties[property]; ok {
+				violations = append(violations, propertySchema.validate(propertyValue, joinPath(path, property))...)
+			}
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range value {
+				violations = append(violations, s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	}
+
+	return violations
+}
+
+// validateOneOf requires the value to match exactly one alternative.
+func (s *Schema) validateOneOf(value interface{}, path string) []Violation {
+	matches := 0
+	for _, alternative := range s.OneOf {
+		if len(alternative.validate(value, path)) == 0 {
+			matches++
+		}
+	}
+
+	if matches == 1 {
+		return nil
+	}
+
+	return []Violation{{
+		Path:    path,
+		Message: fmt.Sprintf("value matches %d of the oneOf alternatives instead of exactly one", matches),
+	}}
+}
+
+// validateAnyOf requires the value to match at least one alternative.
+func (s *Schema) validateAnyOf(value interface{}, path string) []Violation {
+	for _, alternative := range s.AnyOf {
+		if len(alternative.validate(value, path)) == 0 {
+			return nil
+		}
+	}
+
+	return []Violation{{
+		Path:    path,
+		Message: "value matches none of the anyOf alternatives",
+	}}
+}
+
+// allowsType reports whether a value of the given JSON type is allowed. A
+// schema without any type allows every value.
+func (s *Schema) allowsType(valueType string) bool {
+	if len(s.Type) == 0 {
+		return true
+	}
+
+	for _, schemaType := range s.Type {
+		if schemaType == valueType {
+			return true
+		}
+
+		if schemaType == gojsonschema.TYPE_NUMBER && valueType == gojsonschema.TYPE_INTEGER {
+			return true
+		}
+	}
+
+	return false
+}
+
+// jsonType returns the JSON schema type of a decoded JSON value. Whole numbers
+// are reported as integers.
+func jsonType(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return gojsonschema.TYPE_NULL
+	case bool:
+		return gojsonschema.TYPE_BOOLEAN
+	case string:
+		return gojsonschema.TYPE_STRING
+	case float64:
+		if value == math.Trunc(value) {
+			return gojsonschema.TYPE_INTEGER
+		}
+		return gojsonschema.TYPE_NUMBER
+	case json.Number:
+		if _, err := value.Int64(); err == nil {
+			return gojsonschema.TYPE_INTEGER
+		}
+		return gojsonschema.TYPE_NUMBER
+	case []interface{}:
+		return gojsonschema.TYPE_ARRAY
+	default:
+		return gojsonschema.TYPE_OBJECT
+	}
+}
+
+func joinPath(path string, property string) string {
+	if path == "" {
+		return property
+	}
+
+	return path + "." + property
+}
+
+// allowsValue reports whether the value is one of the enumerated values.
+func (s *Schema) allowsValue(value interface{}) bool {
+	for _, allowed := range s.Enum {
+		if reflect.DeepEqual(allowed, value) {
+			return true
+		}
+	}
+
+	return false
+}
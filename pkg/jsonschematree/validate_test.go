@@ -0,0 +1,105 @@
+package jsonschematree
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestValidateOneOfAndAnyOf(t *testing.T) {
+	// The alternatives overlap: short strings match both of them
+	alternatives := `[{"type": "string"}, {"type": "string", "maxLength": 5}]`
+
+	var oneOf, anyOf Schema
+	if err := json.Unmarshal([]byte(`{"oneOf": `+alternatives+`}`), &oneOf); err != nil {
+		t.Fatalf("failed to unmarshal oneOf schema: %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"anyOf": `+alternatives+`}`), &anyOf); err != nil {
+		t.Fatalf("failed to unmarshal anyOf schema: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		value     interface{}
+		wantOneOf bool
+		wantAnyOf bool
+	}{
+		{name: "matches both alternatives", value: "abc", wantOneOf: false, wantAnyOf: true},
+		{name: "matches one alternative", value: "abcdefgh", wantOneOf: true, wantAnyOf: true},
+		{name: "matches no alternative", value: 42.0, wantOneOf: false, wantAnyOf: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := len(oneOf.Validate(tt.value)) == 0; got != tt.wantOneOf {
+				t.Errorf("oneOf Validate(%v) valid = %v, want %v: %v", tt.value, got, tt.wantOneOf, oneOf.Validate(tt.value))
+			}
+			if got := len(anyOf.Validate(tt.value)) == 0; got != tt.wantAnyOf {
+				t.Errorf("anyOf Validate(%v) valid = %v, want %v: %v", tt.value, got, tt.wantAnyOf, anyOf.Validate(tt.value))
+			}
+		})
+	}
+}
+
+func TestValidateComposition(t *testing.T) {
+	tests := []struct {
+		name      string
+		schema    string
+		value     interface{}
+		wantPaths []string
+	}{
+		{
+			name:   "allOf members hold",
+			schema: `{"allOf": [{"type": "object", "required": ["companyName"]}, {"properties": {"email": {"type": "string", "maxLength": 10}}}]}`,
+			value:  map[string]interface{}{"companyName": "Acme", "email": "a@b.co"},
+		},
+		{
+			name:      "allOf members violated",
+			schema:    `{"allOf": [{"type": "object", "required": ["companyName"]}, {"properties": {"email": {"type": "string", "maxLength": 10}}}]}`,
+			value:     map[string]interface{}{"email": "someone@example.com"},
+			wantPaths: []string{"companyName", "email"},
+		},
+		{
+			name:      "oneOf with own required",
+			schema:    `{"type": "object", "required": ["companyName"], "oneOf": [{"required": ["email"]}, {"required": ["phone"]}]}`,
+			value:     map[string]interface{}{"email": "a@b.co"},
+			wantPaths: []string{"companyName"},
+		},
+		{
+			name:      "anyOf with own property",
+			schema:    `{"type": "object", "properties": {"email": {"type": "string"}}, "anyOf": [{"required": ["email"]}, {"required": ["phone"]}]}`,
+			value:     map[string]interface{}{"email": 42.0},
+			wantPaths: []string{"email"},
+		},
+		{
+			name:      "anyOf with own type",
+			schema:    `{"type": "string", "anyOf": [{"maxLength": 3}, {"maxLength": 5}]}`,
+			value:     42.0,
+			wantPaths: []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schema Schema
+			if err := json.Unmarshal([]byte(tt.schema), &schema); err != nil {
+				t.Fatalf("failed to unmarshal schema: %v", err)
+			}
+
+			violations := schema.Validate(tt.value)
+			paths := make([]string, 0, len(violations))
+			for _, violation := range violations {
+				if len(paths) == 0 || paths[len(paths)-1] != violation.Path {
+					paths = append(paths, violation.Path)
+				}
+			}
+			if len(paths) != len(tt.wantPaths) {
+				t.Fatalf("Validate() = %v, want violations at %v", violations, tt.wantPaths)
+			}
+			for i := range paths {
+				if paths[i] != tt.wantPaths[i] {
+					t.Errorf("Validate() = %v, want violations at %v", violations, tt.wantPaths)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/glints-dev/mcp-netsuite/pkg/netsuite"
+)
+
+// columnAliases returns the aliases configured for the FROM table of the
+// query, keyed by lower case column name, or nil if there are none. Record
+// types and column names are matched case-insensitively, since SuiteQL
+// returns column names in lower case.
+func columnAliases(config Config, query string) map[string]string {
+	table := netsuite.SourceTable(query)
+	if table == "" {
+		return nil
+	}
+
+	for recordType, aliases := range config.ColumnAliases {
+		if !strings.EqualFold(recordType, table) {
+			continue
+		}
+
+		byColumn := make(map[string]string, len(aliases))
+		for column, alias := range aliases {
+			byColumn[strings.ToLower(column)] = alias
+		}
+		return byColumn
+	}
+
+	return nil
+}
+
+// aliasColumns renames the columns of every result row that have an alias,
+// leaving the others untouched. It also returns the aliases that were not
+// applied because they collide with another column, keyed by column.
+func aliasColumns(items []json.RawMessage, aliases map[string]string) ([]json.RawMessage, map[string]string, error) {
+	if len(aliases) == 0 {
+		return items, nil, nil
+	}
+
+	aliased := make([]json.RawMessage, 0, len(items))
+	collisions := make(map[string]string)
+	for _, item := range items {
+		var row map[string]json.RawMessage
+		if err := json.Unmarshal(item, &row); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+		}
+
+		itemJSON, err := json.Marshal(aliasRow(row, aliases, collisions))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+
+		aliased = append(aliased, itemJSON)
+	}
+
+	return aliased, collisions, nil
+}
+
+// aliasRow returns the row with the columns that have an alias renamed. An
+// alias naming another column of the row, or the alias of a column earlier
+// in name order, would overwrite its value, so such columns keep their names
+// and are recorded in collisions with their alias.
+func aliasRow[V any](row map[string]V, aliases map[string]string, collisions map[string]string) map[string]V {
+	if len(aliases) == 0 {
+		return row
+	}
+
+	renamed := make(map[string]V, len(row))
+	var aliased []string
+	for _, column := range sortedKeys(row) {
+		if _, ok := aliases[strings.ToLower(column)]; ok {
+			aliased = append(aliased, column)
+			continue
+		}
+		renamed[column] = row[column]
+	}
+
+	for _, column := range aliased {
+		alias := aliases[strings.ToLower(column)]
+		_, taken := renamed[alias]
+		if _, other := row[alias]; (other && alias != column) || taken {
+			collisions[column] = alias
+			alias = column
+		}
+		renamed[alias] = row[column]
+	}
+
+	return renamed
+}
+
+// aliasedName returns the name aliasRow gives a column: its alias, unless the
+// alias collided with another column.
+func aliasedName(column string, aliases map[string]string, collisions map[string]string) string {
+	alias, ok := aliases[strings.ToLower(column)]
+	if !ok {
+		return column
+	}
+	if _, collided := collisions[column]; collided {
+		return column
+	}
+
+	return alias
+}
+
+// aliasCollisionWarnings describes the aliases aliasRow did not apply.
+func aliasCollisionWarnings(collisions map[string]string) []string {
+	warnings := make([]string, 0, len(collisions))
+	for _, column := range sortedKeys(collisions) {
+		warnings = append(warnings, fmt.Sprintf("Column '%s' was not renamed to its alias '%s', since that name is already taken by another column", column, collisions[column]))
+	}
+
+	return warnings
+}
@@ -0,0 +1,152 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestAliasRow(t *testing.T) {
+	tests := []struct {
+		name           string
+		row            map[string]interface{}
+		aliases        map[string]string
+		want           map[string]interface{}
+		wantCollisions map[string]string
+	}{
+		{
+			name:           "no aliases",
+			row:            map[string]interface{}{"id": "1", "companyname": "Acme"},
+			want:           map[string]interface{}{"id": "1", "companyname": "Acme"},
+			wantCollisions: map[string]string{},
+		},
+		{
+			name:           "renamed",
+			row:            map[string]interface{}{"id": "1", "custentity_tier": "Gold"},
+			aliases:        map[string]string{"custentity_tier": "tier"},
+			want:           map[string]interface{}{"id": "1", "tier": "Gold"},
+			wantCollisions: map[string]string{},
+		},
+		{
+			name:           "alias names another column",
+			row:            map[string]interface{}{"id": "1", "custentity_tier": "Gold", "tier": "2"},
+			aliases:        map[string]string{"custentity_tier": "tier"},
+			want:           map[string]interface{}{"id": "1", "custentity_tier": "Gold", "tier": "2"},
+			wantCollisions: map[string]string{"custentity_tier": "tier"},
+		},
+		{
+			name:           "two columns with the same alias",
+			row:            map[string]interface{}{"custentity_a": "A", "custentity_b": "B"},
+			aliases:        map[string]string{"custentity_a": "tier", "custentity_b": "tier"},
+			want:           map[string]interface{}{"tier": "A", "custentity_b": "B"},
+			wantCollisions: map[string]string{"custentity_b": "tier"},
+		},
+		{
+			name:           "swapped names",
+			row:            map[string]interface{}{"a": "1", "b": "2"},
+			aliases:        map[string]string{"a": "b", "b": "a"},
+			want:           map[string]interface{}{"a": "1", "b": "2"},
+			wantCollisions: map[string]string{"a": "b", "b": "a"},
+		},
+		{
+			name:           "alias equal to column",
+			row:            map[string]interface{}{"id": "1"},
+			aliases:        map[string]string{"id": "id"},
+			want:           map[string]interface{}{"id": "1"},
+			wantCollisions: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collisions := make(map[string]string)
+			got := aliasRow(tt.row, tt.aliases, collisions)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("aliasRow() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(collisions, tt.wantCollisions) {
+				t.Errorf("collisions = %v, want %v", collisions, tt.wantCollisions)
+			}
+		})
+	}
+}
+
+func TestAliasColumns(t *testing.T) {
+	tests := []struct {
+		name           string
+		items          []string
+		aliases        map[string]string
+		want           []string
+		wantCollisions map[string]string
+	}{
+		{
+			name:    "no aliases",
+			items:   []string{`{"id":"1"}`},
+			aliases: nil,
+			want:    []string{`{"id":"1"}`},
+		},
+		{
+			name:           "collision in some rows",
+			items:          []string{`{"custentity_tier":"Gold","id":"1"}`, `{"custentity_tier":"Gold","id":"2","tier":"2"}`},
+			aliases:        map[string]string{"custentity_tier": "tier"},
+			want:           []string{`{"id":"1","tier":"Gold"}`, `{"custentity_tier":"Gold","id":"2","tier":"2"}`},
+			wantCollisions: map[string]string{"custentity_tier": "tier"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := make([]json.RawMessage, len(tt.items))
+			for i, item := range tt.items {
+				items[i] = json.RawMessage(item)
+			}
+
+			got, collisions, err := aliasColumns(items, tt.aliases)
+			if err != nil {
+				t.Fatalf("aliasColumns() error = %v", err)
+			}
+			for i, item := range got {
+				if string(item) != tt.want[i] {
+					t.Errorf("aliasColumns()[%d] = %s, want %s", i, item, tt.want[i])
+				}
+			}
+			if len(collisions) != len(tt.wantCollisions) || (len(tt.wantCollisions) > 0 && !reflect.DeepEqual(collisions, tt.wantCollisions)) {
+				t.Errorf("collisions = %v, want %v", collisions, tt.wantCollisions)
+			}
+		})
+	}
+}
+
+func TestAliasCollisionWarnings(t *testing.T) {
+	got := aliasCollisionWarnings(map[string]string{"custentity_b": "tier", "custentity_a": "id"})
+	want := []string{
+		"Column 'custentity_a' was not renamed to its alias 'id', since that name is already taken by another column",
+		"Column 'custentity_b' was not renamed to its alias 'tier', since that name is already taken by another column",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("aliasCollisionWarnings() = %v, want %v", got, want)
+	}
+}
+
+func TestAliasedName(t *testing.T) {
+	aliases := map[string]string{"entityid": "customer_number", "custentity_tier": "tier"}
+	collisions := map[string]string{"custentity_tier": "tier"}
+
+	tests := []struct {
+		column string
+		want   string
+	}{
+		{column: "entityid", want: "customer_number"},
+		{column: "EntityId", want: "customer_number"},
+		{column: "custentity_tier", want: "custentity_tier"},
+		{column: "id", want: "id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.column, func(t *testing.T) {
+			if got := aliasedName(tt.column, aliases, collisions); got != tt.want {
+				t.Errorf("aliasedName(%q) = %q, want %q", tt.column, got, tt.want)
+			}
+		})
+	}
+}
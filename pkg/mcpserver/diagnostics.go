@@ -0,0 +1,79 @@
+package mcpserver
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// bytesPerToken is the rough number of bytes of JSON per model token, used to
+// estimate the tokens a response costs.
+const bytesPerToken = 4
+
+// ResponseSize is the size of a tool response, for agents budgeting their
+// context to decide whether to summarize or paginate further.
+type ResponseSize struct {
+	Bytes           int `json:"bytes"`
+	EstimatedTokens int `json:"estimated_tokens"`
+}
+
+// newResponseSize estimates the size of a response of the given length.
+func newResponseSize(length int) ResponseSize {
+	return ResponseSize{
+		Bytes:           length,
+		EstimatedTokens: (length + bytesPerToken - 1) / bytesPerToken,
+	}
+}
+
+// takeDiagnostics removes the diagnostics a handler put in a response before
+// it is marshalled, so that addDiagnostics can add them back along with the
+// size of the response.
+func takeDiagnostics(response map[string]interface{}) map[string]interface{} {
+	diagnostics, _ := response["diagnostics"].(map[string]interface{})
+	delete(response, "diagnostics")
+
+	if diagnostics == nil {
+		diagnostics = make(map[string]interface{})
+	}
+
+	return diagnostics
+}
+
+// addDiagnostics records the size of a marshalled object response in its
+// "diagnostics" field, next to the diagnostics taken from it by
+// takeDiagnostics, both in the response map and at the end of its JSON. The
+// size is that of the JSON before the field was added.
+func addDiagnostics(response map[string]interface{}, diagnostics map[string]interface{}, responseJSON []byte, pretty bool) []byte {
+	diagnostics["response_size"] = newResponseSize(len(responseJSON))
+	response["diagnostics"] = diagnostics
+
+	var diagnosticsJSON []byte
+	var err error
+	if pretty {
+		diagnosticsJSON, err = json.MarshalIndent(diagnostics, "  ", "  ")
+	} else {
+		diagnosticsJSON, err = json.Marshal(diagnostics)
+	}
+	if err != nil {
+		return responseJSON
+	}
+
+	end := bytes.LastIndexByte(responseJSON, '}')
+	if end < 0 {
+		return responseJSON
+	}
+
+	var buffer bytes.Buffer
+	buffer.Grow(len(responseJSON) + len(diagnosticsJSON) + 24)
+	buffer.Write(bytes.TrimRight(responseJSON[:end], " \n"))
+	if pretty {
+		buffer.WriteString(",\n  \"diagnostics\": ")
+		buffer.Write(diagnosticsJSON)
+		buffer.WriteString("\n}")
+	} else {
+		buffer.WriteString(`,"diagnostics":`)
+		buffer.Write(diagnosticsJSON)
+		buffer.WriteString("}")
+	}
+
+	return buffer.Bytes()
+}
@@ -0,0 +1,203 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/glints-dev/mcp-netsuite/pkg/netsuite"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// keyFieldNames are the fields most often selected or filtered on, in the
+// order they are listed when a record type has them. Names are compared
+// case-insensitively.
+var keyFieldNames = []string{
+	"id", "externalId", "tranId", "entityId", "companyName", "name", "title",
+	"status", "entityStatus", "tranDate", "entity", "subsidiary", "department",
+	"location", "currency", "total", "amount", "email", "isInactive",
+	"dateCreated", "createdDate", "lastModifiedDate",
+}
+
+// maxExplainedFields is the number of fields listed at most in each part of
+// an explanation.
+const maxExplainedFields = 15
+
+// explainedField is a field of a record type worth pointing out.
+type explainedField struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Label       string `json:"label,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// explainedRelationship is a record type a record type refers to.
+type explainedRelationship struct {
+	RecordType string   `json:"record_type"`
+	Fields     []string `json:"fields"`
+}
+
+// handleExplainRecord handles the netsuite_explain_record tool request
+func handleExplainRecord(ctx context.Context, client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get record type from arguments
+	recordType, err := request.RequireString("record_type")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid record_type parameter: %v", err)), nil
+	}
+
+	// Get metadata from NetSuite
+	metadata, err := client.Metadata(recordType, nil)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get metadata for record type '%s': %v", recordType, err)), nil
+	}
+	if metadata == nil {
+		return mcp.NewToolResultError(fmt.Sprintf("No metadata found for record type '%s'", recordType)), nil
+	}
+
+	var warnings []string
+	fieldNames := metadata.PropertyNames()
+
+	customFields, err := client.LabelCustomFields(ctx, fieldNames)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("Unable to label custom fields: %v", err))
+	}
+
+	describe := func(name string) explainedField {
+		schema := metadata.Properties[name]
+		field := explainedField{
+			Name:        name,
+			Type:        schema.BaseType(),
+			Label:       customFields[name].Label,
+			Description: schema.Description,
+		}
+		if field.Description == "" {
+			field.Description = schema.Title
+		}
+		if schema.IsReference() {
+			field.Type = "reference"
+		}
+		return field
+	}
+
+	// Key fields come first in a fixed order, so that the explanation of
+	// similar record types reads alike
+	byLowerName := make(map[string]string, len(fieldNames))
+	for _, name := range fieldNames {
+		byLowerName[strings.ToLower(name)] = name
+	}
+	keyFields := []explainedField{}
+	for _, keyName := range keyFieldNames {
+		if name, ok := byLowerName[strings.ToLower(keyName)]; ok {
+			keyFields = append(keyFields, describe(name))
+		}
+	}
+
+	requiredFields := []explainedField{}
+	for _, name := range metadata.Required {
+		if _, ok := metadata.Properties[name]; ok {
+			requiredFields = append(requiredFields, describe(name))
+		}
+	}
+	sort.Slice(requiredFields, func(i, j int) bool {
+		return requiredFields[i].Name < requiredFields[j].Name
+	})
+
+	customFieldList := []explainedField{}
+	customCount := 0
+	for _, name := range fieldNames {
+		if !netsuite.IsCustomField(name) {
+			continue
+		}
+		customCount++
+		if len(customFieldList) < maxExplainedFields {
+			customFieldList = append(customFieldList, describe(name))
+		}
+	}
+
+	// Top-level references either point at other record types, or at the
+	// sublists and subrecords of this one, which are named after it
+	relationships, sublists, subrecords, err := explainReferences(client, recordType)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get references of record type '%s': %v", recordType, err)), nil
+	}
+
+	summary := fmt.Sprintf("Record type '%s' has %d fields, %d of them required and %d custom.", recordType, len(fieldNames), len(requiredFields), customCount)
+	if len(relationships) > 0 {
+		targets := make([]string, 0, len(relationships))
+		for _, relationship := range relationships[:min(len(relationships), 5)] {
+			targets = append(targets, fmt.Sprintf("%s (via %s)", relationship.RecordType, strings.Join(relationship.Fields, ", ")))
+		}
+		summary += fmt.Sprintf(" It refers to %d other record types, such as %s.", len(relationships), strings.Join(targets, "; "))
+	}
+	if len(sublists) > 0 {
+		summary += fmt.Sprintf(" Its sublists are %s.", strings.Join(sublists, ", "))
+	}
+
+	if len(requiredFields) > maxExplainedFields {
+		warnings = append(warnings, fmt.Sprintf("Only the first %d of %d required fields are listed", maxExplainedFields, len(requiredFields)))
+		requiredFields = requiredFields[:maxExplainedFields]
+	}
+	if customCount > maxExplainedFields {
+		warnings = append(warnings, fmt.Sprintf("Only the first %d of %d custom fields are listed; see netsuite_describe_custom_fields", maxExplainedFields, customCount))
+	}
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"record_type":     recordType,
+		"summary":         summary,
+		"total_fields":    len(fieldNames),
+		"key_fields":      keyFields,
+		"required_fields": requiredFields,
+		"custom_fields":   customFieldList,
+		"relationships":   relationships,
+		"sublists":        sublists,
+		"subrecords":      subrecords,
+		"warnings":        warnings,
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// explainReferences sorts the top-level references of a record type into the
+// other record types it refers to, ordered by the number of fields referring
+// to them, and the names of its sublists and subrecords.
+func explainReferences(client *netsuite.Client, recordType string) ([]explainedRelationship, []string, []string, error) {
+	references, err := client.References(recordType)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	ownPrefix := strings.ToLower(recordType) + "-"
+
+	sublists := []string{}
+	subrecords := []string{}
+	fieldsByTarget := make(map[string][]string)
+	for _, reference := range references {
+		if strings.ContainsAny(reference.Path, ".[") {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(strings.ToLower(reference.Target), ownPrefix) && strings.HasSuffix(reference.Target, "Collection"):
+			sublists = append(sublists, reference.Path)
+		case strings.HasPrefix(strings.ToLower(reference.Target), ownPrefix):
+			subrecords = append(subrecords, reference.Path)
+		default:
+			fieldsByTarget[reference.Target] = append(fieldsByTarget[reference.Target], reference.Path)
+		}
+	}
+
+	relationships := make([]explainedRelationship, 0, len(fieldsByTarget))
+	for target, fields := range fieldsByTarget {
+		relationships = append(relationships, explainedRelationship{RecordType: target, Fields: fields})
+	}
+	sort.Slice(relationships, func(i, j int) bool {
+		if len(relationships[i].Fields) != len(relationships[j].Fields) {
+			return len(relationships[i].Fields) > len(relationships[j].Fields)
+		}
+		return relationships[i].RecordType < relationships[j].RecordType
+	})
+
+	return relationships, sublists, subrecords, nil
+}
@@ -0,0 +1,326 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/glints-dev/mcp-netsuite/pkg/netsuite"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// formatCSV is the comma-separated output format, supported by exports only.
+const formatCSV = "csv"
+
+// handleExportSuiteQL handles the netsuite_export_suiteql tool request
+func handleExportSuiteQL(ctx context.Context, client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get query, path, and format from arguments
+	query, err := request.RequireString("query")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid query parameter: %v", err)), nil
+	}
+	if result := rejectSelectStar(client, config, query); result != nil {
+		return result, nil
+	}
+
+	path, err := request.RequireString("path")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid path parameter: %v", err)), nil
+	}
+
+	format := request.GetString("format", formatNDJSON)
+	if format != formatCSV && format != formatNDJSON {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid format '%s': expected 'csv' or 'ndjson'", format)), nil
+	}
+
+	exportPath, err := resolveExportPath(config.ExportDir, path)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// The file must not exist yet, so that neither an earlier export nor
+	// the target of a link placed in the directory is overwritten
+	file, err := os.OpenFile(exportPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to create export file: %v", err)), nil
+	}
+	defer file.Close()
+
+	maxFieldLength, warnings := clampParameter(nil, "max_field_length", request.GetInt("max_field_length", 0), 0, math.MaxInt)
+
+	counter := &countingWriter{w: file}
+	collisions := make(map[string]string)
+	rowCount, truncatedCount, droppedColumns, err := exportRows(ctx, client, query, format, columnAliases(config, query), collisions, maxFieldLength, counter)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to export SuiteQL query after %d rows, which were kept in %s: %v", rowCount, exportPath, err)), nil
+	}
+
+	if err := file.Close(); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to close export file: %v", err)), nil
+	}
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"path":         exportPath,
+		"format":       format,
+		"rowCount":     rowCount,
+		"bytesWritten": counter.n,
+	}
+
+	if truncatedCount > 0 {
+		warnings = append(warnings, truncationWarning(truncatedCount, maxFieldLength))
+	}
+	warnings = append(warnings, aliasCollisionWarnings(collisions)...)
+	if len(droppedColumns) > 0 {
+		warnings = append(warnings, fmt.Sprintf(
+			"Columns %s first appeared after the first %d rows and are missing from the CSV; export as ndjson to keep them",
+			strings.Join(droppedColumns, ", "),
+			csvHeaderSampleSize,
+		))
+	}
+	if len(warnings) > 0 {
+		response["warnings"] = warnings
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// resolveExportPath resolves the path against the export directory and
+// returns an error if it would land outside of it, or if the file already
+// exists. A symlink at the path itself is refused as well, since writing to
+// it would write to its target.
+func resolveExportPath(exportDir string, path string) (string, error) {
+	if exportDir == "" {
+		return "", fmt.Errorf("exports are disabled; set NETSUITE_EXPORT_DIR to allow them")
+	}
+
+	dir, err := filepath.Abs(exportDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve export directory: %w", err)
+	}
+
+	// Symlinks are resolved so that a link inside the directory cannot be
+	// used to write elsewhere
+	dir, err = filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve export directory: %w", err)
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+
+	parent, err := filepath.EvalSymlinks(filepath.Dir(filepath.Clean(path)))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve export path: %w", err)
+	}
+	resolved := filepath.Join(parent, filepath.Base(path))
+
+	relative, err := filepath.Rel(dir, resolved)
+	if err != nil || relative == "." || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("export path %s is outside of the export directory %s", path, dir)
+	}
+
+	if info, err := os.Lstat(resolved); err == nil {
+		if info.Mode()&os.ModeSymlink != 0 {
+			return "", fmt.Errorf("export path %s is a symbolic link", path)
+		}
+		return "", fmt.Errorf("export path %s already exists; choose another path", path)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("failed to check export path: %w", err)
+	}
+
+	return resolved, nil
+}
+
+// csvHeaderSampleSize is the number of rows buffered to derive the header of
+// a CSV export from, since the rows are streamed to the file.
+const csvHeaderSampleSize = 1000
+
+// exportRows writes every row of the query to w in the given format and
+// returns the number of rows written and of values truncated to
+// maxFieldLength. Columns with an alias are renamed, except where the alias
+// collides with another column, which is recorded in collisions. For CSV, it
+// also returns the columns that first appeared after the header was written,
+// whose values could not be exported.
+func exportRows(ctx context.Context, client *netsuite.Client, query string, format string, aliases map[string]string, collisions map[string]string, maxFieldLength int, w io.Writer) (int, int, []string, error) {
+	truncatedCount := 0
+
+	if format != formatCSV {
+		encoder := json.NewEncoder(w)
+
+		rowCount := 0
+		for row, err := range client.SuiteQLSeq(ctx, query, 0) {
+			if err != nil {
+				return rowCount, truncatedCount, nil, err
+			}
+
+			row = aliasRow(row, aliases, collisions)
+			truncatedCount += truncateRow(row, maxFieldLength)
+			if err := encoder.Encode(row); err != nil {
+				return rowCount, truncatedCount, nil, fmt.Errorf("failed to write row: %w", err)
+			}
+			rowCount++
+		}
+
+		return rowCount, truncatedCount, nil, nil
+	}
+
+	exporter := &csvExporter{writer: csv.NewWriter(w)}
+	for row, err := range client.SuiteQLSeq(ctx, query, 0) {
+		if err != nil {
+			// Keep the rows exported so far, e.g. when the deadline passed
+			if flushErr := exporter.flush(); flushErr != nil {
+				return exporter.rowCount, truncatedCount, exporter.droppedColumns(), flushErr
+			}
+			return exporter.rowCount, truncatedCount, exporter.droppedColumns(), err
+		}
+
+		row = aliasRow(row, aliases, collisions)
+		truncatedCount += truncateRow(row, maxFieldLength)
+		if err := exporter.add(row); err != nil {
+			return exporter.rowCount, truncatedCount, exporter.droppedColumns(), err
+		}
+	}
+
+	err := exporter.flush()
+
+	return exporter.rowCount, truncatedCount, exporter.droppedColumns(), err
+}
+
+// csvExporter writes rows as CSV. The header is the union of the columns of
+// the first csvHeaderSampleSize rows, which are buffered until then.
+type csvExporter struct {
+	writer   *csv.Writer
+	sample   []map[string]interface{}
+	header   []string
+	columns  map[string]struct{}
+	rowCount int
+	dropped  map[string]struct{}
+}
+
+func (exporter *csvExporter) add(row map[string]interface{}) error {
+	if exporter.header == nil {
+		exporter.sample = append(exporter.sample, row)
+		if len(exporter.sample) < csvHeaderSampleSize {
+			return nil
+		}
+
+		return exporter.writeSample()
+	}
+
+	return exporter.write(row)
+}
+
+func (exporter *csvExporter) writeSample() error {
+	exporter.header = tableHeader(exporter.sample)
+	exporter.columns = make(map[string]struct{}, len(exporter.header))
+	for _, column := range exporter.header {
+		exporter.columns[column] = struct{}{}
+	}
+	exporter.dropped = make(map[string]struct{})
+	if err := exporter.writer.Write(exporter.header); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+
+	for _, row := range exporter.sample {
+		if err := exporter.write(row); err != nil {
+			return err
+		}
+	}
+	exporter.sample = nil
+
+	return nil
+}
+
+func (exporter *csvExporter) write(row map[string]interface{}) error {
+	record := make([]string, len(exporter.header))
+	for i, column := range exporter.header {
+		record[i] = csvValue(row[column])
+	}
+
+	for column := range row {
+		if _, ok := exporter.columns[column]; !ok && column != "links" {
+			exporter.dropped[column] = struct{}{}
+		}
+	}
+
+	if err := exporter.writer.Write(record); err != nil {
+		return fmt.Errorf("failed to write row: %w", err)
+	}
+	exporter.rowCount++
+
+	return nil
+}
+
+// flush writes out any buffered rows.
+func (exporter *csvExporter) flush() error {
+	if exporter.header == nil && len(exporter.sample) > 0 {
+		if err := exporter.writeSample(); err != nil {
+			return err
+		}
+	}
+
+	exporter.writer.Flush()
+	if err := exporter.writer.Error(); err != nil {
+		return fmt.Errorf("failed to write rows: %w", err)
+	}
+
+	return nil
+}
+
+// droppedColumns returns the columns that were not part of the header, sorted.
+func (exporter *csvExporter) droppedColumns() []string {
+	var columns []string
+	for column := range exporter.dropped {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	return columns
+}
+
+// csvValue formats a column value as a CSV field. Nested values are written
+// as JSON.
+func csvValue(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	case bool:
+		if value {
+			return "true"
+		}
+		return "false"
+	default:
+		valueJSON, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprint(value)
+		}
+		return string(valueJSON)
+	}
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (writer *countingWriter) Write(p []byte) (int, error) {
+	n, err := writer.w.Write(p)
+	writer.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,67 @@
+package mcpserver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveExportPath(t *testing.T) {
+	outside := t.TempDir()
+	target := filepath.Join(outside, "authorized_keys")
+	if err := os.WriteFile(target, []byte("ssh-ed25519 AAAA"), 0o600); err != nil {
+		t.Fatalf("failed to write target: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "existing.csv"), []byte("id\n1\n"), 0o644); err != nil {
+		t.Fatalf("failed to write existing export: %v", err)
+	}
+	if err := os.Symlink(target, filepath.Join(dir, "out.csv")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(dir, "existing.csv"), filepath.Join(dir, "inside.csv")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	if err := os.Symlink(outside, filepath.Join(dir, "linked")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	resolvedDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatalf("failed to resolve directory: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		exportDir string
+		path      string
+		want      string
+		wantErr   bool
+	}{
+		{name: "new file", exportDir: dir, path: "customers.csv", want: filepath.Join(resolvedDir, "customers.csv")},
+		{name: "exports disabled", path: "customers.csv", wantErr: true},
+		{name: "parent directory", exportDir: dir, path: "../customers.csv", wantErr: true},
+		{name: "existing file", exportDir: dir, path: "existing.csv", wantErr: true},
+		{name: "symlink pointing outside", exportDir: dir, path: "out.csv", wantErr: true},
+		{name: "symlink pointing inside", exportDir: dir, path: "inside.csv", wantErr: true},
+		{name: "symlinked directory pointing outside", exportDir: dir, path: "linked/customers.csv", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveExportPath(tt.exportDir, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveExportPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveExportPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	content, err := os.ReadFile(target)
+	if err != nil || string(content) != "ssh-ed25519 AAAA" {
+		t.Errorf("symlink target = %q, %v, want it untouched", content, err)
+	}
+}
@@ -0,0 +1,163 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Field name conventions supported by the field_case tool parameter
+const (
+	fieldCaseRaw   = "raw"
+	fieldCaseSnake = "snake_case"
+	fieldCaseCamel = "camelCase"
+)
+
+// transformFieldNames rewrites the keys of every result row according to the
+// convention, leaving the values untouched. Distinct keys that the convention
+// maps to the same name, such as "foo_bar" and "fooBar" in snake_case, are an
+// error rather than one of the values being lost.
+func transformFieldNames(items []json.RawMessage, convention string) ([]json.RawMessage, error) {
+	if convention == "" || convention == fieldCaseRaw {
+		return items, nil
+	}
+
+	transform, err := fieldCaseTransform(convention)
+	if err != nil {
+		return nil, err
+	}
+
+	transformed := make([]json.RawMessage, 0, len(items))
+	for _, item := range items {
+		var row map[string]json.RawMessage
+		if err := json.Unmarshal(item, &row); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+		}
+
+		renamed := make(map[string]json.RawMessage, len(row))
+		originals := make(map[string]string, len(row))
+		for _, key := range sortedKeys(row) {
+			name := transform(key)
+			if original, ok := originals[name]; ok {
+				return nil, fmt.Errorf("%s maps both \"%s\" and \"%s\" to \"%s\"", convention, original, key, name)
+			}
+
+			originals[name] = key
+			renamed[name] = row[key]
+		}
+
+		itemJSON, err := json.Marshal(renamed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+
+		transformed = append(transformed, itemJSON)
+	}
+
+	return transformed, nil
+}
+
+// fieldCaseTransform returns the function renaming a field name according to
+// the convention, which leaves raw names untouched.
+func fieldCaseTransform(convention string) (func(string) string, error) {
+	switch convention {
+	case "", fieldCaseRaw:
+		return func(name string) string { return name }, nil
+	case fieldCaseSnake:
+		return toSnakeCase, nil
+	case fieldCaseCamel:
+		return toCamelCase, nil
+	default:
+		return nil, fmt.Errorf("unknown field case \"%s\"", convention)
+	}
+}
+
+// splitWords splits a field name into words on underscores, hyphens, spaces,
+// and lower-to-upper case transitions.
+func splitWords(name string) []string {
+	var words []string
+	var current []rune
+
+	runes := []rune(name)
+	for i, r := range runes {
+		if r == '_' || r == '-' || unicode.IsSpace(r) {
+			if len(current) > 0 {
+				words = append(words, string(current))
+				current = nil
+			}
+			continue
+		}
+
+		if unicode.IsUpper(r) && i > 0 && unicode.IsLower(runes[i-1]) && len(current) > 0 {
+			words = append(words, string(current))
+			current = nil
+		}
+
+		current = append(current, r)
+	}
+
+	if len(current) > 0 {
+		words = append(words, string(current))
+	}
+
+	return words
+}
+
+// toSnakeCase converts a field name such as "entityStatus" to "entity_status"
+func toSnakeCase(name string) string {
+	words := splitWords(name)
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+
+	return strings.Join(words, "_")
+}
+
+// toCamelCase converts a field name such as "custbody_due_date" to
+// "custbodyDueDate"
+func toCamelCase(name string) string {
+	words := splitWords(name)
+	for i, word := range words {
+		word = strings.ToLower(word)
+		if i > 0 {
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+		words[i] = word
+	}
+
+	return strings.Join(words, "")
+}
+
+// omitNulls drops the fields of every result row whose value is null. Nulls
+// nested in objects or arrays are kept, since they are part of the value.
+func omitNulls(items []json.RawMessage) ([]json.RawMessage, error) {
+	omitted := make([]json.RawMessage, 0, len(items))
+	for _, item := range items {
+		var row map[string]json.RawMessage
+		if err := json.Unmarshal(item, &row); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+		}
+
+		nullCount := 0
+		for key, value := range row {
+			if string(value) == "null" {
+				delete(row, key)
+				nullCount++
+			}
+		}
+		if nullCount == 0 {
+			omitted = append(omitted, item)
+			continue
+		}
+
+		itemJSON, err := json.Marshal(row)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+
+		omitted = append(omitted, itemJSON)
+	}
+
+	return omitted, nil
+}
@@ -0,0 +1,40 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTransformFieldNames(t *testing.T) {
+	tests := []struct {
+		name       string
+		row        string
+		convention string
+		want       string
+		wantErr    bool
+	}{
+		{name: "raw", row: `{"custbody_due_date":1}`, convention: fieldCaseRaw, want: `{"custbody_due_date":1}`},
+		{name: "camel case", row: `{"custbody_due_date":1,"id":"2"}`, convention: fieldCaseCamel, want: `{"custbodyDueDate":1,"id":"2"}`},
+		{name: "snake case", row: `{"entityStatus":1,"id":"2"}`, convention: fieldCaseSnake, want: `{"entity_status":1,"id":"2"}`},
+		{name: "camel case collision", row: `{"foo_bar":1,"fooBar":2}`, convention: fieldCaseCamel, wantErr: true},
+		{name: "snake case collision", row: `{"foo_bar":1,"fooBar":2}`, convention: fieldCaseSnake, wantErr: true},
+		{name: "hyphen collision", row: `{"foo-bar":1,"foo_bar":2}`, convention: fieldCaseSnake, wantErr: true},
+		{name: "unknown convention", row: `{"id":1}`, convention: "kebab-case", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := transformFieldNames([]json.RawMessage{json.RawMessage(tt.row)}, tt.convention)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("transformFieldNames() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if string(got[0]) != tt.want {
+				t.Errorf("transformFieldNames() = %s, want %s", got[0], tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/glints-dev/mcp-netsuite/pkg/netsuite"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// handleDownloadFile handles the netsuite_download_file tool request
+func handleDownloadFile(ctx context.Context, client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get file ID from arguments
+	fileID, err := request.RequireInt("file_id")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid file_id parameter: %v", err)), nil
+	}
+
+	file, err := client.DownloadFile(ctx, fileID)
+	if err != nil {
+		if errors.Is(err, netsuite.ErrFileTooLarge) {
+			return mcp.NewToolResultError(fmt.Sprintf("File %d is too large to return; raise NETSUITE_MAX_FILE_BYTES to download it: %v", fileID, err)), nil
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to download file %d: %v", fileID, err)), nil
+	}
+
+	// Text that is not valid UTF-8 cannot be embedded in JSON as is
+	encoding := "text"
+	content := string(file.Content)
+	if !netsuite.IsTextContentType(file.ContentType) || !utf8.Valid(file.Content) {
+		encoding = "base64"
+		content = base64.StdEncoding.EncodeToString(file.Content)
+	}
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"file_id":      file.ID,
+		"name":         file.Name,
+		"content_type": file.ContentType,
+		"size":         len(file.Content),
+		"encoding":     encoding,
+		"content":      content,
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
@@ -0,0 +1,111 @@
+package mcpserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Output formats supported by the format tool parameter
+const (
+	formatJSON     = "json"
+	formatNDJSON   = "ndjson"
+	formatMarkdown = "markdown"
+)
+
+// maxMarkdownCellLength is the number of characters kept in a markdown table
+// cell, so that long text columns do not make the table unreadable.
+const maxMarkdownCellLength = 60
+
+// renderNDJSON renders the rows as newline-delimited JSON, one compact object
+// per line, without any summary.
+func renderNDJSON(items []json.RawMessage) (string, error) {
+	var buffer bytes.Buffer
+	for _, item := range items {
+		if err := json.Compact(&buffer, item); err != nil {
+			return "", fmt.Errorf("failed to compact JSON: %w", err)
+		}
+		buffer.WriteByte('\n')
+	}
+
+	return buffer.String(), nil
+}
+
+// renderMarkdown renders the rows as a GitHub-flavored markdown table. Cells
+// longer than maxMarkdownCellLength are truncated, with a note below the
+// table.
+func renderMarkdown(items []json.RawMessage) (string, error) {
+	if len(items) == 0 {
+		return "No rows returned.\n", nil
+	}
+
+	rows := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		decoder := json.NewDecoder(bytes.NewReader(item))
+		decoder.UseNumber()
+
+		var row map[string]interface{}
+		if err := decoder.Decode(&row); err != nil {
+			return "", fmt.Errorf("failed to unmarshal JSON: %w", err)
+		}
+		rows = append(rows, row)
+	}
+
+	header := tableHeader(rows)
+
+	var buffer bytes.Buffer
+	buffer.WriteString("| " + strings.Join(header, " | ") + " |\n")
+	buffer.WriteString("|" + strings.Repeat(" --- |", len(header)) + "\n")
+
+	truncated := false
+	for _, row := range rows {
+		cells := make([]string, len(header))
+		for i, column := range header {
+			cell := []rune(markdownEscaper.Replace(csvValue(row[column])))
+			if len(cell) > maxMarkdownCellLength {
+				cell = append(cell[:maxMarkdownCellLength-1], '…')
+				truncated = true
+			}
+			cells[i] = string(cell)
+		}
+		buffer.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	if truncated {
+		fmt.Fprintf(&buffer, "\nCells longer than %d characters were truncated.\n", maxMarkdownCellLength)
+	}
+
+	return buffer.String(), nil
+}
+
+// markdownEscaper keeps cell values from breaking the table layout.
+var markdownEscaper = strings.NewReplacer(
+	"|", "\\|",
+	"\r\n", " ",
+	"\n", " ",
+	"\r", " ",
+)
+
+// tableHeader returns the union of the columns of the rows, sorted. NetSuite
+// omits null columns from rows, so no single row is guaranteed to hold every
+// column. The links NetSuite adds to rows are left out.
+func tableHeader(rows []map[string]interface{}) []string {
+	columns := make(map[string]struct{})
+	for _, row := range rows {
+		for column := range row {
+			if column != "links" {
+				columns[column] = struct{}{}
+			}
+		}
+	}
+
+	header := make([]string, 0, len(columns))
+	for column := range columns {
+		header = append(header, column)
+	}
+	sort.Strings(header)
+
+	return header
+}
@@ -0,0 +1,119 @@
+package mcpserver
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/glints-dev/mcp-netsuite/pkg/netsuite"
+)
+
+// Sort orders supported by the sort_order tool parameter
+const (
+	sortOrderAsc  = "asc"
+	sortOrderDesc = "desc"
+)
+
+// listedRecords fetches the records of a page of netsuite_list_records
+// concurrently and keeps the requested fields of each, along with its ID.
+// Without fields, only the IDs are returned and nothing is fetched. Records
+// that could not be fetched are reported by ID instead.
+func listedRecords(ctx context.Context, client *netsuite.Client, recordType string, ids []string, fields []string) ([]map[string]interface{}, map[string]string) {
+	records := make([]map[string]interface{}, len(ids))
+	failures := make(map[string]string)
+	if len(fields) == 0 {
+		for i, id := range ids {
+			records[i] = map[string]interface{}{"id": id}
+		}
+		return records, failures
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+
+			record, err := client.GetRecord(ctx, recordType, id, 0)
+			if err != nil {
+				mutex.Lock()
+				failures[id] = err.Error()
+				mutex.Unlock()
+				return
+			}
+
+			listed := map[string]interface{}{"id": id}
+			for _, field := range fields {
+				if value, ok := record[field]; ok {
+					listed[field] = value
+				}
+			}
+			records[i] = listed
+		}(i, id)
+	}
+	wg.Wait()
+
+	// Drop the records that could not be fetched
+	return slices.DeleteFunc(records, func(record map[string]interface{}) bool {
+		return record == nil
+	}), failures
+}
+
+// sortRecords sorts records by a field, stably so that records with equal
+// values keep NetSuite's order. Records missing the field sort last.
+func sortRecords(records []map[string]interface{}, field string, order string) {
+	slices.SortStableFunc(records, func(a, b map[string]interface{}) int {
+		aValue, aOK := sortValue(a[field])
+		bValue, bOK := sortValue(b[field])
+		switch {
+		case !aOK && !bOK:
+			return 0
+		case !aOK:
+			return 1
+		case !bOK:
+			return -1
+		}
+
+		result := compareSortValues(aValue, bValue)
+		if order == sortOrderDesc {
+			return -result
+		}
+		return result
+	})
+}
+
+// sortValue returns the value a field is sorted on. References to other
+// records sort on their display name.
+func sortValue(value interface{}) (interface{}, bool) {
+	switch value := value.(type) {
+	case nil:
+		return nil, false
+	case map[string]interface{}:
+		refName, ok := value["refName"]
+		if !ok {
+			return nil, false
+		}
+		return sortValue(refName)
+	case json.Number:
+		return sortValue(value.String())
+	default:
+		return value, true
+	}
+}
+
+// compareSortValues compares numbers, including numbers returned as strings
+// such as IDs, numerically, and everything else as text.
+func compareSortValues(a, b interface{}) int {
+	aNumber, aErr := strconv.ParseFloat(fmt.Sprint(a), 64)
+	bNumber, bErr := strconv.ParseFloat(fmt.Sprint(b), 64)
+	if aErr == nil && bErr == nil {
+		return cmp.Compare(aNumber, bNumber)
+	}
+
+	return cmp.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
@@ -0,0 +1,46 @@
+package mcpserver
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortRecords(t *testing.T) {
+	records := func() []map[string]interface{} {
+		return []map[string]interface{}{
+			{"id": "10", "companyName": "Beta", "balance": 5.5, "status": map[string]interface{}{"id": "2", "refName": "Lead"}},
+			{"id": "9", "companyName": "alpha", "balance": 120.0, "status": map[string]interface{}{"id": "1", "refName": "Customer"}},
+			{"id": "100", "balance": 5.5},
+			{"id": "11", "companyName": "Alpha", "balance": nil, "status": map[string]interface{}{"id": "3"}},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		field string
+		order string
+		want  []string
+	}{
+		{name: "id numerically", field: "id", order: sortOrderAsc, want: []string{"9", "10", "11", "100"}},
+		{name: "id descending", field: "id", order: sortOrderDesc, want: []string{"100", "11", "10", "9"}},
+		{name: "text with missing last", field: "companyName", order: sortOrderAsc, want: []string{"11", "10", "9", "100"}},
+		{name: "text descending with missing last", field: "companyName", order: sortOrderDesc, want: []string{"9", "10", "11", "100"}},
+		{name: "numbers stable with nulls last", field: "balance", order: sortOrderAsc, want: []string{"10", "100", "9", "11"}},
+		{name: "references by name", field: "status", order: sortOrderAsc, want: []string{"9", "10", "100", "11"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorted := records()
+			sortRecords(sorted, tt.field, tt.order)
+
+			var got []string
+			for _, record := range sorted {
+				got = append(got, record["id"].(string))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sortRecords() order = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/glints-dev/mcp-netsuite/pkg/netsuite"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// handleUpsertRecord handles the netsuite_upsert_record tool request
+func handleUpsertRecord(ctx context.Context, client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get record type, external ID, and payload from arguments
+	recordType, err := request.RequireString("record_type")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid record_type parameter: %v", err)), nil
+	}
+
+	externalID, err := request.RequireString("external_id")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid external_id parameter: %v", err)), nil
+	}
+
+	record, ok := request.GetArguments()["record"].(map[string]interface{})
+	if !ok {
+		return mcp.NewToolResultError("Invalid record parameter: expected a JSON object"), nil
+	}
+
+	// Upsert record in NetSuite
+	if request.GetBool("async", false) {
+		ctx = netsuite.WithRespondAsync(ctx)
+	}
+	if subsidiary := request.GetInt("subsidiary", 0); subsidiary > 0 {
+		ctx = netsuite.WithSubsidiary(ctx, subsidiary)
+	}
+	result, err := client.UpsertRecord(ctx, recordType, externalID, record)
+	if err != nil {
+		if fieldErrorsResult := newFieldErrorsResult(err, fmt.Sprintf("NetSuite rejected the %s record '%s'", recordType, externalID), config); fieldErrorsResult != nil {
+			return fieldErrorsResult, nil
+		}
+
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to upsert %s record '%s': %v", recordType, externalID, err)), nil
+	}
+	if result.JobID != "" {
+		return newJobAcceptedResult(result.JobID, config), nil
+	}
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"record_type": recordType,
+		"external_id": externalID,
+		"created":     result.Created,
+		"id":          result.ID,
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// handleTransformRecord handles the netsuite_transform_record tool request
+func handleTransformRecord(ctx context.Context, client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get record types, ID, and optional payload from arguments
+	fromType, err := request.RequireString("from_type")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid from_type parameter: %v", err)), nil
+	}
+
+	id, err := request.RequireString("id")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid id parameter: %v", err)), nil
+	}
+
+	toType, err := request.RequireString("to_type")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid to_type parameter: %v", err)), nil
+	}
+
+	record, _ := request.GetArguments()["record"].(map[string]interface{})
+
+	// Transform record in NetSuite
+	if request.GetBool("async", false) {
+		ctx = netsuite.WithRespondAsync(ctx)
+	}
+	result, err := client.TransformRecord(ctx, fromType, id, toType, record)
+	if err != nil {
+		if fieldErrorsResult := newFieldErrorsResult(err, fmt.Sprintf("NetSuite rejected the %s created from %s record '%s'", toType, fromType, id), config); fieldErrorsResult != nil {
+			return fieldErrorsResult, nil
+		}
+
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to transform %s record '%s' into %s: %v", fromType, id, toType, err)), nil
+	}
+	if result.JobID != "" {
+		return newJobAcceptedResult(result.JobID, config), nil
+	}
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"from_type": fromType,
+		"from_id":   id,
+		"to_type":   toType,
+		"id":        result.ID,
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// maxCreateRecords is the number of records netsuite_create_records creates
+// at most in a single call.
+const maxCreateRecords = 100
+
+// handleCreateRecords handles the netsuite_create_records tool request
+func handleCreateRecords(ctx context.Context, client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get record type and payloads from arguments
+	recordType, err := request.RequireString("record_type")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid record_type parameter: %v", err)), nil
+	}
+
+	recordsArg, ok := request.GetArguments()["records"].([]interface{})
+	if !ok || len(recordsArg) == 0 {
+		return mcp.NewToolResultError("Invalid records parameter: expected a non-empty array of JSON objects"), nil
+	}
+	if len(recordsArg) > maxCreateRecords {
+		return mcp.NewToolResultError(fmt.Sprintf("Too many records: %d given, at most %d are allowed per call", len(recordsArg), maxCreateRecords)), nil
+	}
+
+	records := make([]map[string]interface{}, 0, len(recordsArg))
+	for i, recordArg := range recordsArg {
+		record, ok := recordArg.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid records parameter: record %d is not a JSON object", i)), nil
+		}
+		records = append(records, record)
+	}
+
+	// Create records in NetSuite
+	if request.GetBool("async", false) {
+		ctx = netsuite.WithRespondAsync(ctx)
+	}
+	if subsidiary := request.GetInt("subsidiary", 0); subsidiary > 0 {
+		ctx = netsuite.WithSubsidiary(ctx, subsidiary)
+	}
+	results, err := client.CreateRecords(ctx, recordType, records)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to create %s records: %v", recordType, err)), nil
+	}
+
+	created, jobs := 0, 0
+	for _, result := range results {
+		if result.Created() {
+			created++
+		}
+		if result.JobID != "" {
+			jobs++
+		}
+	}
+
+	var warnings []string
+	if jobs > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d records were accepted as jobs and have not been created yet; poll netsuite_get_job_status with their jobId for the outcome", jobs))
+	}
+	if failed := len(results) - created; failed > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d of %d records were not created; see the error of each result", failed, len(results)))
+	}
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"record_type": recordType,
+		"created":     created,
+		"failed":      len(results) - created,
+		"results":     results,
+		"warnings":    warnings,
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// newFieldErrorsResult returns an error result giving field validation
+// failures a structured shape so they can be fixed, or nil if the error has
+// no field errors.
+func newFieldErrorsResult(err error, message string, config Config) *mcp.CallToolResult {
+	var nsErr *netsuite.NetSuiteError
+	if !errors.As(err, &nsErr) {
+		return nil
+	}
+
+	fieldErrors := nsErr.FieldErrors()
+	if len(fieldErrors) == 0 {
+		return nil
+	}
+
+	result := newToolResultJSON(map[string]interface{}{
+		"error":        message,
+		"field_errors": fieldErrors,
+	}, config.PrettyOutput)
+	result.IsError = true
+
+	return result
+}
+
+// newJobAcceptedResult returns the result of a write NetSuite accepted as an
+// asynchronous job.
+func newJobAcceptedResult(jobID string, config Config) *mcp.CallToolResult {
+	return newToolResultJSON(map[string]interface{}{
+		"job_id":   jobID,
+		"accepted": true,
+		"warnings": []string{"The write has not happened yet; poll netsuite_get_job_status with the job_id for its outcome"},
+	}, config.PrettyOutput)
+}
+
+// handleGetJobStatus handles the netsuite_get_job_status tool request
+func handleGetJobStatus(ctx context.Context, client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get job ID from arguments
+	jobID, err := request.RequireString("job_id")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid job_id parameter: %v", err)), nil
+	}
+
+	// Get job status from NetSuite
+	status, err := client.GetJobStatus(ctx, jobID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get status of job '%s': %v", jobID, err)), nil
+	}
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"job_id":    status.ID,
+		"completed": status.Completed,
+		"progress":  status.Progress,
+		"tasks":     status.Tasks,
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
@@ -0,0 +1,74 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/glints-dev/mcp-netsuite/pkg/netsuite"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestNewFieldErrorsResult(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want []netsuite.FieldError
+	}{
+		{
+			name: "field errors",
+			err: fmt.Errorf("failed to upsert record: %w", &netsuite.NetSuiteError{
+				StatusCode: http.StatusBadRequest,
+				Details: []netsuite.ErrorDetail{
+					{Detail: "Please enter a value for Company Name.", ErrorCode: "USER_ERROR", Path: "companyName"},
+					{Detail: "Invalid request.", ErrorCode: "INVALID_CONTENT"},
+				},
+			}),
+			want: []netsuite.FieldError{{Field: "companyName", Message: "Please enter a value for Company Name.", Code: "USER_ERROR"}},
+		},
+		{
+			name: "no field errors",
+			err: &netsuite.NetSuiteError{
+				StatusCode: http.StatusBadRequest,
+				Details:    []netsuite.ErrorDetail{{Detail: "Invalid request.", ErrorCode: "INVALID_CONTENT"}},
+			},
+		},
+		{
+			name: "not a NetSuite error",
+			err:  errors.New("connection reset"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newFieldErrorsResult(tt.err, "NetSuite rejected the customer record 'C-1'", Config{})
+			if tt.want == nil {
+				if result != nil {
+					t.Fatalf("newFieldErrorsResult() = %+v, want nil", result)
+				}
+				return
+			}
+
+			if result == nil || !result.IsError {
+				t.Fatalf("newFieldErrorsResult() = %+v, want an error result", result)
+			}
+
+			var got struct {
+				Error       string                `json:"error"`
+				FieldErrors []netsuite.FieldError `json:"field_errors"`
+			}
+			if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &got); err != nil {
+				t.Fatalf("failed to unmarshal result: %v", err)
+			}
+			if got.Error != "NetSuite rejected the customer record 'C-1'" {
+				t.Errorf("error = %q, want the given message", got.Error)
+			}
+			if !reflect.DeepEqual(got.FieldErrors, tt.want) {
+				t.Errorf("field_errors = %+v, want %+v", got.FieldErrors, tt.want)
+			}
+		})
+	}
+}
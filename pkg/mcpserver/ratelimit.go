@@ -0,0 +1,128 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// RateLimit allows a tool to be called Count times per period, with bursts
+// of up to Count calls.
+type RateLimit struct {
+	Count int
+	Per   time.Duration
+}
+
+// rateLimitUnits are the period names accepted by ParseRateLimit, in addition
+// to Go durations such as "30s".
+var rateLimitUnits = map[string]time.Duration{
+	"s":      time.Second,
+	"sec":    time.Second,
+	"second": time.Second,
+	"m":      time.Minute,
+	"min":    time.Minute,
+	"minute": time.Minute,
+	"h":      time.Hour,
+	"hour":   time.Hour,
+	"d":      24 * time.Hour,
+	"day":    24 * time.Hour,
+}
+
+// ParseRateLimit parses a rate limit such as "10/min", "100/hour", or
+// "5/30s".
+func ParseRateLimit(value string) (RateLimit, error) {
+	countString, periodString, ok := strings.Cut(strings.TrimSpace(value), "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("invalid rate limit \"%s\": expected COUNT/PERIOD, e.g. 10/min", value)
+	}
+
+	count, err := strconv.Atoi(countString)
+	if err != nil || count <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit \"%s\": count must be a positive integer", value)
+	}
+
+	period, ok := rateLimitUnits[strings.ToLower(periodString)]
+	if !ok {
+		period, err = time.ParseDuration(periodString)
+		if err != nil || period <= 0 {
+			return RateLimit{}, fmt.Errorf("invalid rate limit \"%s\": unknown period \"%s\"", value, periodString)
+		}
+	}
+
+	return RateLimit{Count: count, Per: period}, nil
+}
+
+// tokenBucket holds up to limit.Count tokens, refilled continuously at
+// limit.Count per limit.Per. Each call takes one token.
+type tokenBucket struct {
+	limit RateLimit
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	return &tokenBucket{
+		limit:  limit,
+		tokens: float64(limit.Count),
+		last:   time.Now(),
+	}
+}
+
+// take takes a token if one is available. Otherwise, it returns how long to
+// wait for the next one.
+func (bucket *tokenBucket) take() (time.Duration, bool) {
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+
+	now := time.Now()
+	rate := float64(bucket.limit.Count) / float64(bucket.limit.Per)
+	bucket.tokens = math.Min(
+		float64(bucket.limit.Count),
+		bucket.tokens+float64(now.Sub(bucket.last))*rate,
+	)
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		return time.Duration((1 - bucket.tokens) / rate), false
+	}
+
+	bucket.tokens--
+	return 0, true
+}
+
+// rateLimitMiddleware rejects calls to the rate limited tools once their
+// limit is exhausted. Tools without a limit are not affected.
+func rateLimitMiddleware(limits map[string]RateLimit) server.ToolHandlerMiddleware {
+	buckets := make(map[string]*tokenBucket, len(limits))
+	for tool, limit := range limits {
+		buckets[tool] = newTokenBucket(limit)
+	}
+
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			bucket, ok := buckets[request.Params.Name]
+			if !ok {
+				return next(ctx, request)
+			}
+
+			if wait, ok := bucket.take(); !ok {
+				return mcp.NewToolResultError(fmt.Sprintf(
+					"Rate limit exceeded for %s, retry in %s",
+					request.Params.Name,
+					time.Duration(math.Ceil(wait.Seconds()))*time.Second,
+				)), nil
+			}
+
+			return next(ctx, request)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/glints-dev/mcp-netsuite/pkg/netsuite"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ReportTemplate is a named SuiteQL query run by netsuite_run_report. The
+// query refers to its parameters with named placeholders such as ":since",
+// whose values are bound by NetSuite rather than spliced into the query.
+type ReportTemplate struct {
+	Description string `json:"description"`
+	Query       string `json:"query"`
+
+	// Parameters maps the name of each placeholder to its description.
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// builtinReports are the templates available without configuration.
+// Config.Reports may add templates or replace these by name.
+var builtinReports = map[string]ReportTemplate{
+	"open_invoices": {
+		Description: "Customer invoices with an amount remaining, oldest due date first",
+		Query: `SELECT t.id, t.tranid, BUILTIN.DF(t.entity) AS customer, t.trandate, t.duedate, t.foreigntotal, t.foreignamountremaining
+FROM transaction t
+WHERE t.type = 'CustInvc' AND t.foreignamountremaining > 0
+ORDER BY t.duedate`,
+	},
+	"overdue_invoices": {
+		Description: "Customer invoices with an amount remaining past their due date, oldest due date first",
+		Query: `SELECT t.id, t.tranid, BUILTIN.DF(t.entity) AS customer, t.trandate, t.duedate, t.foreigntotal, t.foreignamountremaining
+FROM transaction t
+WHERE t.type = 'CustInvc' AND t.foreignamountremaining > 0 AND t.duedate < CURRENT_DATE
+ORDER BY t.duedate`,
+	},
+	"inventory_on_hand": {
+		Description: "Quantities on hand and available of every item, per location",
+		Query: `SELECT il.item, BUILTIN.DF(il.item) AS item_name, il.location, BUILTIN.DF(il.location) AS location_name, il.quantityonhand, il.quantityavailable
+FROM inventoryitemlocations il
+WHERE il.quantityonhand > 0
+ORDER BY item_name`,
+	},
+	"sales_by_customer": {
+		Description: "Number and total of sales orders per customer in a date range, largest total first",
+		Query: `SELECT BUILTIN.DF(t.entity) AS customer, COUNT(*) AS orders, SUM(t.foreigntotal) AS total
+FROM transaction t
+WHERE t.type = 'SalesOrd' AND t.trandate BETWEEN TO_DATE(:start_date, 'YYYY-MM-DD') AND TO_DATE(:end_date, 'YYYY-MM-DD')
+GROUP BY BUILTIN.DF(t.entity)
+ORDER BY SUM(t.foreigntotal) DESC`,
+		Parameters: map[string]string{
+			"start_date": "First transaction date included, formatted as YYYY-MM-DD",
+			"end_date":   "Last transaction date included, formatted as YYYY-MM-DD",
+		},
+	},
+	"new_customers": {
+		Description: "Customers created on or after a date, newest first",
+		Query: `SELECT c.id, c.entityid, c.companyname, c.email, c.datecreated
+FROM customer c
+WHERE c.datecreated >= TO_DATE(:since, 'YYYY-MM-DD')
+ORDER BY c.datecreated DESC`,
+		Parameters: map[string]string{
+			"since": "Earliest creation date included, formatted as YYYY-MM-DD",
+		},
+	},
+}
+
+// reportTemplates returns the built-in templates merged with the configured
+// ones, which take precedence.
+func reportTemplates(configured map[string]ReportTemplate) map[string]ReportTemplate {
+	templates := make(map[string]ReportTemplate, len(builtinReports)+len(configured))
+	for name, template := range builtinReports {
+		templates[name] = template
+	}
+	for name, template := range configured {
+		templates[name] = template
+	}
+
+	return templates
+}
+
+// describeReports lists the templates with their parameters, for the tool
+// description.
+func describeReports(templates map[string]ReportTemplate) string {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var builder strings.Builder
+	for _, name := range names {
+		template := templates[name]
+		fmt.Fprintf(&builder, "\n- %s: %s", name, template.Description)
+
+		parameters := make([]string, 0, len(template.Parameters))
+		for parameter := range template.Parameters {
+			parameters = append(parameters, parameter)
+		}
+		sort.Strings(parameters)
+
+		for _, parameter := range parameters {
+			fmt.Fprintf(&builder, "\n  - %s: %s", parameter, template.Parameters[parameter])
+		}
+	}
+
+	return builder.String()
+}
+
+// handleRunReport handles the netsuite_run_report tool request
+func handleRunReport(ctx context.Context, client *netsuite.Client, config Config, templates map[string]ReportTemplate, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get report name, parameters, and limit from arguments
+	name, err := request.RequireString("report")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid report parameter: %v", err)), nil
+	}
+
+	template, ok := templates[name]
+	if !ok {
+		names := make([]string, 0, len(templates))
+		for templateName := range templates {
+			names = append(names, templateName)
+		}
+		sort.Strings(names)
+
+		return mcp.NewToolResultError(fmt.Sprintf("Unknown report '%s'; available reports: %s", name, strings.Join(names, ", "))), nil
+	}
+
+	parameters, _ := request.GetArguments()["parameters"].(map[string]interface{})
+	for parameter := range parameters {
+		if _, ok := template.Parameters[parameter]; !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("Report '%s' has no parameter '%s'", name, parameter)), nil
+		}
+	}
+
+	limit, warnings := clampParameter(nil, "limit", request.GetInt("limit", client.DefaultLimit()), 1, netsuite.MaxLimit)
+
+	// Run the report query in NetSuite
+	results, err := client.SuiteQLNamed(ctx, template.Query, parameters, limit, 0)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to run report '%s': %v", name, err)), nil
+	}
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"report":       name,
+		"count":        results.Count,
+		"totalResults": results.TotalResults,
+		"hasMore":      results.HasMore,
+		"items":        results.Items,
+		"warnings":     warnings,
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
@@ -0,0 +1,100 @@
+package mcpserver
+
+import (
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// suiteQLOutputSchema describes the result of netsuite_run_suiteql. Only the
+// paging fields are required, since the ndjson and markdown formats return
+// the rows as text alongside them.
+var suiteQLOutputSchema = json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"query": {"type": "string"},
+		"limit": {"type": "integer"},
+		"default_limit": {"type": "integer"},
+		"offset": {"type": "integer"},
+		"count": {"type": "integer"},
+		"totalResults": {"type": "integer"},
+		"hasMore": {"type": "boolean"},
+		"items": {
+			"type": "array",
+			"items": {"type": "object"}
+		},
+		"summary": {"type": "object"},
+		"warnings": {
+			"type": "array",
+			"items": {"type": "string"}
+		},
+		"diagnostics": {"type": "object"},
+		"key_columns": {
+			"type": "array",
+			"items": {"type": "string"}
+		},
+		"next_cursor": {"type": ["array", "null"]},
+		"columns": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"type": {"type": "string"},
+					"catalog_type": {"type": "string"},
+					"description": {"type": "string"}
+				},
+				"required": ["name", "type"]
+			}
+		}
+	},
+	"required": ["count", "hasMore", "warnings"]
+}`)
+
+// metadataOutputSchema describes the structured result of
+// netsuite_get_metadata. It holds the flattened fields, if requested, but
+// never the nested schema, which is only returned in the text result.
+var metadataOutputSchema = json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"record_type": {"type": "string"},
+		"included_fields": {
+			"type": ["array", "null"],
+			"items": {"type": "string"}
+		},
+		"metadata_summary": {"type": "object"},
+		"schema_hash": {"type": "string"},
+		"inactive_filterable": {"type": "boolean"},
+		"warnings": {
+			"type": "array",
+			"items": {"type": "string"}
+		},
+		"diagnostics": {"type": "object"},
+		"metadata_fields": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"properties": {
+					"path": {"type": "string"},
+					"type": {"type": "string"},
+					"format": {"type": "string"},
+					"required": {"type": "boolean"},
+					"description": {"type": "string"},
+					"ref": {"type": "string"}
+				}
+			}
+		}
+	},
+	"required": ["record_type", "metadata_summary", "warnings"]
+}`)
+
+// newStructuredToolResultJSON is like newToolResultJSON, but also returns the
+// response as structured content, for tools that declare an output schema.
+func newStructuredToolResultJSON(response interface{}, pretty bool) *mcp.CallToolResult {
+	result := newToolResultJSON(response, pretty)
+	if !result.IsError {
+		result.StructuredContent = response
+	}
+
+	return result
+}
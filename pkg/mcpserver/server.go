@@ -0,0 +1,2448 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/glints-dev/mcp-netsuite/pkg/jsonschematree"
+	"github.com/glints-dev/mcp-netsuite/pkg/netsuite"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Config holds all configuration for the MCP server
+type Config struct {
+	NetSuiteOptions netsuite.ClientOptions
+
+	// RecordTypes are the record types of interest, read from
+	// NETSUITE_RECORD_TYPES. Their metadata can be preloaded at startup with
+	// Client.PreloadMetadata.
+	RecordTypes  []string
+	PrettyOutput bool
+
+	// ExportDir is the directory netsuite_export_suiteql may write to. The
+	// tool is not registered when it is empty.
+	ExportDir string
+
+	// EnableMutations registers the tools that write to NetSuite, such as
+	// netsuite_upsert_record. The server is read-only without it.
+	EnableMutations bool
+
+	// ForbidSelectStar rejects SuiteQL queries given to tools that select
+	// every column with "SELECT *", for cost control. Queries the server
+	// builds itself, such as schema probes, are not affected.
+	ForbidSelectStar bool
+
+	// RateLimits caps how often each tool, by name, may be called.
+	RateLimits map[string]RateLimit
+
+	// ColumnAliases renames columns in SuiteQL output to friendlier names,
+	// by record type and then column name, e.g. "entityid" to
+	// "customer_number" for queries on customer. The record type is the
+	// first table of the FROM clause. Unmapped columns are left untouched.
+	ColumnAliases map[string]map[string]string
+
+	// Reports adds report templates to netsuite_run_report, replacing the
+	// built-in templates of the same name.
+	Reports map[string]ReportTemplate
+}
+
+// NewServer creates an MCP server with the built-in NetSuite tools
+// registered. Callers may add their own tools before serving it.
+func NewServer(client *netsuite.Client, config Config) *server.MCPServer {
+	s := server.NewMCPServer(
+		"NetSuite MCP Server",
+		"1.0.0",
+		server.WithToolCapabilities(true),
+		server.WithToolHandlerMiddleware(rateLimitMiddleware(config.RateLimits)),
+		server.WithInstructions(`This is a NetSuite MCP Server that provides access to NetSuite data through the following tools:
+
+IMPORTANT WORKFLOW:
+1. ALWAYS use 'netsuite_get_metadata' FIRST to understand the schema of NetSuite record types before querying
+2. Review the returned metadata to verify field names, data types, and structure
+3. Then use 'netsuite_run_suiteql' with the verified field names from the metadata
+
+TOOL USAGE GUIDELINES:
+
+netsuite_get_metadata:
+- Use this tool to get the schema/structure of NetSuite record types
+- Common record types: customer, item, transaction, salesorder, purchaseorder, invoice, employee, vendor
+- This helps you understand what fields are available and their correct names
+- Always call this before writing SuiteQL queries for unfamiliar record types
+
+netsuite_get_metadata_bulk:
+- Use this tool instead of several netsuite_get_metadata calls when modeling related record types together
+- Failures are reported per record type under 'errors'
+
+netsuite_run_suiteql:
+- Use this tool to execute SuiteQL queries against NetSuite
+- MUST be preceded by netsuite_get_metadata to verify field names and structure
+- Use proper NetSuite field names (often different from UI labels)
+- Table names are typically lowercase (e.g., 'customer', 'item', 'transaction')
+- Include LIMIT clauses to avoid retrieving too much data
+- Be mindful of NetSuite's query performance considerations
+- Prefer named placeholders with named_params (e.g. 'WHERE lastmodifieddate > :since') over splicing values into the query
+- For relative periods such as last month, put {{date_range}} in the WHERE clause and set date_range and date_column instead of computing dates
+- When a column is unknown, the error may suggest similar column names under did_you_mean
+- When a table is unknown to SuiteQL but is a REST record type, the error points to netsuite_list_records instead
+- Pass exclude_inactive to leave out archived rows; the query must then select isinactive
+
+netsuite_explain_record:
+- Use this tool to introduce a record type to a user, or to get oriented before reading its full metadata
+- Relay its summary rather than the raw schema
+
+netsuite_describe_relationships:
+- Use this tool to see which fields of a record type reference other record types
+- Helpful for data-modeling questions and for deciding which tables to JOIN
+
+netsuite_get_changes:
+- Use this tool to pull only the records modified since a timestamp
+- Pass the returned high_water_mark as 'since' and high_water_mark_id as 'after_id' on the next call while hasMore is true
+
+netsuite_diff_query:
+- Use this tool to monitor what changed in a query's results between runs; pass the returned snapshot_id on the next call
+- Snapshots are kept in memory until the server restarts
+
+netsuite_estimate_cost:
+- Use this tool before running a query that may scan a large table, and add filters if it warns about poor selectivity
+
+netsuite_export_schema:
+- Use this tool when generating code or types from a record type; its output has a stable, documented shape unlike netsuite_get_metadata
+
+netsuite_describe_custom_fields:
+- Use this tool to find out what custom fields such as custbody_* and custcol_* hold before querying them
+- netsuite_get_metadata and netsuite_run_suiteql with annotate set also label the custom fields they return
+
+netsuite_lint_suiteql:
+- Use this tool to catch misspelled columns and mistyped literals before running a query
+- It is best-effort: with JOINs, only columns qualified with the FROM table's alias are checked
+
+netsuite_list_records:
+- Use this tool to page through the records matching a REST record query filter
+- Pass fields to get those fields of each record instead of only its ID, and sort to order the page by one of them
+- Sorting only applies within a page; use netsuite_run_suiteql with ORDER BY to sort across all records
+- Pass exclude_inactive to leave out archived records when netsuite_get_metadata reports inactive_filterable
+
+netsuite_count_records:
+- Use this tool to answer "how many" questions cheaply instead of fetching rows
+
+netsuite_get_record:
+- Use this tool to fetch one record with its sublists and subrecords
+- Keep expand_depth low; deeply expanded records can be very large
+
+netsuite_get_records_bulk:
+- Use this tool instead of several netsuite_get_record calls when you already have the internal IDs
+- Failures are reported per ID under 'errors'
+
+netsuite_get_sublist:
+- Use this tool to page through the lines of a large sublist instead of expanding the whole record with netsuite_get_record
+
+netsuite_get_sublist_flattened:
+- Use this tool instead of netsuite_get_sublist when each line needs its parent's context, such as the customer and date of a transaction's lines
+- Choose parent_fields sparingly, since they are repeated on every line
+
+netsuite_download_file:
+- Use this tool to read the content of a file a record refers to, by the file's internal ID
+- Binary content is base64-encoded; large files are rejected, so check the file size first when it is known
+
+netsuite_validate_record:
+- Use this tool to dry-run a record payload against its schema before writing it
+- Reports missing required fields, type mismatches, over-length strings, and values outside an enumeration
+
+netsuite_example_record:
+- Use this tool to get a template of a record payload to modify instead of guessing its shape
+
+netsuite_run_report:
+- Use this tool for common questions such as open invoices or inventory on hand before writing SuiteQL by hand
+
+netsuite_upsert_record (only when mutations are enabled):
+- Use this tool to create or replace a record by external ID; repeating the call with the same payload is safe
+- This writes to NetSuite; validate the payload with netsuite_validate_record first
+
+netsuite_transform_record (only when mutations are enabled):
+- Use this tool to turn a record into the next one in its workflow, e.g. bill a sales order by transforming it into an invoice
+- This writes to NetSuite
+
+netsuite_create_records (only when mutations are enabled):
+- Use this tool to load many new records of one type in one call; each record is validated against the schema before anything is sent
+- Records are created independently, so check each result; repeating the call creates duplicates of the records that succeeded
+
+netsuite_get_job_status (only when mutations are enabled):
+- Writes with async set return a job_id at once; use this tool to poll it until completed is true, then check each task's statusCode and error
+
+netsuite_describe_table:
+- Use this tool when a SuiteQL table is not in the metadata catalog, or its name differs from the record type
+
+netsuite_field_catalog:
+- Use this tool to get the SuiteQL column names of several record types at once
+- Reference columns hold internal IDs and name the record type they point at
+
+netsuite_list_field_options:
+- Use this tool to get the valid internal IDs and labels of a select field, e.g. before filtering on it or setting it
+
+netsuite_preview_record:
+- Use this tool to take a quick look at a table's rows and columns before writing a query
+
+netsuite_get_preferences:
+- Use this tool to learn the base currency, date format, and time zone before presenting dates or amounts
+
+netsuite_get_system_info:
+- Use this tool to answer questions about the account itself, such as which subsidiaries, roles, or currencies exist
+- Topics listed under 'unavailable' depend on a feature that is disabled or a permission the role lacks
+- Enabled features and installed SuiteApps are not covered, since SuiteQL has no table for them
+
+netsuite_display_value_expression:
+- Select fields return internal IDs; use this tool to get the BUILTIN.DF(field) expression for their display value
+
+Example workflow:
+1. Call netsuite_get_metadata with record_type="customer" 
+2. Review the returned fields and their types
+3. Construct your SuiteQL query using the verified field names
+4. Execute the query with netsuite_run_suiteql`),
+	)
+
+	// Add NetSuite metadata tool
+	metadataTool := mcp.NewTool("netsuite_get_metadata",
+		mcp.WithDescription("Get metadata (schema) for a NetSuite record type"),
+		mcp.WithString("record_type",
+			mcp.Required(),
+			mcp.Description("The NetSuite record type to get metadata for (e.g., 'customer', 'item', 'transaction')"),
+		),
+		mcp.WithArray("included_fields",
+			mcp.Description("Optional list of specific fields to include in the metadata. If not provided, all available fields will be returned."),
+		),
+		mcp.WithBoolean("resolve_references",
+			mcp.Description("Replace every $ref in the schema with the schema it refers to (default: false)"),
+		),
+		mcp.WithBoolean("flat",
+			mcp.Description("Return a flat list of the fields in declaration order, with their dotted path, type, format, required flag, and description, instead of the nested schema (default: false)"),
+		),
+		mcp.WithRawOutputSchema(metadataOutputSchema),
+	)
+
+	// Add tool handler
+	s.AddTool(metadataTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleGetMetadata(ctx, client, config, request)
+	})
+
+	// Add NetSuite SuiteQL tool
+	suiteQLTool := mcp.NewTool("netsuite_run_suiteql",
+		mcp.WithDescription("Execute a SuiteQL query against NetSuite and return the results"),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("The SuiteQL query to execute (e.g., 'SELECT id, companyname FROM customer LIMIT 10')"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of results to return (default: %d, max: %d)", client.DefaultLimit(), netsuite.MaxLimit)),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Number of records to skip for pagination (default: 0)"),
+		),
+		mcp.WithObject("named_params",
+			mcp.Description("Optional values for named placeholders in the query, e.g. {\"status\": \"open\"} for 'WHERE status = :status'. Values are bound as parameters, not spliced into the query."),
+		),
+		mcp.WithArray("key_columns",
+			mcp.Description("Optional key columns for keyset pagination, e.g. ['id'] or ['transaction', 'id'] for composite keys. The columns must be selected by the query. When set, offset is ignored and a next_cursor is returned."),
+		),
+		mcp.WithArray("after",
+			mcp.Description("The next_cursor returned by the previous keyset page. Omit it to fetch the first page."),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format: 'json' returns the rows with paging details and a summary, 'ndjson' returns only the rows, one JSON object per line, and 'markdown' returns only the rows as a table for presenting to a human (default: json)"),
+			mcp.Enum(formatJSON, formatNDJSON, formatMarkdown),
+		),
+		mcp.WithString("field_case",
+			mcp.Description("Naming convention for the keys of the returned rows: 'raw' keeps NetSuite's names, 'snake_case' turns 'entityStatus' into 'entity_status', 'camelCase' turns 'custbody_due_date' into 'custbodyDueDate' (default: raw)"),
+			mcp.Enum(fieldCaseRaw, fieldCaseSnake, fieldCaseCamel),
+		),
+		mcp.WithNumber("max_field_length",
+			mcp.Description("Truncate string values longer than this many characters, appending a marker with the original length, so a single huge field does not crowd out the rest. Applies to every format (default: 0, no truncation)"),
+		),
+		mcp.WithBoolean("omit_nulls",
+			mcp.Description("Leave out the fields of each row whose value is null, to save tokens. Applies to every format; the summary still describes the rows as NetSuite returned them (default: false)"),
+		),
+		mcp.WithBoolean("annotate",
+			mcp.Description("Describe each returned column with its inferred type and, when the FROM table has catalog metadata, its catalog type and description (default: false)"),
+		),
+		mcp.WithBoolean("include_links",
+			mcp.Description("Include the self, next, and last links NetSuite returned with the page under diagnostics, e.g. to verify paging. For the ndjson and markdown formats, they are in the diagnostics of the structured content (default: false)"),
+		),
+		mcp.WithBoolean("exclude_inactive",
+			mcp.Description("Keep only the rows with isinactive = 'F', leaving out archived records. The query must select the isinactive column and must not page itself; ignored with a warning for tables without the field (default: false)"),
+		),
+		mcp.WithString("as_of_date",
+			mcp.Description("Run the query as of this date (YYYY-MM-DD) for effective-dated reporting. NetSuite rejects the query where historical context is not supported"),
+		),
+		mcp.WithObject("headers",
+			mcp.Description("Optional extra HTTP headers to send to NetSuite with this call, to toggle NetSuite features (e.g., {'X-NetSuite-PropertyNameValidation': 'Warning'})"),
+		),
+		mcp.WithString("date_range",
+			mcp.Description("Optional relative date range replacing the "+dateRangeMacro+" placeholder in the query with a predicate on date_column, computed in the account's time zone. One of: "+strings.Join(netsuite.DateRanges(), ", ")),
+		),
+		mcp.WithString("date_column",
+			mcp.Description("The date column date_range filters on, e.g. 't.trandate'"),
+		),
+		mcp.WithRawOutputSchema(suiteQLOutputSchema),
+	)
+
+	// Add SuiteQL tool handler
+	s.AddTool(suiteQLTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleRunSuiteQL(ctx, client, config, request)
+	})
+
+	// Add NetSuite relationships tool
+	relationshipsTool := mcp.NewTool("netsuite_describe_relationships",
+		mcp.WithDescription("List the fields of a NetSuite record type that reference other record types"),
+		mcp.WithString("record_type",
+			mcp.Required(),
+			mcp.Description("The NetSuite record type to describe relationships for (e.g., 'customer', 'salesorder')"),
+		),
+	)
+
+	// Add relationships tool handler
+	s.AddTool(relationshipsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleDescribeRelationships(client, config, request)
+	})
+
+	// Add NetSuite record explanation tool
+	explainTool := mcp.NewTool("netsuite_explain_record",
+		mcp.WithDescription("Explain a NetSuite record type in brief: its key and required fields, custom fields, the record types it refers to, and its sublists, with a one-paragraph summary to relay"),
+		mcp.WithString("record_type",
+			mcp.Required(),
+			mcp.Description("The NetSuite record type to explain (e.g., 'customer', 'salesorder')"),
+		),
+	)
+
+	// Add record explanation tool handler
+	s.AddTool(explainTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleExplainRecord(ctx, client, config, request)
+	})
+
+	// Add NetSuite incremental extraction tool
+	changesTool := mcp.NewTool("netsuite_get_changes",
+		mcp.WithDescription("Get the records of a NetSuite record type that changed since a timestamp, along with the new high-water mark"),
+		mcp.WithString("record_type",
+			mcp.Required(),
+			mcp.Description("The NetSuite record type to get changes for (e.g., 'customer', 'transaction'). It must have a lastmodifieddate column."),
+		),
+		mcp.WithString("since",
+			mcp.Required(),
+			mcp.Description("Only records modified after this timestamp are returned, formatted as 'YYYY-MM-DD HH:MM:SS' or 'YYYY-MM-DD'"),
+		),
+		mcp.WithNumber("after_id",
+			mcp.Description("Also return the records modified at exactly 'since' whose internal ID is greater than this. Pass the high_water_mark_id of the previous call (default: 0)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of results to return (default: %d, max: %d)", client.DefaultLimit(), netsuite.MaxLimit)),
+		),
+	)
+
+	// Add incremental extraction tool handler
+	s.AddTool(changesTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleGetChanges(ctx, client, config, request)
+	})
+
+	// Add NetSuite query diff tool
+	diffTool := mcp.NewTool("netsuite_diff_query",
+		mcp.WithDescription("Run a SuiteQL query and report the rows added, removed, or changed since a previously saved snapshot of it. Each call saves a new snapshot"),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("The SuiteQL query to run, which must return at most 1000 rows"),
+		),
+		mcp.WithString("key_column",
+			mcp.Description("The column identifying rows across runs (default: id)"),
+		),
+		mcp.WithString("snapshot_id",
+			mcp.Description("The snapshot_id returned by an earlier call with the same query to compare against. Without it, the results are only saved"),
+		),
+	)
+
+	// Add query diff tool handler
+	snapshots := newSnapshotStore()
+	s.AddTool(diffTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleDiffQuery(ctx, client, config, snapshots, request)
+	})
+
+	// Add NetSuite count tool
+	countTool := mcp.NewTool("netsuite_count_records",
+		mcp.WithDescription("Count the records of a NetSuite record type without retrieving them"),
+		mcp.WithString("record_type",
+			mcp.Required(),
+			mcp.Description("The NetSuite record type to count (e.g., 'customer', 'salesorder')"),
+		),
+		mcp.WithString("filter",
+			mcp.Description("Optional filter in the REST record query language (e.g., 'isInactive IS false'). If not provided, all records are counted."),
+		),
+		mcp.WithBoolean("exclude_inactive",
+			mcp.Description("Only count active records, when the record type has an isinactive field (default: false)"),
+		),
+	)
+
+	// Add count tool handler
+	s.AddTool(countTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleCountRecords(ctx, client, config, request)
+	})
+
+	// Add NetSuite cost estimate tool
+	estimateTool := mcp.NewTool("netsuite_estimate_cost",
+		mcp.WithDescription("Estimate how expensive a SuiteQL query is by counting the rows it returns against the rows of its source table, before running it"),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("The SuiteQL query to estimate"),
+		),
+	)
+
+	// Add cost estimate tool handler
+	s.AddTool(estimateTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleEstimateCost(ctx, client, config, request)
+	})
+
+	// Add NetSuite schema export tool
+	exportSchemaTool := mcp.NewTool("netsuite_export_schema",
+		mcp.WithDescription(fmt.Sprintf("Export the schema of a record type in a normalized shape (version %d) for generating types, e.g. Go structs or TypeScript interfaces: references to other records are reference fields, other references are resolved, and every field has a single type", jsonschematree.CodegenVersion)),
+		mcp.WithString("record_type",
+			mcp.Required(),
+			mcp.Description("The NetSuite record type to export (e.g., 'customer', 'salesorder')"),
+		),
+	)
+
+	// Add schema export tool handler
+	s.AddTool(exportSchemaTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleExportSchema(client, config, request)
+	})
+
+	// Add NetSuite custom fields tool
+	customFieldsTool := mcp.NewTool("netsuite_describe_custom_fields",
+		mcp.WithDescription("Map custom field IDs such as custbody_approver or custcol_discount to their labels, kinds, and value types"),
+		mcp.WithString("record_type",
+			mcp.Description("Only describe the custom fields of this record type, according to its metadata (e.g., 'salesorder')"),
+		),
+		mcp.WithArray("field_ids",
+			mcp.Description("Only describe these custom fields (e.g., ['custbody_approver']). Without record_type or field_ids, every custom field is described"),
+		),
+	)
+
+	// Add custom fields tool handler
+	s.AddTool(customFieldsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleDescribeCustomFields(ctx, client, config, request)
+	})
+
+	// Add NetSuite SuiteQL lint tool
+	lintTool := mcp.NewTool("netsuite_lint_suiteql",
+		mcp.WithDescription("Check a SuiteQL query against the columns of its FROM table without running it, flagging unknown columns and comparisons with literals of the wrong type"),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("The SuiteQL query to check"),
+		),
+	)
+
+	// Add SuiteQL lint tool handler
+	s.AddTool(lintTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleLintSuiteQL(client, config, request)
+	})
+
+	// Add NetSuite list records tool
+	listTool := mcp.NewTool("netsuite_list_records",
+		mcp.WithDescription("List the records of a NetSuite record type, optionally filtered with the REST record query language, with the requested fields of each. NetSuite cannot sort record listings, so sort applies within the returned page; use netsuite_run_suiteql to sort across all records"),
+		mcp.WithString("record_type",
+			mcp.Required(),
+			mcp.Description("The NetSuite record type to list (e.g., 'customer', 'salesorder')"),
+		),
+		mcp.WithString("filter",
+			mcp.Description("Optional filter in the REST record query language (e.g., 'email START_WITH \"barbara\"'). If not provided, all records are listed."),
+		),
+		mcp.WithBoolean("exclude_inactive",
+			mcp.Description("Only list active records, when the record type has an isinactive field (default: false)"),
+		),
+		mcp.WithArray("fields",
+			mcp.Description(fmt.Sprintf("Fields to return for each record (e.g., ['companyName', 'email']), fetched record by record. At most %d records are listed per call with fields. If not provided, only the IDs are returned.", maxBulkRecords)),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("sort",
+			mcp.Description("Field to sort the records of the page by (default: 'id'). Fields other than id must also be listed in fields."),
+		),
+		mcp.WithString("sort_order",
+			mcp.Description("Sort order (default: 'asc')"),
+			mcp.Enum(sortOrderAsc, sortOrderDesc),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of records to return (default: %d, max: %d)", client.DefaultLimit(), netsuite.MaxLimit)),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Number of records to skip (default: 0)"),
+		),
+	)
+
+	// Add list records tool handler
+	s.AddTool(listTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleListRecords(ctx, client, config, request)
+	})
+
+	// Add NetSuite record tool
+	recordTool := mcp.NewTool("netsuite_get_record",
+		mcp.WithDescription("Get a single NetSuite record by its internal ID"),
+		mcp.WithString("record_type",
+			mcp.Required(),
+			mcp.Description("The NetSuite record type of the record (e.g., 'customer', 'salesorder')"),
+		),
+		mcp.WithString("id",
+			mcp.Required(),
+			mcp.Description("The internal ID of the record"),
+		),
+		mcp.WithNumber("expand_depth",
+			mcp.Description(fmt.Sprintf("How many levels of sub-resources (sublists, subrecords) to expand (default: 1, max: %d). Use 0 to only return links to them.", netsuite.MaxExpandDepth)),
+		),
+		mcp.WithObject("headers",
+			mcp.Description("Optional extra HTTP headers to send to NetSuite with this call, to toggle NetSuite features (e.g., {'X-NetSuite-PropertyNameValidation': 'Warning'})"),
+		),
+	)
+
+	// Add record tool handler
+	s.AddTool(recordTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleGetRecord(ctx, client, config, request)
+	})
+
+	// Add NetSuite bulk record tool
+	bulkRecordTool := mcp.NewTool("netsuite_get_records_bulk",
+		mcp.WithDescription(fmt.Sprintf("Get up to %d NetSuite records of one record type by their internal IDs in a single call", maxBulkRecords)),
+		mcp.WithString("record_type",
+			mcp.Required(),
+			mcp.Description("The NetSuite record type of the records (e.g., 'customer', 'salesorder')"),
+		),
+		mcp.WithArray("ids",
+			mcp.Required(),
+			mcp.Description("The internal IDs of the records"),
+		),
+		mcp.WithNumber("expand_depth",
+			mcp.Description(fmt.Sprintf("How many levels of sub-resources (sublists, subrecords) to expand (default: 0, max: %d). Use 0 to only return links to them.", netsuite.MaxExpandDepth)),
+		),
+	)
+
+	// Add bulk record tool handler
+	s.AddTool(bulkRecordTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleGetRecordsBulk(ctx, client, config, request)
+	})
+
+	// Add NetSuite sublist tool
+	sublistTool := mcp.NewTool("netsuite_get_sublist",
+		mcp.WithDescription("Get a page of the lines of a record's sublist, such as the items of a sales order, without fetching the whole record"),
+		mcp.WithString("record_type",
+			mcp.Required(),
+			mcp.Description("The NetSuite record type (e.g., 'salesorder', 'invoice')"),
+		),
+		mcp.WithString("id",
+			mcp.Required(),
+			mcp.Description("The internal ID of the record"),
+		),
+		mcp.WithString("sublist",
+			mcp.Required(),
+			mcp.Description("The name of the sublist (e.g., 'item')"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of lines to return (default: %d, max: %d)", client.DefaultLimit(), netsuite.MaxLimit)),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Number of lines to skip for pagination (default: 0)"),
+		),
+	)
+
+	// Add sublist tool handler
+	s.AddTool(sublistTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleGetSubList(ctx, client, config, request)
+	})
+
+	// Add NetSuite flattened sublist tool
+	flattenedSublistTool := mcp.NewTool("netsuite_get_sublist_flattened",
+		mcp.WithDescription("Get a page of the lines of a record's sublist with chosen fields of the record copied onto every line, so that lines such as transaction lines carry their parent's context"),
+		mcp.WithString("record_type",
+			mcp.Required(),
+			mcp.Description("The NetSuite record type (e.g., 'salesorder', 'invoice')"),
+		),
+		mcp.WithString("id",
+			mcp.Required(),
+			mcp.Description("The internal ID of the record"),
+		),
+		mcp.WithString("sublist",
+			mcp.Required(),
+			mcp.Description("The name of the sublist (e.g., 'item')"),
+		),
+		mcp.WithArray("parent_fields",
+			mcp.Description("The fields of the record to copy onto every line as parent_<field> (e.g., ['tranId', 'entity', 'tranDate']). The record's ID is always copied as parent_id"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of lines to return (default: %d, max: %d)", client.DefaultLimit(), netsuite.MaxLimit)),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Number of lines to skip for pagination (default: 0)"),
+		),
+	)
+
+	// Add flattened sublist tool handler
+	s.AddTool(flattenedSublistTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleGetSubListFlattened(ctx, client, config, request)
+	})
+
+	// Add NetSuite file download tool
+	downloadFileTool := mcp.NewTool("netsuite_download_file",
+		mcp.WithDescription("Download a file from the File Cabinet by internal ID, such as a file attached to a record. Text files are returned as text and other files base64-encoded"),
+		mcp.WithNumber("file_id",
+			mcp.Required(),
+			mcp.Description("The internal ID of the file"),
+		),
+	)
+
+	// Add file download tool handler
+	s.AddTool(downloadFileTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleDownloadFile(ctx, client, config, request)
+	})
+
+	// Add NetSuite record validation tool
+	validateTool := mcp.NewTool("netsuite_validate_record",
+		mcp.WithDescription("Validate a record payload against the schema of its record type without sending it to NetSuite"),
+		mcp.WithString("record_type",
+			mcp.Required(),
+			mcp.Description("The NetSuite record type of the payload (e.g., 'customer', 'salesorder')"),
+		),
+		mcp.WithObject("record",
+			mcp.Required(),
+			mcp.Description("The record payload to validate, as it would be sent to NetSuite"),
+		),
+	)
+
+	// Add record validation tool handler
+	s.AddTool(validateTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleValidateRecord(client, config, request)
+	})
+
+	// Add NetSuite example record tool
+	exampleTool := mcp.NewTool("netsuite_example_record",
+		mcp.WithDescription("Get an example payload of a NetSuite record type, generated from its schema, to use as a template when creating records"),
+		mcp.WithString("record_type",
+			mcp.Required(),
+			mcp.Description("The NetSuite record type to get an example of (e.g., 'customer', 'salesorder')"),
+		),
+		mcp.WithBoolean("required_only",
+			mcp.Description("Only fill in the fields the schema marks as required (default: false)"),
+		),
+	)
+
+	// Add example record tool handler
+	s.AddTool(exampleTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleExampleRecord(client, config, request)
+	})
+
+	// Add NetSuite report tool
+	reports := reportTemplates(config.Reports)
+	reportTool := mcp.NewTool("netsuite_run_report",
+		mcp.WithDescription("Run a predefined SuiteQL report by name, without writing SuiteQL. Available reports and their parameters:"+describeReports(reports)),
+		mcp.WithString("report",
+			mcp.Required(),
+			mcp.Description("The name of the report to run"),
+		),
+		mcp.WithObject("parameters",
+			mcp.Description("Values of the report's parameters, by name"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of rows to return (default: %d, max: %d)", client.DefaultLimit(), netsuite.MaxLimit)),
+		),
+	)
+
+	// Add report tool handler
+	s.AddTool(reportTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleRunReport(ctx, client, config, reports, request)
+	})
+
+	// Add NetSuite display value tool
+	displayValueTool := mcp.NewTool("netsuite_display_value_expression",
+		mcp.WithDescription("Get the SuiteQL BUILTIN.DF expression that selects the human-readable display value of a select field instead of its internal ID"),
+		mcp.WithString("record_type",
+			mcp.Required(),
+			mcp.Description("The NetSuite record type the field belongs to (e.g., 'transaction')"),
+		),
+		mcp.WithString("field",
+			mcp.Required(),
+			mcp.Description("The select field to get the display value of (e.g., 'entity', 'status')"),
+		),
+	)
+
+	// Add display value tool handler
+	s.AddTool(displayValueTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleDisplayValueExpression(client, config, request)
+	})
+
+	// Add NetSuite bulk metadata tool
+	bulkMetadataTool := mcp.NewTool("netsuite_get_metadata_bulk",
+		mcp.WithDescription("Get metadata (schema) for several NetSuite record types at once"),
+		mcp.WithArray("record_types",
+			mcp.Required(),
+			mcp.Description("The NetSuite record types to get metadata for (e.g., ['customer', 'contact', 'salesorder'])"),
+		),
+	)
+
+	// Add bulk metadata tool handler
+	s.AddTool(bulkMetadataTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleGetMetadataBulk(client, config, request)
+	})
+
+	// Add NetSuite preferences tool
+	preferencesTool := mcp.NewTool("netsuite_get_preferences",
+		mcp.WithDescription("Get the NetSuite company preferences that affect how values are formatted: base currency, date format, and time zone"),
+	)
+
+	// Add preferences tool handler
+	s.AddTool(preferencesTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleGetPreferences(ctx, client, config)
+	})
+
+	// Add NetSuite system information tool
+	systemInfoTopics := make([]string, 0, len(netsuite.SystemInfoTopics()))
+	for _, topic := range netsuite.SystemInfoTopics() {
+		systemInfoTopics = append(systemInfoTopics, string(topic))
+	}
+	systemInfoTool := mcp.NewTool("netsuite_get_system_info",
+		mcp.WithDescription("Get information about the NetSuite account itself, such as its roles, subsidiaries, currencies, departments, locations, and classes. Topics whose feature is disabled are reported as not available. Enabled features and installed SuiteApps are not supported, since SuiteQL has no table for them"),
+		mcp.WithArray("topics",
+			mcp.Description("The topics to get, any of: "+strings.Join(systemInfoTopics, ", ")+" (default: all)"),
+		),
+	)
+
+	// Add system information tool handler
+	s.AddTool(systemInfoTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleGetSystemInfo(ctx, client, config, request)
+	})
+
+	// Add NetSuite preview tool
+	previewTool := mcp.NewTool("netsuite_preview_record",
+		mcp.WithDescription("Peek at the first rows of a NetSuite record type along with its inferred columns, without writing a query"),
+		mcp.WithString("record_type",
+			mcp.Required(),
+			mcp.Description("The NetSuite record type (SuiteQL table) to preview (e.g., 'customer')"),
+		),
+		mcp.WithNumber("n",
+			mcp.Description("Number of rows to return (default: 5, max: 100)"),
+		),
+	)
+
+	// Add preview tool handler
+	s.AddTool(previewTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handlePreviewRecord(ctx, client, config, request)
+	})
+
+	// Add NetSuite describe table tool
+	describeTableTool := mcp.NewTool("netsuite_describe_table",
+		mcp.WithDescription("Get the SuiteQL column names and inferred types of a table by sampling its rows, for tables whose name differs from the metadata catalog's record type"),
+		mcp.WithString("table",
+			mcp.Required(),
+			mcp.Description("The SuiteQL table name (e.g., 'transactionline')"),
+		),
+	)
+
+	// Add describe table tool handler
+	s.AddTool(describeTableTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleDescribeTable(ctx, client, config, request)
+	})
+
+	// Add NetSuite field catalog tool
+	fieldCatalogTool := mcp.NewTool("netsuite_field_catalog",
+		mcp.WithDescription("Get the SuiteQL column names and types of one or more NetSuite record types, to write queries without guessing field names"),
+		mcp.WithArray("record_types",
+			mcp.Required(),
+			mcp.Description("The NetSuite record types to list the columns of (e.g., ['customer', 'salesorder'])"),
+		),
+	)
+
+	// Add field catalog tool handler
+	s.AddTool(fieldCatalogTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleFieldCatalog(client, config, request)
+	})
+
+	// Add NetSuite field options tool
+	fieldOptionsTool := mcp.NewTool("netsuite_list_field_options",
+		mcp.WithDescription("List the internal IDs and labels a select field of a NetSuite record type can hold"),
+		mcp.WithString("record_type",
+			mcp.Required(),
+			mcp.Description("The NetSuite record type (e.g., 'customer', 'salesorder')"),
+		),
+		mcp.WithString("field",
+			mcp.Required(),
+			mcp.Description("The select field to list the options of (e.g., 'subsidiary', 'terms')"),
+		),
+	)
+
+	// Add field options tool handler
+	s.AddTool(fieldOptionsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleListFieldOptions(ctx, client, config, request)
+	})
+
+	if config.ExportDir != "" {
+		// Add NetSuite export tool
+		exportTool := mcp.NewTool("netsuite_export_suiteql",
+			mcp.WithDescription("Execute a SuiteQL query and write all of its rows to a file in the export directory, returning only a summary. Use this for result sets too large to return directly"),
+			mcp.WithString("query",
+				mcp.Required(),
+				mcp.Description("The SuiteQL query to execute"),
+			),
+			mcp.WithString("path",
+				mcp.Required(),
+				mcp.Description("The file to write, relative to the export directory. It must not exist yet"),
+			),
+			mcp.WithString("format",
+				mcp.Description("The file format (default: ndjson)"),
+				mcp.Enum(formatNDJSON, formatCSV),
+			),
+			mcp.WithNumber("max_field_length",
+				mcp.Description("Truncate string values longer than this many characters, appending a marker with the original length (default: 0, no truncation)"),
+			),
+		)
+
+		// Add export tool handler
+		s.AddTool(exportTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleExportSuiteQL(ctx, client, config, request)
+		})
+	}
+
+	if config.EnableMutations {
+		// Add NetSuite upsert tool
+		upsertTool := mcp.NewTool("netsuite_upsert_record",
+			mcp.WithDescription("Create a NetSuite record with an external ID, or replace it if a record with that external ID already exists. This writes to NetSuite."),
+			mcp.WithString("record_type",
+				mcp.Required(),
+				mcp.Description("The NetSuite record type to write (e.g., 'customer', 'salesorder')"),
+			),
+			mcp.WithString("external_id",
+				mcp.Required(),
+				mcp.Description("The external ID identifying the record, typically its ID in the system being synced from"),
+			),
+			mcp.WithObject("record",
+				mcp.Required(),
+				mcp.Description("The record payload to send to NetSuite"),
+			),
+			mcp.WithBoolean("async",
+				mcp.Description("Have NetSuite process the write as an asynchronous job and return its job_id at once, for writes that may exceed the request timeout. Poll netsuite_get_job_status for the outcome (default: false)"),
+			),
+			mcp.WithNumber("subsidiary",
+				mcp.Description("Internal ID of the subsidiary to write the record for, when the record type has a subsidiary field and the payload does not set it. Overrides NETSUITE_SUBSIDIARY"),
+			),
+		)
+
+		// Add upsert tool handler
+		s.AddTool(upsertTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleUpsertRecord(ctx, client, config, request)
+		})
+
+		// Add NetSuite transform tool
+		transformTool := mcp.NewTool("netsuite_transform_record",
+			mcp.WithDescription("Create a record from an existing one, such as an invoice from a sales order or an item receipt from a purchase order, and return the new record's internal ID. This writes to NetSuite."),
+			mcp.WithString("from_type",
+				mcp.Required(),
+				mcp.Description("The record type of the existing record (e.g., 'salesorder')"),
+			),
+			mcp.WithString("id",
+				mcp.Required(),
+				mcp.Description("The internal ID of the existing record"),
+			),
+			mcp.WithString("to_type",
+				mcp.Required(),
+				mcp.Description("The record type to create (e.g., 'invoice')"),
+			),
+			mcp.WithObject("record",
+				mcp.Description("Optional fields to set on the new record"),
+			),
+			mcp.WithBoolean("async",
+				mcp.Description("Have NetSuite process the write as an asynchronous job and return its job_id at once, for writes that may exceed the request timeout. Poll netsuite_get_job_status for the outcome (default: false)"),
+			),
+		)
+
+		// Add transform tool handler
+		s.AddTool(transformTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleTransformRecord(ctx, client, config, request)
+		})
+
+		// Add NetSuite bulk create tool
+		createRecordsTool := mcp.NewTool("netsuite_create_records",
+			mcp.WithDescription(fmt.Sprintf("Create up to %d NetSuite records of one record type, validating each against the schema first and reporting the outcome per record. This writes to NetSuite.", maxCreateRecords)),
+			mcp.WithString("record_type",
+				mcp.Required(),
+				mcp.Description("The NetSuite record type to create (e.g., 'customer', 'salesorder')"),
+			),
+			mcp.WithArray("records",
+				mcp.Required(),
+				mcp.Description("The record payloads to send to NetSuite, as JSON objects"),
+			),
+			mcp.WithBoolean("async",
+				mcp.Description("Have NetSuite create each record in an asynchronous job and return the job IDs at once. Poll netsuite_get_job_status for the outcomes (default: false)"),
+			),
+			mcp.WithNumber("subsidiary",
+				mcp.Description("Internal ID of the subsidiary to write the records for, when the record type has a subsidiary field and the payload does not set it. Overrides NETSUITE_SUBSIDIARY"),
+			),
+		)
+
+		// Add bulk create tool handler
+		s.AddTool(createRecordsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleCreateRecords(ctx, client, config, request)
+		})
+
+		// Add NetSuite job status tool
+		jobStatusTool := mcp.NewTool("netsuite_get_job_status",
+			mcp.WithDescription("Get the progress of an asynchronous NetSuite job started by a write with async set, and the outcome of the write once it completed"),
+			mcp.WithString("job_id",
+				mcp.Required(),
+				mcp.Description("The job_id returned by the write"),
+			),
+		)
+
+		// Add job status tool handler
+		s.AddTool(jobStatusTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleGetJobStatus(ctx, client, config, request)
+		})
+	}
+
+	return s
+}
+
+// handleGetMetadata handles the netsuite_get_metadata tool request
+func handleGetMetadata(ctx context.Context, client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get record type from arguments
+	recordType, err := request.RequireString("record_type")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid record_type parameter: %v", err)), nil
+	}
+
+	// Get optional included fields
+	var includedFields []string
+	args := request.GetArguments()
+	if fieldsArg, exists := args["included_fields"]; exists {
+		if fieldsArray, ok := fieldsArg.([]interface{}); ok {
+			for _, field := range fieldsArray {
+				if fieldStr, ok := field.(string); ok {
+					includedFields = append(includedFields, fieldStr)
+				}
+			}
+		}
+	}
+
+	// Get metadata from NetSuite
+	var metadata *jsonschematree.Schema
+	if request.GetBool("resolve_references", false) {
+		metadata, err = client.ResolvedMetadata(recordType)
+	} else {
+		metadata, err = client.Metadata(recordType, includedFields)
+	}
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get metadata for record type '%s': %v", recordType, err)), nil
+	}
+
+	// Create a structured response
+	summary, warnings := generateMetadataSummary(metadata)
+	response := map[string]interface{}{
+		"record_type":      recordType,
+		"included_fields":  includedFields,
+		"metadata_summary": summary,
+		"warnings":         warnings,
+	}
+
+	// The hash changes whenever the schema does, e.g. to detect new custom
+	// fields since the last sync
+	if metadata != nil {
+		hash, err := metadata.Hash()
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Failed to hash the schema: %v", err))
+		} else {
+			response["schema_hash"] = hash
+		}
+	}
+
+	// Archived records are returned unless isinactive is filtered on
+	inactiveFilterable := false
+	if metadata != nil {
+		for property := range metadata.Properties {
+			if strings.EqualFold(property, "isinactive") {
+				inactiveFilterable = true
+				break
+			}
+		}
+	}
+	response["inactive_filterable"] = inactiveFilterable
+	if inactiveFilterable {
+		warnings = append(warnings, fmt.Sprintf("Record type '%s' has inactive records; add WHERE isinactive = 'F' to SuiteQL queries, or pass exclude_inactive to netsuite_list_records and netsuite_count_records, to leave them out", recordType))
+	}
+
+	// Custom field IDs such as custbody_foo say little about the field, so
+	// give them their labels
+	if metadata != nil {
+		customFields, err := client.LabelCustomFields(ctx, metadata.PropertyNames())
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Unable to label custom fields: %v", err))
+		} else if len(customFields) > 0 {
+			response["custom_fields"] = customFields
+		}
+	}
+	response["warnings"] = warnings
+
+	if request.GetBool("flat", false) && metadata != nil {
+		response["metadata_fields"] = jsonschematree.Flatten(metadata)
+	} else {
+		response["metadata_schema"] = metadata
+	}
+
+	result := newStreamedToolResultJSON(response, config.PrettyOutput)
+	if !result.IsError {
+		// The nested schema is left out of the structured content, which
+		// would otherwise encode and send it a second time
+		structured := maps.Clone(response)
+		delete(structured, "metadata_schema")
+		result.StructuredContent = structured
+	}
+
+	return result, nil
+}
+
+// handleGetMetadataBulk handles the netsuite_get_metadata_bulk tool request
+func handleGetMetadataBulk(client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get record types from arguments
+	recordTypes, err := request.RequireStringSlice("record_types")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid record_types parameter: %v", err)), nil
+	}
+
+	// Fetch metadata concurrently; the client caps the requests in flight
+	schemas := make(map[string]*jsonschematree.Schema)
+	failures := make(map[string]string)
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for _, recordType := range recordTypes {
+		wg.Add(1)
+		go func(recordType string) {
+			defer wg.Done()
+
+			metadata, err := client.Metadata(recordType, nil)
+			if err == nil && metadata == nil {
+				err = errors.New("no metadata found")
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				failures[recordType] = err.Error()
+				return
+			}
+			schemas[recordType] = metadata
+		}(recordType)
+	}
+	wg.Wait()
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"record_types": recordTypes,
+		"schemas":      schemas,
+		"errors":       failures,
+	}
+
+	return newStreamedToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// handleDescribeTable handles the netsuite_describe_table tool request
+func handleDescribeTable(ctx context.Context, client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get table from arguments
+	table, err := request.RequireString("table")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid table parameter: %v", err)), nil
+	}
+
+	// Describe the table in NetSuite
+	columns, err := client.DescribeTable(ctx, table)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to describe table '%s': %v", table, err)), nil
+	}
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"table":    table,
+		"columns":  columns,
+		"warnings": []string{"Columns are inferred from sampled rows; columns that are always null are not listed"},
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// handleListFieldOptions handles the netsuite_list_field_options tool request
+func handleListFieldOptions(ctx context.Context, client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get record type and field from arguments
+	recordType, err := request.RequireString("record_type")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid record_type parameter: %v", err)), nil
+	}
+
+	field, err := request.RequireString("field")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid field parameter: %v", err)), nil
+	}
+
+	// List the options in NetSuite
+	options, err := client.ListOptions(ctx, recordType, field)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to list options of field '%s': %v", field, err)), nil
+	}
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"record_type": recordType,
+		"field":       field,
+		"options":     options,
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// handleFieldCatalog handles the netsuite_field_catalog tool request
+func handleFieldCatalog(client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get record types from arguments
+	recordTypes, err := request.RequireStringSlice("record_types")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid record_types parameter: %v", err)), nil
+	}
+
+	// Build the catalogs concurrently; the client caps the requests in flight
+	catalog := make(map[string][]netsuite.Column)
+	failures := make(map[string]string)
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for _, recordType := range recordTypes {
+		wg.Add(1)
+		go func(recordType string) {
+			defer wg.Done()
+
+			columns, err := client.FieldCatalog(recordType)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				failures[recordType] = err.Error()
+				return
+			}
+			catalog[recordType] = columns
+		}(recordType)
+	}
+	wg.Wait()
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"record_types": recordTypes,
+		"catalog":      catalog,
+		"errors":       failures,
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// withRequestHeaders returns a context carrying the extra headers given in
+// the "headers" argument, if any.
+func withRequestHeaders(ctx context.Context, request mcp.CallToolRequest) (context.Context, error) {
+	headersArg, ok := request.GetArguments()["headers"].(map[string]interface{})
+	if !ok || len(headersArg) == 0 {
+		return ctx, nil
+	}
+
+	header := make(http.Header, len(headersArg))
+	for name, value := range headersArg {
+		valueString, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("value of header %s is not a string", name)
+		}
+		header.Set(name, valueString)
+	}
+
+	if err := netsuite.ValidateHeaders(header); err != nil {
+		return nil, err
+	}
+
+	return netsuite.WithHeaders(ctx, header), nil
+}
+
+// newToolResultJSON marshals a tool response into a text result. Responses are
+// compact unless pretty output is enabled for debugging. Object responses
+// always carry a "warnings" array, empty when there is nothing to report, and
+// their size in "diagnostics".
+func newToolResultJSON(response interface{}, pretty bool) *mcp.CallToolResult {
+	responseMap, isMap := response.(map[string]interface{})
+	var diagnostics map[string]interface{}
+	if isMap {
+		if warnings, _ := responseMap["warnings"].([]string); warnings == nil {
+			responseMap["warnings"] = []string{}
+		}
+		diagnostics = takeDiagnostics(responseMap)
+	}
+
+	var responseJSON []byte
+	var err error
+	if pretty {
+		responseJSON, err = json.MarshalIndent(response, "", "  ")
+	} else {
+		responseJSON, err = json.Marshal(response)
+	}
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response to JSON: %v", err))
+	}
+
+	if isMap {
+		responseJSON = addDiagnostics(responseMap, diagnostics, responseJSON, pretty)
+	}
+
+	return mcp.NewToolResultText(string(responseJSON))
+}
+
+// clampParameter bounds the value of a numeric parameter to [lower, upper],
+// adding a warning to warnings when the given value was out of range.
+func clampParameter(warnings []string, parameter string, value int, lower int, upper int) (int, []string) {
+	clamped := max(lower, min(value, upper))
+	if clamped != value {
+		warnings = append(warnings, fmt.Sprintf("%s %d is out of range, so %d was used instead", parameter, value, clamped))
+	}
+
+	return clamped, warnings
+}
+
+// generateMetadataSummary creates a human-readable summary of the metadata
+func generateMetadataSummary(metadata interface{}) (map[string]interface{}, []string) {
+	var warnings []string
+	summary := map[string]interface{}{
+		"description": "NetSuite record metadata schema",
+	}
+
+	// List the first fields in declaration order
+	if schema, ok := metadata.(*jsonschematree.Schema); ok && schema != nil {
+		fieldNames := schema.PropertyNames()
+		summary["total_fields"] = len(fieldNames)
+		summary["sample_fields"] = fieldNames[:min(10, len(fieldNames))]
+		if len(fieldNames) > 10 {
+			warnings = append(warnings, fmt.Sprintf("The summary shows the first 10 fields out of %d total fields", len(fieldNames)))
+		}
+		summary["schema_type"] = schema.Type
+
+		return summary, warnings
+	}
+
+	// Try to extract useful information from the metadata structure
+	if metadataMap, ok := metadata.(map[string]interface{}); ok {
+		if properties, exists := metadataMap["properties"]; exists {
+			if propsMap, ok := properties.(map[string]interface{}); ok {
+				fieldCount := len(propsMap)
+				summary["total_fields"] = fieldCount
+
+				// List first few field names as examples
+				fieldNames := make([]string, 0, 10)
+				count := 0
+				for fieldName := range propsMap {
+					if count >= 10 {
+						break
+					}
+					fieldNames = append(fieldNames, fieldName)
+					count++
+				}
+				summary["sample_fields"] = fieldNames
+				if fieldCount > 10 {
+					warnings = append(warnings, fmt.Sprintf("The summary shows the first 10 fields out of %d total fields", fieldCount))
+				}
+			}
+		}
+
+		if schemaType, exists := metadataMap["type"]; exists {
+			summary["schema_type"] = schemaType
+		}
+	}
+
+	return summary, warnings
+}
+
+// handleDescribeRelationships handles the netsuite_describe_relationships tool request
+func handleDescribeRelationships(client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get record type from arguments
+	recordType, err := request.RequireString("record_type")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid record_type parameter: %v", err)), nil
+	}
+
+	// Get the reference fields from NetSuite, resolved to their targets
+	references, err := client.References(recordType)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get references of record type '%s': %v", recordType, err)), nil
+	}
+
+	// Group the reference fields by their target record type
+	adjacency := make(map[string][]string)
+	for _, reference := range references {
+		adjacency[reference.Target] = append(adjacency[reference.Target], reference.Path)
+	}
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"record_type":   recordType,
+		"relationships": references,
+		"adjacency":     adjacency,
+		"summary": map[string]interface{}{
+			"total_references": len(references),
+			"total_targets":    len(adjacency),
+		},
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// handleGetChanges handles the netsuite_get_changes tool request
+func handleGetChanges(ctx context.Context, client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get record type and timestamp from arguments
+	recordType, err := request.RequireString("record_type")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid record_type parameter: %v", err)), nil
+	}
+
+	sinceArg, err := request.RequireString("since")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid since parameter: %v", err)), nil
+	}
+
+	since, err := time.Parse(netsuite.SinceLayout, sinceArg)
+	if err != nil {
+		since, err = time.Parse(time.DateOnly, sinceArg)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid since parameter: expected 'YYYY-MM-DD HH:MM:SS' or 'YYYY-MM-DD', got '%s'", sinceArg)), nil
+		}
+	}
+
+	afterID, warnings := clampParameter(nil, "after_id", request.GetInt("after_id", 0), 0, math.MaxInt)
+	limit, warnings := clampParameter(warnings, "limit", request.GetInt("limit", client.DefaultLimit()), 1, netsuite.MaxLimit)
+
+	// Get changed records from NetSuite
+	changes, err := client.ChangedSince(ctx, recordType, since, afterID, limit)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get changes for record type '%s': %v", recordType, err)), nil
+	}
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"record_type":        recordType,
+		"since":              since.Format(netsuite.SinceLayout),
+		"high_water_mark":    changes.HighWaterMark,
+		"high_water_mark_id": changes.HighWaterMarkID,
+		"count":              changes.Count,
+		"hasMore":            changes.HasMore,
+		"items":              changes.Items,
+		"warnings":           warnings,
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// excludeInactive narrows the filter to active records when exclude_inactive
+// is set, or returns a warning when the record type cannot be filtered so.
+func excludeInactive(client *netsuite.Client, request mcp.CallToolRequest, recordType string, filter string) (string, []string, error) {
+	if !request.GetBool("exclude_inactive", false) {
+		return filter, nil, nil
+	}
+
+	filter, excluded, err := client.ExcludeInactive(recordType, filter)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if !excluded {
+		return filter, []string{fmt.Sprintf("Record type '%s' has no isinactive field, so exclude_inactive was ignored", recordType)}, nil
+	}
+
+	return filter, nil, nil
+}
+
+// handleCountRecords handles the netsuite_count_records tool request
+func handleCountRecords(ctx context.Context, client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get record type and optional filter from arguments
+	recordType, err := request.RequireString("record_type")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid record_type parameter: %v", err)), nil
+	}
+
+	filter, warnings, err := excludeInactive(client, request, recordType, request.GetString("filter", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to exclude inactive records of record type '%s': %v", recordType, err)), nil
+	}
+
+	// Count records in NetSuite
+	count, err := client.CountRecords(ctx, recordType, filter)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to count records of type '%s': %v", recordType, err)), nil
+	}
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"record_type": recordType,
+		"filter":      filter,
+		"count":       count,
+		"warnings":    warnings,
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// handleEstimateCost handles the netsuite_estimate_cost tool request
+func handleEstimateCost(ctx context.Context, client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get query from arguments
+	query, err := request.RequireString("query")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid query parameter: %v", err)), nil
+	}
+
+	// Estimate the cost of the query in NetSuite
+	estimate, err := client.EstimateCost(ctx, query)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to estimate SuiteQL query cost: %v", err)), nil
+	}
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"query":    query,
+		"estimate": estimate,
+		"warnings": estimate.Warnings,
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// handleExportSchema handles the netsuite_export_schema tool request
+func handleExportSchema(client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get record type from arguments
+	recordType, err := request.RequireString("record_type")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid record_type parameter: %v", err)), nil
+	}
+
+	// Normalize the schema from NetSuite
+	schema, err := client.CodegenSchema(recordType)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to export schema for record type '%s': %v", recordType, err)), nil
+	}
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"record_type": recordType,
+		"schema":      schema,
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// handleDescribeCustomFields handles the netsuite_describe_custom_fields tool request
+func handleDescribeCustomFields(ctx context.Context, client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get optional record type and field IDs from arguments
+	recordType := request.GetString("record_type", "")
+	fieldIDs := request.GetStringSlice("field_ids", nil)
+
+	if recordType != "" {
+		metadata, err := client.Metadata(recordType, nil)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to get metadata for record type '%s': %v", recordType, err)), nil
+		}
+		if metadata == nil {
+			return mcp.NewToolResultError(fmt.Sprintf("No metadata found for record type '%s'", recordType)), nil
+		}
+
+		for _, property := range metadata.PropertyNames() {
+			if netsuite.IsCustomField(property) {
+				fieldIDs = append(fieldIDs, property)
+			}
+		}
+	}
+
+	// Get custom field definitions from NetSuite
+	var customFields map[string]netsuite.CustomField
+	var err error
+	if recordType != "" || len(fieldIDs) > 0 {
+		customFields, err = client.LabelCustomFields(ctx, fieldIDs)
+	} else {
+		customFields, err = client.CustomFields(ctx)
+	}
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to describe custom fields: %v", err)), nil
+	}
+
+	var warnings []string
+	for _, fieldID := range fieldIDs {
+		if _, ok := customFields[fieldID]; !ok {
+			warnings = append(warnings, fmt.Sprintf("No definition found for custom field '%s'", fieldID))
+		}
+	}
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"record_type":   recordType,
+		"count":         len(customFields),
+		"custom_fields": customFields,
+		"warnings":      warnings,
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// handleLintSuiteQL handles the netsuite_lint_suiteql tool request
+func handleLintSuiteQL(client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get query from arguments
+	query, err := request.RequireString("query")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid query parameter: %v", err)), nil
+	}
+
+	// Check the query against the columns of its table
+	result, err := client.LintSuiteQL(query)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to lint SuiteQL query: %v", err)), nil
+	}
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"table":    result.Table,
+		"valid":    len(result.Issues) == 0,
+		"issues":   result.Issues,
+		"warnings": result.Warnings,
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// handleListRecords handles the netsuite_list_records tool request
+func handleListRecords(ctx context.Context, client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get record type, filter, and paging from arguments
+	recordType, err := request.RequireString("record_type")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid record_type parameter: %v", err)), nil
+	}
+
+	filter, warnings, err := excludeInactive(client, request, recordType, request.GetString("filter", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to exclude inactive records of record type '%s': %v", recordType, err)), nil
+	}
+
+	fields := request.GetStringSlice("fields", nil)
+	sortField := request.GetString("sort", "id")
+	if sortField != "id" && !slices.Contains(fields, sortField) {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid sort parameter: field '%s' must also be listed in fields", sortField)), nil
+	}
+
+	sortOrder := request.GetString("sort_order", sortOrderAsc)
+	if sortOrder != sortOrderAsc && sortOrder != sortOrderDesc {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid sort_order parameter: expected '%s' or '%s', got '%s'", sortOrderAsc, sortOrderDesc, sortOrder)), nil
+	}
+
+	// Every record is fetched when fields are requested
+	maxLimit := netsuite.MaxLimit
+	if len(fields) > 0 {
+		maxLimit = maxBulkRecords
+	}
+	limit, warnings := clampParameter(warnings, "limit", request.GetInt("limit", client.DefaultLimit()), 1, maxLimit)
+	offset, warnings := clampParameter(warnings, "offset", request.GetInt("offset", 0), 0, math.MaxInt)
+
+	// List records in NetSuite
+	results, err := client.ListRecords(ctx, recordType, filter, limit, offset)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to list records of type '%s': %v", recordType, err)), nil
+	}
+
+	// Only the record IDs are kept from the listing, since the links can be
+	// derived from them
+	ids := make([]string, 0, len(results.Items))
+	for _, item := range results.Items {
+		var reference struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(item, &reference); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to parse record reference: %v", err)), nil
+		}
+		ids = append(ids, reference.ID)
+	}
+
+	records, failures := listedRecords(ctx, client, recordType, ids, fields)
+	sortRecords(records, sortField, sortOrder)
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"record_type":   recordType,
+		"filter":        filter,
+		"sort":          sortField,
+		"sort_order":    sortOrder,
+		"limit":         limit,
+		"default_limit": client.DefaultLimit(),
+		"offset":        offset,
+		"count":         results.Count,
+		"totalResults":  results.TotalResults,
+		"hasMore":       results.HasMore,
+		"records":       records,
+		"errors":        failures,
+		"warnings":      warnings,
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// handleGetRecord handles the netsuite_get_record tool request
+func handleGetRecord(ctx context.Context, client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get record type and ID from arguments
+	recordType, err := request.RequireString("record_type")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid record_type parameter: %v", err)), nil
+	}
+
+	id, err := request.RequireString("id")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid id parameter: %v", err)), nil
+	}
+
+	expandDepth, warnings := clampParameter(nil, "expand_depth", request.GetInt("expand_depth", 1), 0, netsuite.MaxExpandDepth)
+
+	ctx, err = withRequestHeaders(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid headers parameter: %v", err)), nil
+	}
+
+	// Get record from NetSuite
+	record, err := client.GetRecord(ctx, recordType, id, expandDepth)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get %s record '%s': %v", recordType, id, err)), nil
+	}
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"record_type":  recordType,
+		"id":           id,
+		"expand_depth": expandDepth,
+		"record":       record,
+		"warnings":     warnings,
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// maxBulkRecords is the number of records netsuite_get_records_bulk fetches
+// at most in one call.
+const maxBulkRecords = 100
+
+// handleGetRecordsBulk handles the netsuite_get_records_bulk tool request
+func handleGetRecordsBulk(ctx context.Context, client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get record type and IDs from arguments
+	recordType, err := request.RequireString("record_type")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid record_type parameter: %v", err)), nil
+	}
+
+	ids, err := request.RequireStringSlice("ids")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid ids parameter: %v", err)), nil
+	}
+	if len(ids) > maxBulkRecords {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid ids parameter: at most %d IDs can be fetched at once, got %d", maxBulkRecords, len(ids))), nil
+	}
+
+	expandDepth, warnings := clampParameter(nil, "expand_depth", request.GetInt("expand_depth", 0), 0, netsuite.MaxExpandDepth)
+
+	// Fetch the records concurrently; the client caps the requests in flight
+	records := make(map[string]map[string]interface{})
+	failures := make(map[string]string)
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for _, id := range slices.Compact(slices.Sorted(slices.Values(ids))) {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+
+			record, err := client.GetRecord(ctx, recordType, id, expandDepth)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				failures[id] = err.Error()
+				return
+			}
+			records[id] = record
+		}(id)
+	}
+	wg.Wait()
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"record_type":  recordType,
+		"expand_depth": expandDepth,
+		"records":      records,
+		"errors":       failures,
+		"warnings":     warnings,
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// handleGetSubList handles the netsuite_get_sublist tool request
+func handleGetSubList(ctx context.Context, client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get record type, ID, sublist, and paging from arguments
+	recordType, err := request.RequireString("record_type")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid record_type parameter: %v", err)), nil
+	}
+
+	id, err := request.RequireString("id")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid id parameter: %v", err)), nil
+	}
+
+	sublistName, err := request.RequireString("sublist")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid sublist parameter: %v", err)), nil
+	}
+
+	limit, warnings := clampParameter(nil, "limit", request.GetInt("limit", client.DefaultLimit()), 1, netsuite.MaxLimit)
+	offset, warnings := clampParameter(warnings, "offset", request.GetInt("offset", 0), 0, math.MaxInt)
+
+	// Get sublist from NetSuite
+	sublist, err := client.GetSubList(ctx, recordType, id, sublistName, limit, offset)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get sublist '%s' of %s record '%s': %v", sublistName, recordType, id, err)), nil
+	}
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"record_type":  recordType,
+		"id":           id,
+		"sublist":      sublistName,
+		"limit":        limit,
+		"offset":       offset,
+		"count":        sublist.Count,
+		"totalResults": sublist.TotalResults,
+		"hasMore":      sublist.HasMore,
+		"items":        sublist.Items,
+		"warnings":     warnings,
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// handleGetSubListFlattened handles the netsuite_get_sublist_flattened tool request
+func handleGetSubListFlattened(ctx context.Context, client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get record type, ID, sublist, parent fields, and paging from arguments
+	recordType, err := request.RequireString("record_type")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid record_type parameter: %v", err)), nil
+	}
+
+	id, err := request.RequireString("id")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid id parameter: %v", err)), nil
+	}
+
+	sublistName, err := request.RequireString("sublist")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid sublist parameter: %v", err)), nil
+	}
+
+	parentFields := request.GetStringSlice("parent_fields", []string{})
+
+	limit, warnings := clampParameter(nil, "limit", request.GetInt("limit", client.DefaultLimit()), 1, netsuite.MaxLimit)
+	offset, warnings := clampParameter(warnings, "offset", request.GetInt("offset", 0), 0, math.MaxInt)
+
+	// Get record and sublist from NetSuite
+	sublist, err := client.FlattenSubList(ctx, recordType, id, sublistName, parentFields, limit, offset)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get sublist '%s' of %s record '%s': %v", sublistName, recordType, id, err)), nil
+	}
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"record_type":   recordType,
+		"id":            id,
+		"sublist":       sublistName,
+		"parent_fields": parentFields,
+		"limit":         limit,
+		"offset":        offset,
+		"count":         sublist.Count,
+		"totalResults":  sublist.TotalResults,
+		"hasMore":       sublist.HasMore,
+		"items":         sublist.Items,
+		"warnings":      warnings,
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// handleValidateRecord handles the netsuite_validate_record tool request
+func handleValidateRecord(client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get record type and payload from arguments
+	recordType, err := request.RequireString("record_type")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid record_type parameter: %v", err)), nil
+	}
+
+	record, ok := request.GetArguments()["record"].(map[string]interface{})
+	if !ok {
+		return mcp.NewToolResultError("Invalid record parameter: expected a JSON object"), nil
+	}
+
+	// Get metadata from NetSuite
+	metadata, err := client.Metadata(recordType, nil)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get metadata for record type '%s': %v", recordType, err)), nil
+	}
+	if metadata == nil {
+		return mcp.NewToolResultError(fmt.Sprintf("No metadata found for record type '%s'", recordType)), nil
+	}
+
+	violations := metadata.Validate(record)
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"record_type": recordType,
+		"valid":       len(violations) == 0,
+		"violations":  violations,
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// handleExampleRecord handles the netsuite_example_record tool request
+func handleExampleRecord(client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get record type from arguments
+	recordType, err := request.RequireString("record_type")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid record_type parameter: %v", err)), nil
+	}
+
+	// Get metadata from NetSuite, resolved so that sublists and subrecords
+	// are filled in rather than mistaken for references
+	metadata, err := client.ResolvedMetadata(recordType)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get metadata for record type '%s': %v", recordType, err)), nil
+	}
+	if metadata == nil {
+		return mcp.NewToolResultError(fmt.Sprintf("No metadata found for record type '%s'", recordType)), nil
+	}
+
+	var example interface{}
+	if request.GetBool("required_only", false) {
+		example = jsonschematree.RequiredExample(metadata)
+	} else {
+		example = jsonschematree.Example(metadata)
+	}
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"record_type": recordType,
+		"example":     removeLinks(example),
+		"warnings":    []string{"Values are placeholders; replace them, and the internal IDs of referenced records, before writing"},
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// removeLinks removes the read-only links NetSuite adds to records and their
+// sub-resources from an example payload.
+func removeLinks(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		delete(value, "links")
+		for key, nested := range value {
+			value[key] = removeLinks(nested)
+		}
+	case []interface{}:
+		for i, nested := range value {
+			value[i] = removeLinks(nested)
+		}
+	}
+
+	return value
+}
+
+// handleDisplayValueExpression handles the netsuite_display_value_expression tool request
+func handleDisplayValueExpression(client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get record type and field from arguments
+	recordType, err := request.RequireString("record_type")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid record_type parameter: %v", err)), nil
+	}
+
+	field, err := request.RequireString("field")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid field parameter: %v", err)), nil
+	}
+
+	expression, err := client.DisplayValueExpression(recordType, field)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get display value expression: %v", err)), nil
+	}
+
+	column := strings.ToLower(field)
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"record_type": recordType,
+		"field":       field,
+		"expression":  expression,
+		"example":     fmt.Sprintf("SELECT id, %s, %s AS %s_display FROM %s", column, expression, column, strings.ToLower(recordType)),
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// handleGetPreferences handles the netsuite_get_preferences tool request
+func handleGetPreferences(ctx context.Context, client *netsuite.Client, config Config) (*mcp.CallToolResult, error) {
+	preferences, err := client.Preferences(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get preferences: %v", err)), nil
+	}
+
+	return newToolResultJSON(preferences, config.PrettyOutput), nil
+}
+
+// handleGetSystemInfo handles the netsuite_get_system_info tool request
+func handleGetSystemInfo(ctx context.Context, client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get topics from arguments, defaulting to all of them
+	topics := netsuite.SystemInfoTopics()
+	if names := request.GetStringSlice("topics", nil); len(names) > 0 {
+		topics = make([]netsuite.SystemInfoTopic, 0, len(names))
+		for _, name := range names {
+			topics = append(topics, netsuite.SystemInfoTopic(strings.ToLower(name)))
+		}
+	}
+
+	sections, err := client.SystemInfo(ctx, topics)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get system information: %v", err)), nil
+	}
+
+	var warnings []string
+	unavailable := []string{}
+	for _, section := range sections {
+		if !section.Available {
+			unavailable = append(unavailable, string(section.Topic))
+		}
+		if section.HasMore {
+			warnings = append(warnings, fmt.Sprintf("Only the first %d %s are listed; query the table with netsuite_run_suiteql for the rest", len(section.Rows), section.Topic))
+		}
+	}
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"sections":    sections,
+		"unavailable": unavailable,
+		"warnings":    warnings,
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// handlePreviewRecord handles the netsuite_preview_record tool request
+func handlePreviewRecord(ctx context.Context, client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get record type and row count from arguments
+	recordType, err := request.RequireString("record_type")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid record_type parameter: %v", err)), nil
+	}
+
+	n, warnings := clampParameter(nil, "n", request.GetInt("n", 5), 1, 100)
+
+	// Preview the record type in NetSuite
+	results, schema, err := client.Preview(ctx, recordType, n)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to preview record type '%s': %v", recordType, err)), nil
+	}
+
+	columns := make([]columnAnnotation, 0, len(schema.Properties))
+	for column, columnSchema := range schema.Properties {
+		columns = append(columns, columnAnnotation{
+			Name: column,
+			Type: columnSchema.BaseType(),
+		})
+	}
+	sort.Slice(columns, func(i, j int) bool {
+		return columns[i].Name < columns[j].Name
+	})
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"record_type": recordType,
+		"count":       results.Count,
+		"columns":     columns,
+		"items":       results.Items,
+		"warnings":    warnings,
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// handleRunSuiteQL handles the netsuite_run_suiteql tool request
+func handleRunSuiteQL(ctx context.Context, client *netsuite.Client, config Config, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get query from arguments
+	query, err := request.RequireString("query")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid query parameter: %v", err)), nil
+	}
+
+	if result := rejectSelectStar(client, config, query); result != nil {
+		return result, nil
+	}
+
+	// Expand the date range placeholder if requested
+	if dateRange := request.GetString("date_range", ""); dateRange != "" {
+		query, err = expandDateRange(query, request.GetString("date_column", ""), dateRange)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid date_range parameter: %v", err)), nil
+		}
+	}
+
+	// Get optional limit and offset from arguments
+	args := request.GetArguments()
+	limit := client.DefaultLimit()
+	offset := 0
+	var warnings []string
+
+	if limitArg, exists := args["limit"]; exists {
+		if limitFloat, ok := limitArg.(float64); ok {
+			// Validate limit (max 1000 as mentioned in description)
+			limit, warnings = clampParameter(warnings, "limit", int(limitFloat), 0, netsuite.MaxLimit)
+		}
+	}
+
+	if offsetArg, exists := args["offset"]; exists {
+		if offsetFloat, ok := offsetArg.(float64); ok {
+			offset, warnings = clampParameter(warnings, "offset", int(offsetFloat), 0, math.MaxInt)
+		}
+	}
+
+	// Paging in the query itself conflicts with the URL parameters, so the
+	// query's own paging wins
+	if netsuite.HasPaging(query) {
+		if limitArg, exists := args["limit"]; exists && limitArg != nil {
+			warnings = append(warnings, "The query pages its own results with LIMIT/OFFSET/FETCH, so the limit parameter was not applied")
+		}
+		if offsetArg, exists := args["offset"]; exists && offsetArg != nil {
+			warnings = append(warnings, "The query pages its own results with LIMIT/OFFSET/FETCH, so the offset parameter was not applied")
+		}
+		limit = 0
+		offset = 0
+	}
+
+	// Leave out inactive rows if requested, filtering the rows the query
+	// returns, which must not be paged by the query itself
+	if request.GetBool("exclude_inactive", false) {
+		if netsuite.HasPaging(query) {
+			return mcp.NewToolResultError("exclude_inactive cannot be combined with LIMIT/OFFSET/FETCH in the query; use the limit and offset parameters instead"), nil
+		}
+
+		var excluded bool
+		query, excluded, err = client.ExcludeInactiveQuery(query)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to exclude inactive rows: %v", err)), nil
+		}
+		if !excluded {
+			warnings = append(warnings, fmt.Sprintf("Table '%s' has no isinactive field, so exclude_inactive was ignored", netsuite.SourceTable(query)))
+		}
+	}
+
+	ctx, err = withRequestHeaders(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid headers parameter: %v", err)), nil
+	}
+
+	// Run the query at a point in time if requested
+	asOfDate := request.GetString("as_of_date", "")
+	if asOfDate != "" {
+		if _, err := time.Parse(time.DateOnly, asOfDate); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid as_of_date parameter: expected YYYY-MM-DD, got '%s'", asOfDate)), nil
+		}
+		ctx = netsuite.WithAsOfDate(ctx, asOfDate)
+	}
+
+	// Execute SuiteQL query, paginating by keyset when key columns are given
+	keyColumns := request.GetStringSlice("key_columns", nil)
+	var results *netsuite.SuiteQLResponse
+	var nextCursor []interface{}
+	namedParams, hasNamedParams := args["named_params"].(map[string]interface{})
+	if len(keyColumns) > 0 && hasNamedParams {
+		return mcp.NewToolResultError("named_params cannot be combined with key_columns"), nil
+	}
+
+	if hasNamedParams {
+		results, err = client.SuiteQLNamed(ctx, query, namedParams, limit, offset)
+	} else if len(keyColumns) > 0 {
+		after, _ := args["after"].([]interface{})
+
+		var keysetResults *netsuite.KeysetResponse
+		keysetResults, err = client.SuiteQLKeysetContext(ctx, query, keyColumns, after, limit)
+		if err == nil {
+			results = keysetResults.SuiteQLResponse
+			nextCursor = keysetResults.NextCursor
+		}
+	} else {
+		results, err = client.SuiteQLContext(ctx, query, limit, offset)
+	}
+	if err != nil {
+		// Give the syntax error a structured shape so it can be corrected,
+		// suggesting similar columns for an unknown one
+		var nsErr *netsuite.NetSuiteError
+		if errors.As(err, &nsErr) {
+			var suggestions []string
+			column, table := nsErr.UnknownColumn()
+			if table == "" {
+				table = netsuite.SourceTable(query)
+			}
+			if column != "" && table != "" {
+				suggestions = client.SuggestColumns(ctx, table, column)
+			}
+
+			if syntaxErr := nsErr.SyntaxError(); syntaxErr != nil {
+				response := map[string]interface{}{
+					"error":        "SuiteQL query could not be parsed",
+					"query":        query,
+					"syntax_error": syntaxErr,
+				}
+				if len(suggestions) > 0 {
+					response["unknown_column"] = column
+					response["did_you_mean"] = suggestions
+				}
+
+				result := newToolResultJSON(response, config.PrettyOutput)
+				result.IsError = true
+				return result, nil
+			}
+
+			if len(suggestions) > 0 {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to execute SuiteQL query: %v (did you mean %s?)", err, strings.Join(suggestions, ", "))), nil
+			}
+
+			// Some record types can only be queried through the REST record
+			// service, which netsuite_list_records uses
+			if table := nsErr.UnknownTable(); table != "" {
+				recordType, tableSuggestions := client.TableAlternatives(table)
+				if recordType != "" {
+					return mcp.NewToolResultError(fmt.Sprintf("Failed to execute SuiteQL query: %v. Record type '%s' cannot be queried with SuiteQL, but its records can be listed with netsuite_list_records (record_type '%s', with a filter in the REST record query language) and fetched with netsuite_get_record", err, recordType, recordType)), nil
+				}
+				if len(tableSuggestions) > 0 {
+					return mcp.NewToolResultError(fmt.Sprintf("Failed to execute SuiteQL query: %v (did you mean %s?)", err, strings.Join(tableSuggestions, ", "))), nil
+				}
+			}
+		}
+
+		if errors.Is(err, netsuite.ErrGovernanceExceeded) {
+			return mcp.NewToolResultError(fmt.Sprintf("SuiteQL query too expensive; add filters or reduce the limit: %v", err)), nil
+		}
+
+		if asOfDate != "" && nsErr != nil && nsErr.StatusCode == http.StatusBadRequest {
+			return mcp.NewToolResultError(fmt.Sprintf("NetSuite rejected the query as of %s; historical queries may not be supported for this account or query: %v", asOfDate, err)), nil
+		}
+
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to execute SuiteQL query: %v", err)), nil
+	}
+
+	// Rename the configured columns first, then the keys of the returned
+	// rows if requested
+	aliases := columnAliases(config, query)
+	items, collisions, err := aliasColumns(results.Items, aliases)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to alias columns: %v", err)), nil
+	}
+	warnings = append(warnings, aliasCollisionWarnings(collisions)...)
+	fieldCase := request.GetString("field_case", fieldCaseRaw)
+	items, err = transformFieldNames(items, fieldCase)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid field_case parameter: %v", err)), nil
+	}
+
+	// The summary names the fields as they are returned
+	summaryItems := items
+
+	// Shorten long values before rendering, so that every format agrees
+	maxFieldLength, warnings := clampParameter(warnings, "max_field_length", request.GetInt("max_field_length", 0), 0, math.MaxInt)
+	items, truncatedCount, err := truncateFields(items, maxFieldLength)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to truncate results: %v", err)), nil
+	}
+	if truncatedCount > 0 {
+		warnings = append(warnings, truncationWarning(truncatedCount, maxFieldLength))
+	}
+
+	// Drop null fields from the returned rows only; the summary is built from
+	// the rows before, so it still counts them
+	if request.GetBool("omit_nulls", false) {
+		items, err = omitNulls(items)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to omit null fields: %v", err)), nil
+		}
+	}
+
+	// The links are diagnostics of every format
+	diagnostics := make(map[string]interface{})
+	if request.GetBool("include_links", false) {
+		links := results.Links
+		if links == nil {
+			links = []netsuite.Link{}
+		}
+		diagnostics["links"] = links
+	}
+
+	// Render the rows alone in line-oriented formats
+	switch format := request.GetString("format", formatJSON); format {
+	case formatJSON:
+	case formatNDJSON, formatMarkdown:
+		render := renderNDJSON
+		if format == formatMarkdown {
+			render = renderMarkdown
+		}
+
+		text, err := render(items)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to format results: %v", err)), nil
+		}
+		result := mcp.NewToolResultText(text)
+		if warnings == nil {
+			warnings = []string{}
+		}
+		diagnostics["response_size"] = newResponseSize(len(text))
+		result.StructuredContent = map[string]interface{}{
+			"count":        results.Count,
+			"totalResults": results.TotalResults,
+			"hasMore":      results.HasMore,
+			"warnings":     warnings,
+			"diagnostics":  diagnostics,
+		}
+		return result, nil
+	default:
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid format parameter: unknown format '%s'", format)), nil
+	}
+
+	// Create a structured response
+	summary, summaryWarnings := generateSuiteQLSummary(results, summaryItems)
+	response := map[string]interface{}{
+		"query":         query,
+		"limit":         limit,
+		"default_limit": client.DefaultLimit(),
+		"offset":        offset,
+		"count":         results.Count,
+		"totalResults":  results.TotalResults,
+		"hasMore":       results.HasMore,
+		"items":         items,
+		"summary":       summary,
+		"warnings":      append(warnings, summaryWarnings...),
+	}
+
+	if len(keyColumns) > 0 {
+		response["key_columns"] = keyColumns
+		response["next_cursor"] = nextCursor
+	}
+
+	if request.GetBool("annotate", false) {
+		// Annotate the columns under the names the rows use
+		transform, _ := fieldCaseTransform(fieldCase)
+		response["columns"] = annotateColumns(ctx, client, query, results, func(column string) string {
+			return transform(aliasedName(column, aliases, collisions))
+		})
+	}
+
+	if len(diagnostics) > 0 {
+		response["diagnostics"] = diagnostics
+	}
+
+	return newStructuredToolResultJSON(response, config.PrettyOutput), nil
+}
+
+// maxSuggestedColumns is the number of columns suggested at most in place of
+// SELECT *.
+const maxSuggestedColumns = 20
+
+// rejectSelectStar returns an error result if SELECT * is forbidden and the
+// query uses it, suggesting columns of the FROM table to list instead, or
+// nil if the query may run.
+func rejectSelectStar(client *netsuite.Client, config Config, query string) *mcp.CallToolResult {
+	if !config.ForbidSelectStar || !netsuite.HasSelectStar(query) {
+		return nil
+	}
+
+	message := "SELECT * is not allowed on this server; list the columns to select instead"
+
+	table := netsuite.SourceTable(query)
+	if table == "" {
+		return mcp.NewToolResultError(message)
+	}
+
+	columns, err := client.FieldCatalog(table)
+	if err != nil || len(columns) == 0 {
+		return mcp.NewToolResultError(message + fmt.Sprintf(", e.g. with netsuite_field_catalog for %s", table))
+	}
+
+	names := make([]string, 0, min(len(columns), maxSuggestedColumns))
+	for _, column := range columns[:min(len(columns), maxSuggestedColumns)] {
+		names = append(names, column.Name)
+	}
+
+	return mcp.NewToolResultError(message + fmt.Sprintf(". Columns of %s include: %s", table, strings.Join(names, ", ")))
+}
+
+// dateRangeMacro is replaced by the predicate of the date_range parameter.
+const dateRangeMacro = "{{date_range}}"
+
+// expandDateRange replaces every date range placeholder in the query with the
+// predicate selecting the range on the column.
+func expandDateRange(query string, column string, dateRange string) (string, error) {
+	if !strings.Contains(query, dateRangeMacro) {
+		return "", fmt.Errorf("the query has no %s placeholder, e.g. 'WHERE %s'", dateRangeMacro, dateRangeMacro)
+	}
+
+	if column == "" {
+		return "", errors.New("date_column is required with date_range")
+	}
+
+	predicate, err := netsuite.DateRangePredicate(column, dateRange)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.ReplaceAll(query, dateRangeMacro, predicate), nil
+}
+
+// columnAnnotation describes a single column of SuiteQL results
+type columnAnnotation struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	CatalogType string `json:"catalog_type,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	// Label is the label of a custom field column.
+	Label string `json:"label,omitempty"`
+}
+
+// annotateColumns describes every column found in the results, named as
+// rename names it in the returned rows. The type is inferred from the
+// returned values, and the catalog metadata of the FROM table is used for
+// descriptions when it can be resolved. Custom field columns are labeled when
+// their definitions can be read.
+func annotateColumns(ctx context.Context, client *netsuite.Client, query string, results *netsuite.SuiteQLResponse, rename func(string) string) []columnAnnotation {
+	rows, err := results.Rows()
+	if err != nil {
+		return []columnAnnotation{}
+	}
+
+	// Collect the JSON types seen for each column across all rows
+	columnTypes := make(map[string]map[string]struct{})
+	for _, row := range rows {
+		for column, value := range row {
+			if columnTypes[column] == nil {
+				columnTypes[column] = make(map[string]struct{})
+			}
+			columnTypes[column][jsonType(value)] = struct{}{}
+		}
+	}
+
+	// Catalog property names are camel cased, while SuiteQL columns are not
+	catalogProperties := make(map[string]*jsonschematree.Schema)
+	if table := netsuite.SourceTable(query); table != "" {
+		if metadata, err := client.Metadata(table, nil); err == nil && metadata != nil {
+			for property, schema := range metadata.Properties {
+				catalogProperties[strings.ToLower(property)] = schema
+			}
+		}
+	}
+
+	names := make([]string, 0, len(columnTypes))
+	for column := range columnTypes {
+		names = append(names, column)
+	}
+	customFields, _ := client.LabelCustomFields(ctx, names)
+
+	columns := make([]columnAnnotation, 0, len(columnTypes))
+	for column, types := range columnTypes {
+		// Null says nothing about the type unless it is all there is
+		if len(types) > 1 {
+			delete(types, "null")
+		}
+
+		typeNames := make([]string, 0, len(types))
+		for typeName := range types {
+			typeNames = append(typeNames, typeName)
+		}
+		sort.Strings(typeNames)
+
+		annotation := columnAnnotation{
+			Name: rename(column),
+			Type: strings.Join(typeNames, "|"),
+		}
+
+		if property, ok := catalogProperties[strings.ToLower(column)]; ok {
+			annotation.CatalogType = property.BaseType()
+			annotation.Description = property.Description
+			if annotation.Description == "" {
+				annotation.Description = property.Title
+			}
+		}
+
+		if customField, ok := customFields[column]; ok {
+			annotation.Label = customField.Label
+		}
+
+		columns = append(columns, annotation)
+	}
+
+	sort.Slice(columns, func(i, j int) bool {
+		return columns[i].Name < columns[j].Name
+	})
+
+	return columns
+}
+
+// jsonType returns the JSON type name of a decoded value
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number, float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// generateSuiteQLSummary creates a human-readable summary of the SuiteQL results
+func generateSuiteQLSummary(results *netsuite.SuiteQLResponse, items []json.RawMessage) (map[string]interface{}, []string) {
+	var warnings []string
+	summary := map[string]interface{}{
+		"description": "NetSuite SuiteQL query results",
+		"count":       results.Count,
+		"offset":      results.Offset,
+		"total":       results.TotalResults,
+		"hasMore":     results.HasMore,
+	}
+
+	// Try to extract useful information from the first result item
+	if len(items) > 0 {
+		// Parse the first item to see what fields are available
+		var firstItemMap map[string]interface{}
+		if err := json.Unmarshal(items[0], &firstItemMap); err == nil {
+			fieldCount := len(firstItemMap)
+			summary["total_fields"] = fieldCount
+
+			// List first few field names as examples
+			fieldNames := sortedKeys(firstItemMap)
+			summary["sample_fields"] = fieldNames[:min(len(fieldNames), 10)]
+			if fieldCount > 10 {
+				warnings = append(warnings, fmt.Sprintf("The summary shows the first 10 fields out of %d total fields", fieldCount))
+			}
+		}
+	}
+
+	return summary, warnings
+}
@@ -0,0 +1,351 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/glints-dev/mcp-netsuite/pkg/netsuite"
+	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/oauth2"
+)
+
+// registeredTools returns the names of the tools a server built with the
+// config lists.
+func registeredTools(t *testing.T, config Config) []string {
+	t.Helper()
+
+	client, err := netsuite.NewClient(netsuite.ClientOptions{
+		AccountID:       "1234567",
+		APIHostOverride: "http://127.0.0.1:0",
+		TokenSource:     oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}),
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	message := json.RawMessage(`{"jsonrpc": "2.0", "id": 1, "method": "tools/list"}`)
+	response := NewServer(client, config).HandleMessage(context.Background(), message)
+
+	result, ok := response.(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatalf("tools/list returned %T, want a response", response)
+	}
+
+	resultJSON, err := json.Marshal(result.Result)
+	if err != nil {
+		t.Fatalf("failed to marshal result: %v", err)
+	}
+
+	var tools mcp.ListToolsResult
+	if err := json.Unmarshal(resultJSON, &tools); err != nil {
+		t.Fatalf("failed to unmarshal result: %v", err)
+	}
+
+	names := make([]string, 0, len(tools.Tools))
+	for _, tool := range tools.Tools {
+		names = append(names, tool.Name)
+	}
+
+	return names
+}
+
+func TestMutationToolsRequireEnableMutations(t *testing.T) {
+	mutationTools := []string{
+		"netsuite_upsert_record",
+		"netsuite_transform_record",
+		"netsuite_create_records",
+	}
+
+	tests := []struct {
+		name            string
+		enableMutations bool
+	}{
+		{name: "read-only", enableMutations: false},
+		{name: "mutations enabled", enableMutations: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tools := registeredTools(t, Config{EnableMutations: tt.enableMutations})
+			for _, tool := range mutationTools {
+				if got := slices.Contains(tools, tool); got != tt.enableMutations {
+					t.Errorf("tool %s registered = %v, want %v", tool, got, tt.enableMutations)
+				}
+			}
+		})
+	}
+}
+
+func TestRunSuiteQLIncludeLinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{
+			"count": 1, "offset": 0, "totalResults": 2, "hasMore": true,
+			"items": [{"id": "1", "companyname": "Acme"}],
+			"links": [
+				{"rel": "self", "href": "https://1234567.suitetalk.api.netsuite.com/services/rest/query/v1/suiteql?limit=1&offset=0"},
+				{"rel": "next", "href": "https://1234567.suitetalk.api.netsuite.com/services/rest/query/v1/suiteql?limit=1&offset=1"}
+			]
+		}`)
+	}))
+	defer server.Close()
+
+	client, err := netsuite.NewClient(netsuite.ClientOptions{
+		AccountID:       "1234567",
+		APIHostOverride: server.URL,
+		TokenSource:     oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}),
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		format       string
+		includeLinks bool
+		wantLinks    []string
+	}{
+		{name: "json", format: formatJSON, includeLinks: true, wantLinks: []string{"self", "next"}},
+		{name: "ndjson", format: formatNDJSON, includeLinks: true, wantLinks: []string{"self", "next"}},
+		{name: "markdown", format: formatMarkdown, includeLinks: true, wantLinks: []string{"self", "next"}},
+		{name: "json without links", format: formatJSON},
+		{name: "ndjson without links", format: formatNDJSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := mcp.CallToolRequest{}
+			request.Params.Arguments = map[string]interface{}{
+				"query":         "SELECT id, companyname FROM customer",
+				"limit":         float64(1),
+				"format":        tt.format,
+				"include_links": tt.includeLinks,
+			}
+
+			result, err := handleRunSuiteQL(context.Background(), client, Config{}, request)
+			if err != nil || result.IsError {
+				t.Fatalf("handleRunSuiteQL() = %+v, %v, want a result", result, err)
+			}
+
+			structuredJSON, err := json.Marshal(result.StructuredContent)
+			if err != nil {
+				t.Fatalf("failed to marshal structured content: %v", err)
+			}
+
+			var structured struct {
+				Diagnostics struct {
+					Links []netsuite.Link `json:"links"`
+				} `json:"diagnostics"`
+			}
+			if err := json.Unmarshal(structuredJSON, &structured); err != nil {
+				t.Fatalf("failed to unmarshal structured content: %v", err)
+			}
+
+			var got []string
+			for _, link := range structured.Diagnostics.Links {
+				got = append(got, link.Rel)
+			}
+			if !slices.Equal(got, tt.wantLinks) {
+				t.Errorf("diagnostics links = %v, want %v", got, tt.wantLinks)
+			}
+		})
+	}
+}
+
+func TestGenerateSuiteQLSummary(t *testing.T) {
+	tests := []struct {
+		name       string
+		items      []json.RawMessage
+		wantFields []string
+	}{
+		{
+			name:       "aliased fields",
+			items:      []json.RawMessage{json.RawMessage(`{"tier":"Gold","id":"1","companyname":"Acme"}`)},
+			wantFields: []string{"companyname", "id", "tier"},
+		},
+		{
+			name: "no items",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := &netsuite.SuiteQLResponse{Items: []json.RawMessage{json.RawMessage(`{"custentity_tier":"Gold"}`)}}
+
+			summary, _ := generateSuiteQLSummary(results, tt.items)
+			got, _ := summary["sample_fields"].([]string)
+			if !slices.Equal(got, tt.wantFields) {
+				t.Errorf("sample_fields = %v, want %v", got, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestRunSuiteQLAnnotate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/rest/query/v1/suiteql" {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{
+			"count": 1, "offset": 0, "totalResults": 1, "hasMore": false,
+			"items": [{"id": "1", "entityid": "C-1", "custentity_due_date": "2024-01-31"}]
+		}`)
+	}))
+	defer server.Close()
+
+	client, err := netsuite.NewClient(netsuite.ClientOptions{
+		AccountID:       "1234567",
+		APIHostOverride: server.URL,
+		TokenSource:     oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}),
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		config    Config
+		fieldCase string
+		want      []string
+	}{
+		{name: "raw", fieldCase: fieldCaseRaw, want: []string{"custentity_due_date", "entityid", "id"}},
+		{name: "camel case", fieldCase: fieldCaseCamel, want: []string{"custentityDueDate", "entityid", "id"}},
+		{
+			name:      "alias",
+			config:    Config{ColumnAliases: map[string]map[string]string{"customer": {"entityid": "customer_number"}}},
+			fieldCase: fieldCaseRaw,
+			want:      []string{"custentity_due_date", "customer_number", "id"},
+		},
+		{
+			name:      "alias and camel case",
+			config:    Config{ColumnAliases: map[string]map[string]string{"customer": {"entityid": "customer_number"}}},
+			fieldCase: fieldCaseCamel,
+			want:      []string{"custentityDueDate", "customerNumber", "id"},
+		},
+		{
+			name:      "colliding alias",
+			config:    Config{ColumnAliases: map[string]map[string]string{"customer": {"entityid": "id"}}},
+			fieldCase: fieldCaseRaw,
+			want:      []string{"custentity_due_date", "entityid", "id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := mcp.CallToolRequest{}
+			request.Params.Arguments = map[string]interface{}{
+				"query":      "SELECT id, entityid, custentity_due_date FROM customer",
+				"annotate":   true,
+				"field_case": tt.fieldCase,
+			}
+
+			result, err := handleRunSuiteQL(context.Background(), client, tt.config, request)
+			if err != nil || result.IsError {
+				t.Fatalf("handleRunSuiteQL() = %+v, %v, want a result", result, err)
+			}
+
+			var response struct {
+				Items   []map[string]interface{} `json:"items"`
+				Columns []columnAnnotation       `json:"columns"`
+			}
+			if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response); err != nil {
+				t.Fatalf("failed to unmarshal result: %v", err)
+			}
+
+			var got []string
+			for _, column := range response.Columns {
+				got = append(got, column.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("column names = %v, want %v", got, tt.want)
+			}
+			for _, name := range got {
+				if _, ok := response.Items[0][name]; !ok {
+					t.Errorf("column %q is not a key of the returned rows %v", name, response.Items[0])
+				}
+			}
+		})
+	}
+}
+
+func TestGetMetadataStructuredContent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/rest/record/v1/metadata-catalog/customer" {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"components": {"schemas": {"customer": {
+			"type": "object",
+			"properties": {"id": {"type": "string"}, "companyName": {"type": "string", "title": "Company Name"}}
+		}}}}`)
+	}))
+	defer server.Close()
+
+	client, err := netsuite.NewClient(netsuite.ClientOptions{
+		AccountID:       "1234567",
+		APIHostOverride: server.URL,
+		TokenSource:     oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}),
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		flat           bool
+		wantTextKey    string
+		wantStructured bool
+	}{
+		{name: "nested schema", wantTextKey: "metadata_schema", wantStructured: false},
+		{name: "flattened fields", flat: true, wantTextKey: "metadata_fields", wantStructured: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := mcp.CallToolRequest{}
+			request.Params.Arguments = map[string]interface{}{
+				"record_type": "customer",
+				"flat":        tt.flat,
+			}
+
+			result, err := handleGetMetadata(context.Background(), client, Config{}, request)
+			if err != nil || result.IsError {
+				t.Fatalf("handleGetMetadata() = %+v, %v, want a result", result, err)
+			}
+
+			var text map[string]json.RawMessage
+			if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &text); err != nil {
+				t.Fatalf("failed to unmarshal result: %v", err)
+			}
+			if _, ok := text[tt.wantTextKey]; !ok {
+				t.Errorf("text result has no %s: %v", tt.wantTextKey, text)
+			}
+
+			structured, ok := result.StructuredContent.(map[string]interface{})
+			if !ok {
+				t.Fatalf("StructuredContent = %T, want a map", result.StructuredContent)
+			}
+			if _, ok := structured["metadata_schema"]; ok {
+				t.Errorf("structured content holds metadata_schema, want it only in the text")
+			}
+			if _, ok := structured[tt.wantTextKey]; ok != tt.wantStructured {
+				t.Errorf("structured content has %s = %v, want %v", tt.wantTextKey, ok, tt.wantStructured)
+			}
+			for _, key := range []string{"record_type", "metadata_summary", "warnings", "diagnostics"} {
+				if _, ok := structured[key]; !ok {
+					t.Errorf("structured content has no %s", key)
+				}
+			}
+		})
+	}
+}
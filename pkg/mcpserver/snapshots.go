@@ -0,0 +1,161 @@
+package mcpserver
+
+import (
+	"container/list"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/glints-dev/mcp-netsuite/pkg/netsuite"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// maxSnapshots is the number of query snapshots kept for diffing, the
+	// oldest ones being dropped beyond it.
+	maxSnapshots = 50
+
+	// maxSnapshotRows is the number of rows a snapshot can hold. Queries with
+	// more rows must be narrowed to be diffed.
+	maxSnapshotRows = 1000
+)
+
+// snapshot is a saved result of a query, to diff later results against.
+type snapshot struct {
+	id        string
+	query     string
+	keyColumn string
+	results   *netsuite.SuiteQLResponse
+	takenAt   time.Time
+}
+
+// snapshotStore keeps the most recent snapshots in memory. They do not
+// survive a restart. It is safe for concurrent use.
+type snapshotStore struct {
+	mu        sync.Mutex
+	snapshots map[string]*list.Element
+	order     *list.List
+}
+
+func newSnapshotStore() *snapshotStore {
+	return &snapshotStore{
+		snapshots: make(map[string]*list.Element),
+		order:     list.New(),
+	}
+}
+
+// get returns the snapshot with the given ID, if it is still kept.
+func (store *snapshotStore) get(id string) (*snapshot, bool) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	element, ok := store.snapshots[id]
+	if !ok {
+		return nil, false
+	}
+
+	return element.Value.(*snapshot), true
+}
+
+// save keeps a snapshot under a new random ID, dropping the oldest snapshot
+// once maxSnapshots are kept.
+func (store *snapshotStore) save(query string, keyColumn string, results *netsuite.SuiteQLResponse) (*snapshot, error) {
+	idBytes := make([]byte, 8)
+	if _, err := rand.Read(idBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate snapshot ID: %w", err)
+	}
+
+	saved := &snapshot{
+		id:        hex.EncodeToString(idBytes),
+		query:     query,
+		keyColumn: keyColumn,
+		results:   results,
+		takenAt:   time.Now(),
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.snapshots[saved.id] = store.order.PushFront(saved)
+	if store.order.Len() > maxSnapshots {
+		oldest := store.order.Back()
+		store.order.Remove(oldest)
+		delete(store.snapshots, oldest.Value.(*snapshot).id)
+	}
+
+	return saved, nil
+}
+
+// handleDiffQuery handles the netsuite_diff_query tool request
+func handleDiffQuery(ctx context.Context, client *netsuite.Client, config Config, snapshots *snapshotStore, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get query and key column from arguments
+	query, err := request.RequireString("query")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid query parameter: %v", err)), nil
+	}
+	if result := rejectSelectStar(client, config, query); result != nil {
+		return result, nil
+	}
+
+	// SuiteQL lowercases column names
+	keyColumn := strings.ToLower(request.GetString("key_column", "id"))
+
+	// Look up the snapshot to compare against before running the query
+	var previous *snapshot
+	if snapshotID := request.GetString("snapshot_id", ""); snapshotID != "" {
+		var ok bool
+		previous, ok = snapshots.get(snapshotID)
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("Snapshot '%s' not found; snapshots are kept in memory until the server restarts", snapshotID)), nil
+		}
+
+		if previous.query != query || previous.keyColumn != keyColumn {
+			return mcp.NewToolResultError(fmt.Sprintf("Snapshot '%s' was taken of a different query or key column", snapshotID)), nil
+		}
+	}
+
+	// Execute SuiteQL query in NetSuite
+	results, err := client.SuiteQLContext(ctx, query, maxSnapshotRows, 0)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to execute SuiteQL query: %v", err)), nil
+	}
+	if results.HasMore {
+		return mcp.NewToolResultError(fmt.Sprintf("The query returns more than %d rows; narrow it down to diff it", maxSnapshotRows)), nil
+	}
+
+	// Compare against the previous snapshot, or against no rows on the first
+	// run, which still checks the key column
+	before := &netsuite.SuiteQLResponse{}
+	if previous != nil {
+		before = previous.results
+	}
+	diff, err := netsuite.DiffResponses(before, results, keyColumn)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to diff results: %v", err)), nil
+	}
+
+	// Save the results to diff the next run against
+	saved, err := snapshots.save(query, keyColumn, results)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Create a structured response
+	response := map[string]interface{}{
+		"snapshot_id": saved.id,
+		"count":       results.Count,
+	}
+	if previous != nil {
+		response["previous_snapshot_id"] = previous.id
+		response["previous_taken_at"] = previous.takenAt.UTC().Format(time.RFC3339)
+		response["diff"] = diff
+	} else {
+		response["warnings"] = []string{"No snapshot_id was given, so the results were only saved; pass snapshot_id on a later call to get what changed"}
+	}
+
+	return newToolResultJSON(response, config.PrettyOutput), nil
+}
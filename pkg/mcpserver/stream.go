@@ -0,0 +1,132 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/glints-dev/mcp-netsuite/pkg/jsonschematree"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// newStreamedToolResultJSON is like newToolResultJSON, but writes schemas
+// straight into the result text as they are encoded instead of marshalling
+// the whole response first, and appends the diagnostics in place, so that the
+// encoded schemas are held in memory only once, as the text. Callers that set
+// structured content must leave the schemas out of it, since it is encoded
+// again. Pretty output is left to newToolResultJSON, since it is meant for
+// debugging.
+func newStreamedToolResultJSON(response map[string]interface{}, pretty bool) *mcp.CallToolResult {
+	if pretty {
+		return newToolResultJSON(response, pretty)
+	}
+
+	if warnings, _ := response["warnings"].([]string); warnings == nil {
+		response["warnings"] = []string{}
+	}
+
+	diagnostics := takeDiagnostics(response)
+
+	var text strings.Builder
+	keys := sortedKeys(response)
+	text.WriteString("{")
+	if err := writeJSONMembers(&text, keys, func(key string) interface{} { return response[key] }); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response to JSON: %v", err))
+	}
+
+	// The size is that of the JSON without the diagnostics, including the
+	// closing brace
+	diagnostics["response_size"] = newResponseSize(text.Len() + 1)
+	response["diagnostics"] = diagnostics
+
+	diagnosticsJSON, err := json.Marshal(diagnostics)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response to JSON: %v", err))
+	}
+	if len(keys) > 0 {
+		text.WriteString(",")
+	}
+	text.WriteString(`"diagnostics":`)
+	text.Write(diagnosticsJSON)
+	text.WriteString("}")
+
+	return mcp.NewToolResultText(text.String())
+}
+
+// writeJSON writes a value as json.Marshal would. Schemas, and the maps
+// holding them, are streamed.
+func writeJSON(w io.Writer, value interface{}) error {
+	switch value := value.(type) {
+	case *jsonschematree.Schema:
+		return value.WriteJSON(w)
+	case map[string]*jsonschematree.Schema:
+		if value != nil {
+			return writeJSONObject(w, sortedKeys(value), func(key string) interface{} { return value[key] })
+		}
+	case map[string]interface{}:
+		if value != nil {
+			return writeJSONObject(w, sortedKeys(value), func(key string) interface{} { return value[key] })
+		}
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+
+	return err
+}
+
+// writeJSONObject writes the members with the given keys as a JSON object.
+func writeJSONObject(w io.Writer, keys []string, member func(key string) interface{}) error {
+	if _, err := io.WriteString(w, "{"); err != nil {
+		return err
+	}
+
+	if err := writeJSONMembers(w, keys, member); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "}")
+
+	return err
+}
+
+// writeJSONMembers writes the members with the given keys, separated by
+// commas, without the braces of their object.
+func writeJSONMembers(w io.Writer, keys []string, member func(key string) interface{}) error {
+	for i, key := range keys {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+
+		keyJSON, err := json.Marshal(key)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(append(keyJSON, ':')); err != nil {
+			return err
+		}
+
+		if err := writeJSON(w, member(key)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
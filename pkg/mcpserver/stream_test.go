@@ -0,0 +1,74 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/glints-dev/mcp-netsuite/pkg/jsonschematree"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestNewStreamedToolResultJSON(t *testing.T) {
+	var customer jsonschematree.Schema
+	if err := json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"companyName": {"type": "string", "description": "Name <b>&</b> \"title\""},
+			"balance": {"type": "number", "format": "double"},
+			"subsidiary": {"$ref": "#/components/schemas/subsidiary"}
+		},
+		"required": ["companyName"]
+	}`), &customer); err != nil {
+		t.Fatalf("failed to unmarshal schema: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		response func() map[string]interface{}
+	}{
+		{
+			name: "schema",
+			response: func() map[string]interface{} {
+				return map[string]interface{}{"record_type": "customer", "metadata": &customer}
+			},
+		},
+		{
+			name: "schema map and warnings",
+			response: func() map[string]interface{} {
+				return map[string]interface{}{
+					"schemas":  map[string]*jsonschematree.Schema{"customer": &customer, "missing": nil},
+					"warnings": []string{"Record type 'missing' has no schema"},
+				}
+			},
+		},
+		{
+			name: "diagnostics",
+			response: func() map[string]interface{} {
+				return map[string]interface{}{
+					"metadata":    &customer,
+					"diagnostics": map[string]interface{}{"cache": "hit"},
+				}
+			},
+		},
+		{
+			name:     "empty",
+			response: func() map[string]interface{} { return map[string]interface{}{} },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newStreamedToolResultJSON(tt.response(), false)
+			want := newToolResultJSON(tt.response(), false)
+
+			gotText := got.Content[0].(mcp.TextContent).Text
+			wantText := want.Content[0].(mcp.TextContent).Text
+			if gotText != wantText {
+				t.Errorf("newStreamedToolResultJSON() = %s, want %s", gotText, wantText)
+			}
+			if !json.Valid([]byte(gotText)) {
+				t.Errorf("newStreamedToolResultJSON() = %s, want valid JSON", gotText)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package mcpserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// truncateFields shortens string values longer than maxLength characters in
+// every result row, including those nested in objects and arrays, and returns
+// the number of values shortened. Every row is re-encoded, with its keys
+// sorted, whether or not it was shortened, so that the columns come in the
+// same order in every row. A maxLength of 0 or less leaves the rows untouched.
+func truncateFields(items []json.RawMessage, maxLength int) ([]json.RawMessage, int, error) {
+	if maxLength <= 0 {
+		return items, 0, nil
+	}
+
+	truncated := make([]json.RawMessage, 0, len(items))
+	count := 0
+	for _, item := range items {
+		// Numbers are kept as written, since rows are re-encoded
+		decoder := json.NewDecoder(bytes.NewReader(item))
+		decoder.UseNumber()
+
+		var row map[string]interface{}
+		if err := decoder.Decode(&row); err != nil {
+			return nil, 0, fmt.Errorf("failed to unmarshal JSON: %w", err)
+		}
+
+		count += truncateRow(row, maxLength)
+
+		itemJSON, err := json.Marshal(row)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		truncated = append(truncated, itemJSON)
+	}
+
+	return truncated, count, nil
+}
+
+// truncateRow shortens string values longer than maxLength characters in the
+// row in place and returns the number of values shortened.
+func truncateRow(row map[string]interface{}, maxLength int) int {
+	if maxLength <= 0 {
+		return 0
+	}
+
+	count := 0
+	for key, value := range row {
+		var n int
+		row[key], n = truncateValue(value, maxLength)
+		count += n
+	}
+
+	return count
+}
+
+func truncateValue(value interface{}, maxLength int) (interface{}, int) {
+	switch value := value.(type) {
+	case string:
+		runes := []rune(value)
+		if len(runes) <= maxLength {
+			return value, 0
+		}
+		return fmt.Sprintf("%s… [truncated from %d characters]", string(runes[:maxLength]), len(runes)), 1
+	case map[string]interface{}:
+		return value, truncateRow(value, maxLength)
+	case []interface{}:
+		count := 0
+		for i, element := range value {
+			var n int
+			value[i], n = truncateValue(element, maxLength)
+			count += n
+		}
+		return value, count
+	}
+
+	return value, 0
+}
+
+// truncationWarning describes the values shortened by max_field_length.
+func truncationWarning(count int, maxLength int) string {
+	return fmt.Sprintf("%d values longer than %d characters were truncated; raise max_field_length or select the column alone to see them in full", count, maxLength)
+}
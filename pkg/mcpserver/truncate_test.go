@@ -0,0 +1,75 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTruncateFields(t *testing.T) {
+	tests := []struct {
+		name      string
+		items     []string
+		maxLength int
+		want      []string
+		wantCount int
+	}{
+		{
+			name:      "key order of every row",
+			items:     []string{`{"tranid":"INV-1","memo":"short","id":1}`, `{"tranid":"INV-2","memo":"much too long","id":2}`},
+			maxLength: 5,
+			want:      []string{`{"id":1,"memo":"short","tranid":"INV-1"}`, `{"id":2,"memo":"much … [truncated from 13 characters]","tranid":"INV-2"}`},
+			wantCount: 1,
+		},
+		{
+			name:      "no row truncated",
+			items:     []string{`{"b":"x","a":"y"}`},
+			maxLength: 5,
+			want:      []string{`{"a":"y","b":"x"}`},
+		},
+		{
+			name:      "nested values",
+			items:     []string{`{"lines":[{"description":"abcdefgh"}],"entity":{"refName":"Acme Corporation"}}`},
+			maxLength: 4,
+			want:      []string{`{"entity":{"refName":"Acme… [truncated from 16 characters]"},"lines":[{"description":"abcd… [truncated from 8 characters]"}]}`},
+			wantCount: 2,
+		},
+		{
+			name:      "numbers kept as written",
+			items:     []string{`{"amount":1234567890.1200,"memo":"longer"}`},
+			maxLength: 4,
+			want:      []string{`{"amount":1234567890.1200,"memo":"long… [truncated from 6 characters]"}`},
+			wantCount: 1,
+		},
+		{
+			name:      "disabled",
+			items:     []string{`{"memo":"much too long","id":1}`},
+			maxLength: 0,
+			want:      []string{`{"memo":"much too long","id":1}`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := make([]json.RawMessage, len(tt.items))
+			for i, item := range tt.items {
+				items[i] = json.RawMessage(item)
+			}
+
+			got, count, err := truncateFields(items, tt.maxLength)
+			if err != nil {
+				t.Fatalf("truncateFields() error = %v", err)
+			}
+			if count != tt.wantCount {
+				t.Errorf("truncateFields() count = %d, want %d", count, tt.wantCount)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("truncateFields() returned %d rows, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if string(got[i]) != tt.want[i] {
+					t.Errorf("truncateFields() row %d = %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package netsuite
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+)
+
+type respondAsyncKey struct{}
+
+// WithRespondAsync returns a context whose writes to NetSuite are sent with
+// "Prefer: respond-async", so that NetSuite processes them as an
+// asynchronous job instead of within the request. Writes then return the ID
+// of the job to poll with GetJobStatus instead of the ID of the record.
+func WithRespondAsync(ctx context.Context) context.Context {
+	return context.WithValue(ctx, respondAsyncKey{}, true)
+}
+
+func respondAsync(ctx context.Context) bool {
+	async, _ := ctx.Value(respondAsyncKey{}).(bool)
+	return async
+}
+
+// JobStatus describes the progress of an asynchronous job.
+type JobStatus struct {
+	ID        string `json:"id"`
+	Completed bool   `json:"completed"`
+	Progress  string `json:"progress"`
+
+	// Tasks holds the outcome of each task of the job once it completed.
+	Tasks []TaskResult `json:"tasks,omitempty"`
+}
+
+// TaskResult is the outcome of a task of an asynchronous job, i.e. the
+// response NetSuite would have given the request synchronously.
+type TaskResult struct {
+	ID         string `json:"id"`
+	StatusCode int    `json:"statusCode"`
+
+	// RecordID is the internal ID of the written record, if NetSuite
+	// returned one.
+	RecordID string `json:"recordId,omitempty"`
+
+	// Error is the error message of a failed task.
+	Error string `json:"error,omitempty"`
+}
+
+// GetJobStatus returns the progress of an asynchronous job, along with the
+// outcome of its tasks once it completed.
+func (c *Client) GetJobStatus(ctx context.Context, jobID string) (*JobStatus, error) {
+	jobEndpoint := "/async/v1/job/" + url.PathEscape(jobID)
+
+	var status JobStatus
+	if err := c.getJSON(ctx, jobEndpoint, &status); err != nil {
+		return nil, fmt.Errorf("failed to get job %s: %w", jobID, err)
+	}
+
+	if !status.Completed {
+		return &status, nil
+	}
+
+	var tasks struct {
+		Items []struct {
+			ID string `json:"id"`
+		} `json:"items"`
+	}
+	if err := c.getJSON(ctx, jobEndpoint+"/task", &tasks); err != nil {
+		return nil, fmt.Errorf("failed to list tasks of job %s: %w", jobID, err)
+	}
+
+	for _, task := range tasks.Items {
+		result, err := c.taskResult(ctx, jobEndpoint+"/task/"+url.PathEscape(task.ID)+"/result")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get result of task %s of job %s: %w", task.ID, jobID, err)
+		}
+
+		result.ID = task.ID
+		status.Tasks = append(status.Tasks, *result)
+	}
+
+	return &status, nil
+}
+
+// taskResult fetches the result of a task, which replays the response of the
+// original request, including its status and Location header.
+func (c *Client) taskResult(ctx context.Context, endpoint string) (*TaskResult, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	response, err := c.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to GET %s: %w", request.URL.Path, err)
+	}
+	defer response.Body.Close()
+
+	bodyBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	result := &TaskResult{StatusCode: response.StatusCode}
+	if location := response.Header.Get("Location"); location != "" {
+		result.RecordID = path.Base(location)
+	}
+	if response.StatusCode >= http.StatusBadRequest {
+		result.Error = newNetSuiteError(response, bodyBytes).Error()
+	}
+
+	return result, nil
+}
@@ -0,0 +1,155 @@
+package netsuite
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting NetSuite while the circuit
+// breaker is open after repeated failures.
+var ErrCircuitOpen = errors.New("NetSuite is unavailable; requests are paused after repeated failures")
+
+const (
+	// DefaultCircuitBreakerThreshold is the number of consecutive failures
+	// after which the circuit breaker opens by default.
+	DefaultCircuitBreakerThreshold = 5
+	// DefaultCircuitBreakerCooldown is how long the circuit breaker stays
+	// open by default before letting a request through to test recovery.
+	DefaultCircuitBreakerCooldown = 30 * time.Second
+)
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+func (state circuitState) String() string {
+	switch state {
+	case circuitOpen:
+		return "open"
+	case circuitHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// circuitBreakerTransport fails requests fast once threshold consecutive
+// requests have failed with a network error or a 5xx status. After the
+// cooldown, a single request is let through: the circuit closes again if it
+// succeeds, and reopens otherwise.
+type circuitBreakerTransport struct {
+	next      http.RoundTripper
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    circuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func (transport *circuitBreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := transport.allow(); err != nil {
+		return nil, err
+	}
+
+	response, err := transport.next.RoundTrip(req)
+
+	switch {
+	case err != nil && req.Context().Err() != nil:
+		// Requests canceled by the caller say nothing about NetSuite
+		transport.release()
+	case err != nil:
+		transport.record(err)
+	case response.StatusCode >= http.StatusInternalServerError:
+		transport.record(fmt.Errorf("status %d", response.StatusCode))
+	default:
+		transport.record(nil)
+	}
+
+	return response, err
+}
+
+// allow returns ErrCircuitOpen if the request must not be sent.
+func (transport *circuitBreakerTransport) allow() error {
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+
+	switch transport.state {
+	case circuitOpen:
+		remaining := transport.cooldown - time.Since(transport.openedAt)
+		if remaining > 0 {
+			return fmt.Errorf("%w (retrying in %s)", ErrCircuitOpen, remaining.Round(time.Second))
+		}
+
+		transport.transition(circuitHalfOpen)
+		transport.probing = true
+		return nil
+	case circuitHalfOpen:
+		if transport.probing {
+			return fmt.Errorf("%w (testing recovery)", ErrCircuitOpen)
+		}
+
+		transport.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// release gives up the probe of a half-open circuit without an outcome, so
+// that the next request probes instead. The state and the failure count are
+// left as they are.
+func (transport *circuitBreakerTransport) release() {
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+
+	if transport.state == circuitHalfOpen {
+		transport.probing = false
+	}
+}
+
+// record updates the state of the circuit with the outcome of a request.
+func (transport *circuitBreakerTransport) record(failure error) {
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+
+	if transport.state == circuitHalfOpen {
+		transport.probing = false
+	}
+
+	if failure == nil {
+		transport.failures = 0
+		if transport.state != circuitClosed {
+			transport.transition(circuitClosed)
+		}
+		return
+	}
+
+	transport.failures++
+	if transport.state == circuitHalfOpen || transport.failures >= transport.threshold {
+		if transport.state != circuitOpen {
+			log.Printf("netsuite: %d consecutive failures, last: %v", transport.failures, failure)
+		}
+		transport.openedAt = time.Now()
+		transport.transition(circuitOpen)
+	}
+}
+
+func (transport *circuitBreakerTransport) transition(state circuitState) {
+	if transport.state == state {
+		return
+	}
+
+	log.Printf("netsuite: circuit breaker %s -> %s", transport.state, state)
+	transport.state = state
+}
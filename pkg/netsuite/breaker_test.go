@@ -0,0 +1,88 @@
+package netsuite
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestCircuitBreakerCanceledProbe(t *testing.T) {
+	errUnavailable := errors.New("connection refused")
+
+	tests := []struct {
+		name string
+		// outcome is the outcome of the request sent after the canceled
+		// probe.
+		outcome   error
+		wantState circuitState
+	}{
+		{name: "next probe succeeds", outcome: nil, wantState: circuitClosed},
+		{name: "next probe fails", outcome: errUnavailable, wantState: circuitOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var outcome error
+			transport := &circuitBreakerTransport{
+				threshold: 1,
+				cooldown:  time.Millisecond,
+				next: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+					if err := req.Context().Err(); err != nil {
+						return nil, err
+					}
+					if outcome != nil {
+						return nil, outcome
+					}
+					return &http.Response{StatusCode: http.StatusOK}, nil
+				}),
+			}
+
+			send := func(ctx context.Context) error {
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com", nil)
+				if err != nil {
+					t.Fatalf("failed to create request: %v", err)
+				}
+				_, err = transport.RoundTrip(req)
+				return err
+			}
+
+			// Open the circuit, then let the cooldown pass
+			outcome = errUnavailable
+			if err := send(context.Background()); !errors.Is(err, errUnavailable) {
+				t.Fatalf("send() error = %v, want %v", err, errUnavailable)
+			}
+			if transport.state != circuitOpen {
+				t.Fatalf("state = %s, want %s", transport.state, circuitOpen)
+			}
+			time.Sleep(2 * time.Millisecond)
+
+			// The canceled probe leaves the circuit half-open
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			if err := send(ctx); !errors.Is(err, context.Canceled) {
+				t.Fatalf("send() error = %v, want %v", err, context.Canceled)
+			}
+			if transport.state != circuitHalfOpen || transport.probing {
+				t.Fatalf("state = %s, probing = %v, want %s without a probe", transport.state, transport.probing, circuitHalfOpen)
+			}
+
+			// The next request probes and decides the state
+			outcome = tt.outcome
+			if err := send(context.Background()); !errors.Is(err, tt.outcome) {
+				t.Fatalf("send() error = %v, want %v", err, tt.outcome)
+			}
+			if transport.state != tt.wantState {
+				t.Errorf("state = %s, want %s", transport.state, tt.wantState)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package netsuite
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExceeded is returned without contacting NetSuite once the client
+// has sent RequestBudget requests within the current budget window.
+var ErrBudgetExceeded = errors.New("request budget exceeded")
+
+// DefaultRequestBudgetWindow is the window a request budget applies to by
+// default.
+const DefaultRequestBudgetWindow = time.Hour
+
+// budgetTransport rejects requests beyond limit per window. The window starts
+// with the first request after the previous one ended, so that the budget is
+// renewed at a predictable time rather than trickling back. Token exchanges
+// are not counted, since they are not requests made on behalf of a caller.
+type budgetTransport struct {
+	next   http.RoundTripper
+	limit  int
+	window time.Duration
+
+	mu          sync.Mutex
+	used        int
+	windowStart time.Time
+}
+
+func (transport *budgetTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isTokenRequest(req) {
+		if err := transport.spend(time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	return transport.next.RoundTrip(req)
+}
+
+// spend counts a request against the budget, or returns ErrBudgetExceeded if
+// none is left.
+func (transport *budgetTransport) spend(now time.Time) error {
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+
+	if transport.windowStart.IsZero() || now.Sub(transport.windowStart) >= transport.window {
+		transport.windowStart = now
+		transport.used = 0
+	}
+
+	if transport.used >= transport.limit {
+		renewal := transport.windowStart.Add(transport.window).Sub(now)
+		return fmt.Errorf("%w: %d requests per %s were sent; the budget renews in %s", ErrBudgetExceeded, transport.limit, transport.window, renewal.Round(time.Second))
+	}
+
+	transport.used++
+	return nil
+}
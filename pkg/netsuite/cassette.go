@@ -0,0 +1,209 @@
+package netsuite
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// CassetteMode selects whether NetSuite interactions are recorded to or
+// replayed from a cassette file.
+type CassetteMode string
+
+const (
+	// CassetteModeRecord sends requests to NetSuite and appends every
+	// request/response pair to the cassette.
+	CassetteModeRecord CassetteMode = "record"
+	// CassetteModeReplay serves responses from the cassette without
+	// contacting NetSuite.
+	CassetteModeReplay CassetteMode = "replay"
+)
+
+const redacted = "REDACTED"
+
+// interaction is a single request/response pair stored in a cassette. A
+// cassette holds one interaction per line.
+type interaction struct {
+	Request struct {
+		Method string      `json:"method"`
+		URL    string      `json:"url"`
+		Header http.Header `json:"header"`
+		Body   string      `json:"body"`
+	} `json:"request"`
+	Response struct {
+		StatusCode int         `json:"status_code"`
+		Header     http.Header `json:"header"`
+		Body       string      `json:"body"`
+	} `json:"response"`
+}
+
+// isTokenRequest reports whether the request exchanges credentials for an
+// access token, whose body and response must never be stored.
+func isTokenRequest(req *http.Request) bool {
+	return strings.HasSuffix(req.URL.Path, "/auth/oauth2/v1/token")
+}
+
+func readRequestBody(req *http.Request) (string, error) {
+	if req.Body == nil {
+		return "", nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read request body: %w", err)
+	}
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	return string(body), nil
+}
+
+type recordingTransport struct {
+	next http.RoundTripper
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+func newRecordingTransport(next http.RoundTripper, path string) (*recordingTransport, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cassette: %w", err)
+	}
+
+	return &recordingTransport{
+		next: next,
+		file: file,
+	}, nil
+}
+
+func (transport *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var recorded interaction
+	recorded.Request.Method = req.Method
+	recorded.Request.URL = req.URL.String()
+	recorded.Request.Header = req.Header.Clone()
+	if recorded.Request.Header.Get("Authorization") != "" {
+		recorded.Request.Header.Set("Authorization", redacted)
+	}
+
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	recorded.Request.Body = body
+
+	response, err := transport.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	response.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	recorded.Response.StatusCode = response.StatusCode
+	recorded.Response.Header = response.Header.Clone()
+	recorded.Response.Body = string(responseBody)
+
+	if isTokenRequest(req) {
+		recorded.Request.Body = redacted
+		recorded.Response.Body = fmt.Sprintf(
+			`{"access_token":"%s","token_type":"Bearer","expires_in":3600}`,
+			redacted,
+		)
+	}
+
+	line, err := json.Marshal(recorded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal interaction: %w", err)
+	}
+
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+
+	if _, err := transport.file.Write(append(line, '\n')); err != nil {
+		return nil, fmt.Errorf("failed to write cassette: %w", err)
+	}
+
+	return response, nil
+}
+
+type replayTransport struct {
+	mu           sync.Mutex
+	interactions []*interaction
+	used         []bool
+}
+
+func newReplayTransport(path string) (*replayTransport, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cassette: %w", err)
+	}
+	defer file.Close()
+
+	transport := &replayTransport{}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 64*1024*1024)
+	for scanner.Scan() {
+		var recorded interaction
+		if err := json.Unmarshal(scanner.Bytes(), &recorded); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal cassette: %w", err)
+		}
+
+		transport.interactions = append(transport.interactions, &recorded)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	transport.used = make([]bool, len(transport.interactions))
+
+	return transport, nil
+}
+
+// RoundTrip serves the first unused interaction matching the method, URL, and
+// body of the request. Bodies of token requests are not compared, since they
+// are redacted when recorded.
+func (transport *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+
+	for i, recorded := range transport.interactions {
+		if transport.used[i] ||
+			recorded.Request.Method != req.Method ||
+			recorded.Request.URL != req.URL.String() ||
+			(recorded.Request.Body != body && !isTokenRequest(req)) {
+			continue
+		}
+
+		transport.used[i] = true
+
+		return &http.Response{
+			Status:     fmt.Sprintf("%d %s", recorded.Response.StatusCode, http.StatusText(recorded.Response.StatusCode)),
+			StatusCode: recorded.Response.StatusCode,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     recorded.Response.Header.Clone(),
+			Body:       io.NopCloser(strings.NewReader(recorded.Response.Body)),
+			Request:    req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no recorded interaction for %s %s", req.Method, req.URL)
+}
@@ -0,0 +1,115 @@
+package netsuite
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/glints-dev/mcp-netsuite/pkg/jsonschematree"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Column describes a column that can be selected with SuiteQL.
+type Column struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Format string `json:"format,omitempty"`
+
+	// Reference is the record type a reference column points at. The column
+	// itself holds the internal ID of the referenced record.
+	Reference string `json:"reference,omitempty"`
+}
+
+// FieldCatalog returns the SuiteQL columns of a record type, sorted by name.
+// The columns are drawn from the metadata catalog, or inferred from a sample
+// row for record types without metadata. Sublists and subrecords are left
+// out, since they are separate tables in SuiteQL.
+func (c *Client) FieldCatalog(recordType string) ([]Column, error) {
+	metadata, err := c.Metadata(recordType, nil)
+	if err != nil {
+		return nil, err
+	}
+	if metadata == nil {
+		return nil, fmt.Errorf("no metadata found for record type %s", recordType)
+	}
+
+	columns := make([]Column, 0, len(metadata.Properties))
+	for property, schema := range metadata.Properties {
+		if property == "links" {
+			continue
+		}
+
+		column := Column{
+			// SuiteQL column names are the lowercased REST field names
+			Name:   strings.ToLower(property),
+			Type:   schema.BaseType(),
+			Format: schema.Format,
+		}
+
+		switch {
+		case schema.IsReference():
+			column.Type = "reference"
+			column.Reference = referenceTarget(schema)
+		case column.Type == gojsonschema.TYPE_ARRAY, column.Type == gojsonschema.TYPE_OBJECT:
+			continue
+		}
+
+		columns = append(columns, column)
+	}
+
+	sort.Slice(columns, func(i, j int) bool {
+		return columns[i].Name < columns[j].Name
+	})
+
+	return columns, nil
+}
+
+// describeTableSampleSize is the number of rows DescribeTable infers columns
+// from. NetSuite omits null columns from rows, so a single row rarely holds
+// every column.
+const describeTableSampleSize = 100
+
+// DescribeTable returns the columns of a SuiteQL table, sorted by name, with
+// types inferred from a sample of its rows. Unlike FieldCatalog, it does not
+// use the metadata catalog, whose record type names do not always match the
+// SuiteQL table names. Columns that are null in every sampled row are not
+// found.
+func (c *Client) DescribeTable(ctx context.Context, table string) ([]Column, error) {
+	_, schema, err := c.Preview(ctx, table, describeTableSampleSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(schema.Properties) == 0 {
+		return nil, fmt.Errorf("table %s has no rows to infer its columns from", table)
+	}
+
+	columns := make([]Column, 0, len(schema.Properties))
+	for name, columnSchema := range schema.Properties {
+		if name == "links" {
+			continue
+		}
+
+		columns = append(columns, Column{
+			Name: name,
+			Type: columnSchema.BaseType(),
+		})
+	}
+
+	sort.Slice(columns, func(i, j int) bool {
+		return columns[i].Name < columns[j].Name
+	})
+
+	return columns, nil
+}
+
+// referenceTarget returns the record type a reference schema points at, if
+// it is known.
+func referenceTarget(schema *jsonschematree.Schema) string {
+	if schema.Ref != "" {
+		return jsonschematree.RefTarget(schema.Ref)
+	}
+
+	return ""
+}
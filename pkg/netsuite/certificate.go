@@ -0,0 +1,48 @@
+package netsuite
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+)
+
+// checkCertificate verifies the certificate bundled with the private key, if
+// any. The certificate ID cannot be derived from the certificate, since
+// NetSuite assigns it when the certificate is uploaded, but a bundled
+// certificate still catches a key file that belongs to another certificate.
+func checkCertificate(pemBytes []byte, key crypto.Signer, certificateID string) error {
+	for block, rest := pem.Decode(pemBytes); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("failed to parse certificate: %w", err)
+		}
+
+		fingerprint := sha256.Sum256(certificate.Raw)
+		description := fmt.Sprintf(
+			"certificate %q (SHA-256 fingerprint %s)",
+			certificate.Subject.CommonName,
+			hex.EncodeToString(fingerprint[:]),
+		)
+
+		publicKey, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool })
+		if !ok || !publicKey.Equal(certificate.PublicKey) {
+			return fmt.Errorf("the private key does not belong to the bundled %s", description)
+		}
+
+		if certificateID == "" {
+			return fmt.Errorf(
+				"no certificate ID set; use the ID NetSuite assigned to the %s when it was uploaded",
+				description,
+			)
+		}
+	}
+
+	return nil
+}
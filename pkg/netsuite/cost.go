@@ -0,0 +1,106 @@
+package netsuite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Thresholds used to warn about expensive queries in CostEstimate.
+const (
+	largeTableRows  = 100000
+	poorSelectivity = 0.5
+	largeResultRows = 10000
+)
+
+// CostEstimate approximates the cost of a SuiteQL query, since NetSuite does
+// not expose query plans.
+type CostEstimate struct {
+	// SourceTable is the table the query reads from, if it could be found.
+	SourceTable string `json:"sourceTable,omitempty"`
+
+	// RowsScanned is the number of rows in the source table, or -1 when it
+	// is unknown.
+	RowsScanned int `json:"rowsScanned"`
+
+	// RowsReturned is the number of rows the query returns.
+	RowsReturned int `json:"rowsReturned"`
+
+	// Selectivity is the fraction of scanned rows that are returned, or -1
+	// when the number of scanned rows is unknown.
+	Selectivity float64 `json:"selectivity"`
+
+	Warnings []string `json:"warnings"`
+}
+
+// EstimateCost counts the rows of the query and of its source table to
+// estimate how selective the query is. Both counts are themselves queries,
+// so this is cheaper than fetching the rows, but not free.
+func (c *Client) EstimateCost(ctx context.Context, query string) (*CostEstimate, error) {
+	estimate := &CostEstimate{
+		SourceTable: SourceTable(query),
+		RowsScanned: -1,
+		Selectivity: -1,
+		Warnings:    []string{},
+	}
+
+	returned, err := c.count(ctx, fmt.Sprintf("SELECT COUNT(*) AS count FROM (%s)", query))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count the rows of the query: %w", err)
+	}
+	estimate.RowsReturned = returned
+
+	if estimate.SourceTable == "" {
+		estimate.Warnings = append(estimate.Warnings, "The source table of the query could not be determined, so its selectivity is unknown")
+	} else {
+		scanned, err := c.count(ctx, fmt.Sprintf("SELECT COUNT(*) AS count FROM %s", QuoteIdentifier(estimate.SourceTable)))
+		if err != nil {
+			estimate.Warnings = append(estimate.Warnings, fmt.Sprintf("Unable to count the rows of %s: %v", estimate.SourceTable, err))
+		} else {
+			estimate.RowsScanned = scanned
+			if scanned > 0 {
+				estimate.Selectivity = float64(returned) / float64(scanned)
+			}
+		}
+	}
+
+	if estimate.RowsScanned >= largeTableRows && estimate.Selectivity >= poorSelectivity {
+		estimate.Warnings = append(estimate.Warnings, fmt.Sprintf(
+			"The query returns %.0f%% of the %d rows of %s; add filters to make it more selective",
+			estimate.Selectivity*100,
+			estimate.RowsScanned,
+			estimate.SourceTable,
+		))
+	}
+
+	if estimate.RowsReturned >= largeResultRows {
+		estimate.Warnings = append(estimate.Warnings, fmt.Sprintf(
+			"The query returns %d rows; page through them or export them instead of fetching them at once",
+			estimate.RowsReturned,
+		))
+	}
+
+	return estimate, nil
+}
+
+// count runs a query selecting a single "count" column and returns its value.
+// NetSuite may return the count as a number or as a string.
+func (c *Client) count(ctx context.Context, query string) (int, error) {
+	var rows []struct {
+		Count json.Number `json:"count"`
+	}
+	if err := c.queryInto(ctx, query, &rows); err != nil {
+		return 0, err
+	}
+
+	if len(rows) == 0 {
+		return 0, fmt.Errorf("no count returned")
+	}
+
+	count, err := rows[0].Count.Int64()
+	if err != nil {
+		return 0, fmt.Errorf("invalid count %q: %w", rows[0].Count, err)
+	}
+
+	return int(count), nil
+}
@@ -0,0 +1,129 @@
+package netsuite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+
+	"github.com/glints-dev/mcp-netsuite/pkg/jsonschematree"
+)
+
+// createBatchSize is the number of records CreateRecords sends concurrently.
+// The client's concurrency limit still applies, so a smaller limit only
+// queues the requests of a batch.
+const createBatchSize = DefaultMaxConcurrency
+
+// CreateResult is the outcome of creating one of the records given to
+// CreateRecords.
+type CreateResult struct {
+	// Index is the position of the record in the records given.
+	Index int `json:"index"`
+
+	// ID is the internal ID of the created record, if NetSuite returned one.
+	ID string `json:"id,omitempty"`
+
+	// JobID is the asynchronous job creating the record, with
+	// WithRespondAsync.
+	JobID string `json:"jobId,omitempty"`
+
+	// Error is set when the record was not created. Violations holds the
+	// schema violations of a record that was not sent, and FieldErrors the
+	// fields NetSuite rejected.
+	Error       string                     `json:"error,omitempty"`
+	Violations  []jsonschematree.Violation `json:"violations,omitempty"`
+	FieldErrors []FieldError               `json:"fieldErrors,omitempty"`
+}
+
+// Created reports whether the record was created, or accepted as a job that
+// creates it.
+func (r CreateResult) Created() bool {
+	return r.Error == ""
+}
+
+// CreateRecords creates records of one record type and returns an outcome per
+// record, in the order given. The subsidiary of the context is set on records
+// that do not set one. Every record is then validated against the schema of
+// the record type, and the ones with violations are not sent. The
+// others are sent in batches of concurrent requests, each batch waiting for
+// the previous one, so that a large load neither floods NetSuite nor is lost
+// to a single failure. Once the context is done, the records not yet sent
+// fail with its error. With WithRespondAsync, each record is accepted as its
+// own job.
+func (c *Client) CreateRecords(ctx context.Context, recordType string, bodies []map[string]interface{}) ([]CreateResult, error) {
+	recordType = c.canonicalRecordType(recordType)
+
+	metadata, err := c.Metadata(recordType, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata for record type '%s': %w", recordType, err)
+	}
+	if metadata == nil {
+		return nil, fmt.Errorf("no metadata found for record type '%s'", recordType)
+	}
+
+	results := make([]CreateResult, len(bodies))
+	pending := make([]int, 0, len(bodies))
+	bodies = slices.Clone(bodies)
+	for i, body := range bodies {
+		results[i].Index = i
+		body, err := c.applySubsidiary(ctx, recordType, body)
+		if err != nil {
+			return nil, err
+		}
+		bodies[i] = body
+
+		if violations := metadata.Validate(body); len(violations) > 0 {
+			results[i].Error = fmt.Sprintf("record does not match the %s schema", recordType)
+			results[i].Violations = violations
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	endpoint := "/record/v1/" + url.PathEscape(recordType)
+	for start := 0; start < len(pending); start += createBatchSize {
+		batch := pending[start:min(start+createBatchSize, len(pending))]
+
+		if err := ctx.Err(); err != nil {
+			for _, i := range batch {
+				results[i].Error = err.Error()
+			}
+			continue
+		}
+
+		var wg sync.WaitGroup
+		for _, i := range batch {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				results[i] = c.createRecord(ctx, endpoint, bodies[i], i)
+			}(i)
+		}
+		wg.Wait()
+	}
+
+	return results, nil
+}
+
+// createRecord sends a single record of CreateRecords.
+func (c *Client) createRecord(ctx context.Context, endpoint string, body map[string]interface{}, index int) CreateResult {
+	result := CreateResult{Index: index}
+
+	written, err := c.sendRecord(ctx, http.MethodPost, endpoint, body)
+	if err == nil {
+		result.ID = written.id
+		result.JobID = written.jobID
+		return result
+	}
+
+	result.Error = err.Error()
+	var nsErr *NetSuiteError
+	if errors.As(err, &nsErr) {
+		result.FieldErrors = nsErr.FieldErrors()
+	}
+
+	return result
+}
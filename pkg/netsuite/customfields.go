@@ -0,0 +1,162 @@
+package netsuite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// customFieldsQuery reads the definitions of the custom fields of standard
+// record types: body, column, entity, item, and CRM fields.
+const customFieldsQuery = "SELECT scriptid, name, fieldtype, fieldvaluetype, description FROM customfield ORDER BY scriptid"
+
+// customRecordFieldsQuery reads the definitions of the fields of custom
+// record types, whose script IDs start with custrecord_.
+const customRecordFieldsQuery = "SELECT scriptid, name, fieldvaluetype, description FROM customrecordcustomfield ORDER BY scriptid"
+
+// customRecordFieldKind is the Kind of the fields of custom record types.
+const customRecordFieldKind = "RECORD"
+
+// customFieldPrefixes are the script ID prefixes of custom fields, by what
+// they apply to.
+var customFieldPrefixes = []string{
+	"custbody_", "custcol_", "custentity_", "custitem_", "custrecord_", "custevent_",
+}
+
+// customFieldPageSize is the page size used to read custom field definitions.
+const customFieldPageSize = 1000
+
+// CustomField is the definition of a custom field, such as custbody_approver.
+type CustomField struct {
+	// ID is the script ID of the field, lower cased as in record and SuiteQL
+	// column names.
+	ID    string `json:"id"`
+	Label string `json:"label"`
+
+	// Kind is what the field applies to, e.g. BODY for transaction body
+	// fields and COLUMN for transaction line fields.
+	Kind string `json:"kind,omitempty"`
+
+	// Type is the type of the field's values, e.g. "Free-Form Text" or
+	// "List/Record".
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// IsCustomField reports whether a record or column name is a custom field,
+// going by NetSuite's script ID prefixes. Standard fields starting with
+// "cust", such as customForm, are not.
+func IsCustomField(name string) bool {
+	lower := strings.ToLower(name)
+	for _, prefix := range customFieldPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CustomFields returns the definitions of the custom fields of the account,
+// including the fields of custom record types, keyed by lower-cased script
+// ID. They rarely change, so the first result is cached for the lifetime of
+// the client.
+func (c *Client) CustomFields(ctx context.Context) (map[string]CustomField, error) {
+	c.customFieldsMutex.Lock()
+	defer c.customFieldsMutex.Unlock()
+
+	if c.customFields != nil {
+		return c.customFields, nil
+	}
+
+	rows, err := c.SuiteQLAll(ctx, customFieldsQuery, customFieldPageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query custom fields: %w", err)
+	}
+
+	fields := make(map[string]CustomField, len(rows))
+	addCustomFields(fields, rows, "")
+
+	// Accounts without custom record types may not expose the table
+	rows, err = c.SuiteQLAll(ctx, customRecordFieldsQuery, customFieldPageSize)
+	var nsErr *NetSuiteError
+	switch {
+	case err == nil:
+		addCustomFields(fields, rows, customRecordFieldKind)
+	case errors.As(err, &nsErr) && nsErr.UnknownTable() != "":
+	default:
+		return nil, fmt.Errorf("failed to query custom record fields: %w", err)
+	}
+
+	c.customFields = fields
+	return fields, nil
+}
+
+// addCustomFields adds the custom fields defined by the rows to fields,
+// keeping the definitions already there. Fields without a kind of their own
+// get the given one.
+func addCustomFields(fields map[string]CustomField, rows []map[string]interface{}, kind string) {
+	for _, row := range rows {
+		field := CustomField{
+			ID:          strings.ToLower(rowString(row, "scriptid")),
+			Label:       rowString(row, "name"),
+			Kind:        rowString(row, "fieldtype"),
+			Type:        rowString(row, "fieldvaluetype"),
+			Description: rowString(row, "description"),
+		}
+		if field.ID == "" {
+			continue
+		}
+		if _, ok := fields[field.ID]; ok {
+			continue
+		}
+		if field.Kind == "" {
+			field.Kind = kind
+		}
+
+		fields[field.ID] = field
+	}
+}
+
+// LabelCustomFields returns the definitions of the custom fields among the
+// names, keyed by the names as given. Names that are not custom fields, or
+// have no definition, are left out.
+func (c *Client) LabelCustomFields(ctx context.Context, names []string) (map[string]CustomField, error) {
+	labels := make(map[string]CustomField)
+
+	hasCustom := false
+	for _, name := range names {
+		if IsCustomField(name) {
+			hasCustom = true
+			break
+		}
+	}
+	if !hasCustom {
+		return labels, nil
+	}
+
+	fields, err := c.CustomFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		if field, ok := fields[strings.ToLower(name)]; ok {
+			labels[name] = field
+		}
+	}
+
+	return labels, nil
+}
+
+// rowString returns a column of a SuiteQL row as a string, or an empty string
+// if it is null or missing.
+func rowString(row map[string]interface{}, column string) string {
+	value, ok := row[column]
+	if !ok || value == nil {
+		return ""
+	}
+
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,116 @@
+package netsuite
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIsCustomField(t *testing.T) {
+	tests := []struct {
+		name  string
+		field string
+		want  bool
+	}{
+		{name: "transaction body field", field: "custbody_approver", want: true},
+		{name: "transaction column field", field: "custcol_discount", want: true},
+		{name: "entity field", field: "custentity_region", want: true},
+		{name: "item field", field: "custitem_color", want: true},
+		{name: "custom record field", field: "custrecord_rate", want: true},
+		{name: "CRM field", field: "custevent_outcome", want: true},
+		{name: "mixed case", field: "CustBody_Approver", want: true},
+		{name: "custom form", field: "customForm", want: false},
+		{name: "customer", field: "customer", want: false},
+		{name: "prefix without underscore", field: "custbody", want: false},
+		{name: "standard field", field: "tranId", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsCustomField(tt.field); got != tt.want {
+				t.Errorf("IsCustomField(%q) = %v, want %v", tt.field, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCustomFields(t *testing.T) {
+	customFieldRows := `[
+		{"scriptid": "CUSTBODY_APPROVER", "name": "Approver", "fieldtype": "BODY", "fieldvaluetype": "List/Record"},
+		{"scriptid": "CUSTRECORD_RATE", "name": "Rate", "fieldtype": "RECORD", "fieldvaluetype": "Currency"}
+	]`
+	customRecordFieldRows := `[
+		{"scriptid": "CUSTRECORD_RATE", "name": "Duplicate Rate", "fieldvaluetype": "Decimal Number"},
+		{"scriptid": "CUSTRECORD_REGION", "name": "Region", "fieldvaluetype": "Free-Form Text", "description": "Sales region"}
+	]`
+
+	tests := []struct {
+		name    string
+		tables  map[string]string
+		want    map[string]CustomField
+		wantErr bool
+	}{
+		{
+			name:   "custom record fields",
+			tables: map[string]string{"customfield": customFieldRows, "customrecordcustomfield": customRecordFieldRows},
+			want: map[string]CustomField{
+				"custbody_approver": {ID: "custbody_approver", Label: "Approver", Kind: "BODY", Type: "List/Record"},
+				"custrecord_rate":   {ID: "custrecord_rate", Label: "Rate", Kind: "RECORD", Type: "Currency"},
+				"custrecord_region": {ID: "custrecord_region", Label: "Region", Kind: "RECORD", Type: "Free-Form Text", Description: "Sales region"},
+			},
+		},
+		{
+			name:   "custom record fields unavailable",
+			tables: map[string]string{"customfield": customFieldRows},
+			want: map[string]CustomField{
+				"custbody_approver": {ID: "custbody_approver", Label: "Approver", Kind: "BODY", Type: "List/Record"},
+				"custrecord_rate":   {ID: "custrecord_rate", Label: "Rate", Kind: "RECORD", Type: "Currency"},
+			},
+		},
+		{
+			name:    "custom fields unavailable",
+			tables:  map[string]string{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Client{Client: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				var body struct {
+					Q string `json:"q"`
+				}
+				if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+					t.Fatalf("failed to decode request body: %v", err)
+				}
+
+				table := SourceTable(body.Q)
+				statusCode := http.StatusOK
+				responseBody := `{"count": 0, "hasMore": false, "items": ` + tt.tables[table] + `}`
+				if _, ok := tt.tables[table]; !ok {
+					statusCode = http.StatusBadRequest
+					responseBody = `{"title": "Bad Request", "o:errorDetails": [{"detail": "Invalid search query. Detailed unprocessed description follows. Search error occurred: Record '` + table + `' was not found.", "o:errorCode": "INVALID_PARAMETER"}]}`
+				}
+
+				return &http.Response{
+					StatusCode: statusCode,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(responseBody)),
+					Request:    req,
+				}, nil
+			})}}
+
+			got, err := client.CustomFields(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CustomFields() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CustomFields() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
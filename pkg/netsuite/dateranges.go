@@ -0,0 +1,70 @@
+package netsuite
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// dateRange is a half-open range of days, as SuiteQL expressions for its
+// first day and the day after its last.
+type dateRange struct {
+	start string
+	end   string
+}
+
+// dateRanges are the ranges DateRangePredicate supports. They are relative to
+// SYSDATE, which NetSuite evaluates in the session time zone, i.e. the
+// account's, so "today" is the account's today rather than the server's.
+// Weeks start on Monday.
+var dateRanges = map[string]dateRange{
+	"today":         {"TRUNC(SYSDATE)", "TRUNC(SYSDATE) + 1"},
+	"yesterday":     {"TRUNC(SYSDATE) - 1", "TRUNC(SYSDATE)"},
+	"this_week":     {"TRUNC(SYSDATE, 'IW')", "TRUNC(SYSDATE, 'IW') + 7"},
+	"last_week":     {"TRUNC(SYSDATE, 'IW') - 7", "TRUNC(SYSDATE, 'IW')"},
+	"this_month":    {"TRUNC(SYSDATE, 'MM')", "ADD_MONTHS(TRUNC(SYSDATE, 'MM'), 1)"},
+	"last_month":    {"ADD_MONTHS(TRUNC(SYSDATE, 'MM'), -1)", "TRUNC(SYSDATE, 'MM')"},
+	"this_quarter":  {"TRUNC(SYSDATE, 'Q')", "ADD_MONTHS(TRUNC(SYSDATE, 'Q'), 3)"},
+	"last_quarter":  {"ADD_MONTHS(TRUNC(SYSDATE, 'Q'), -3)", "TRUNC(SYSDATE, 'Q')"},
+	"this_year":     {"TRUNC(SYSDATE, 'YYYY')", "ADD_MONTHS(TRUNC(SYSDATE, 'YYYY'), 12)"},
+	"last_year":     {"ADD_MONTHS(TRUNC(SYSDATE, 'YYYY'), -12)", "TRUNC(SYSDATE, 'YYYY')"},
+	"last_7_days":   {"TRUNC(SYSDATE) - 6", "TRUNC(SYSDATE) + 1"},
+	"last_30_days":  {"TRUNC(SYSDATE) - 29", "TRUNC(SYSDATE) + 1"},
+	"month_to_date": {"TRUNC(SYSDATE, 'MM')", "TRUNC(SYSDATE) + 1"},
+	"year_to_date":  {"TRUNC(SYSDATE, 'YYYY')", "TRUNC(SYSDATE) + 1"},
+}
+
+// columnPattern matches a column name, optionally qualified with a table
+// alias, e.g. "trandate" or "t.trandate".
+var columnPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// DateRanges returns the names of the ranges DateRangePredicate supports,
+// sorted.
+func DateRanges() []string {
+	names := make([]string, 0, len(dateRanges))
+	for name := range dateRanges {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// DateRangePredicate returns a SuiteQL predicate matching the rows whose date
+// column falls within a named range such as "last_month" or "this_quarter",
+// e.g. "(t.trandate >= TRUNC(SYSDATE) AND t.trandate < TRUNC(SYSDATE) + 1)"
+// for "today". The bounds are computed by NetSuite in the account's time zone
+// when the query runs. Times on the last day of the range are included.
+func DateRangePredicate(column string, name string) (string, error) {
+	if !columnPattern.MatchString(column) {
+		return "", fmt.Errorf("invalid column \"%s\": expected a column name such as trandate or t.trandate", column)
+	}
+
+	dateRange, ok := dateRanges[strings.ToLower(name)]
+	if !ok {
+		return "", fmt.Errorf("unknown date range \"%s\": expected one of %s", name, strings.Join(DateRanges(), ", "))
+	}
+
+	return fmt.Sprintf("(%s >= %s AND %s < %s)", column, dateRange.start, column, dateRange.end), nil
+}
@@ -0,0 +1,124 @@
+package netsuite
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// QueryDiff is the difference between two results of the same query, with
+// rows matched by a key column.
+type QueryDiff struct {
+	// Added holds the rows only found in the later result.
+	Added []map[string]interface{} `json:"added"`
+
+	// Removed holds the keys of the rows only found in the earlier result.
+	Removed []string `json:"removed"`
+
+	// Changed holds the rows found in both results whose values differ.
+	Changed []RowChange `json:"changed"`
+}
+
+// RowChange describes how the values of a row changed, mapping each changed
+// column to its earlier and later value. A column missing from a result is
+// null, as NetSuite leaves null columns out of rows.
+type RowChange struct {
+	Key     string                    `json:"key"`
+	Changes map[string][2]interface{} `json:"changes"`
+}
+
+// Empty reports whether the results were the same.
+func (d *QueryDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffResponses compares two results of the same query, matching their rows
+// by the value of keyColumn, which must be unique and present in every row.
+// Rows are reported in key order.
+func DiffResponses(before *SuiteQLResponse, after *SuiteQLResponse, keyColumn string) (*QueryDiff, error) {
+	beforeRows, err := keyedRows(before, keyColumn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read earlier rows: %w", err)
+	}
+
+	afterRows, err := keyedRows(after, keyColumn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read later rows: %w", err)
+	}
+
+	diff := &QueryDiff{
+		Added:   []map[string]interface{}{},
+		Removed: []string{},
+		Changed: []RowChange{},
+	}
+
+	for _, key := range sortedRowKeys(afterRows) {
+		afterRow := afterRows[key]
+		beforeRow, ok := beforeRows[key]
+		if !ok {
+			diff.Added = append(diff.Added, afterRow)
+			continue
+		}
+
+		changes := make(map[string][2]interface{})
+		for column := range beforeRow {
+			if !reflect.DeepEqual(beforeRow[column], afterRow[column]) {
+				changes[column] = [2]interface{}{beforeRow[column], afterRow[column]}
+			}
+		}
+		for column := range afterRow {
+			if _, ok := beforeRow[column]; !ok {
+				changes[column] = [2]interface{}{nil, afterRow[column]}
+			}
+		}
+
+		if len(changes) > 0 {
+			diff.Changed = append(diff.Changed, RowChange{Key: key, Changes: changes})
+		}
+	}
+
+	for _, key := range sortedRowKeys(beforeRows) {
+		if _, ok := afterRows[key]; !ok {
+			diff.Removed = append(diff.Removed, key)
+		}
+	}
+
+	return diff, nil
+}
+
+// keyedRows decodes the rows of a result, indexed by the value of keyColumn.
+// The links NetSuite adds to rows are left out.
+func keyedRows(response *SuiteQLResponse, keyColumn string) (map[string]map[string]interface{}, error) {
+	rows := make(map[string]map[string]interface{}, len(response.Items))
+	for i, item := range response.Items {
+		var row map[string]interface{}
+		if err := json.Unmarshal(item, &row); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal row %d: %w", i, err)
+		}
+		delete(row, "links")
+
+		value, ok := row[keyColumn]
+		if !ok || value == nil {
+			return nil, fmt.Errorf("row %d has no value for key column %s", i, keyColumn)
+		}
+
+		key := fmt.Sprint(value)
+		if _, ok := rows[key]; ok {
+			return nil, fmt.Errorf("key column %s is not unique: %s appears more than once", keyColumn, key)
+		}
+		rows[key] = row
+	}
+
+	return rows, nil
+}
+
+func sortedRowKeys(rows map[string]map[string]interface{}) []string {
+	keys := make([]string, 0, len(rows))
+	for key := range rows {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,226 @@
+package netsuite
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// NetSuiteError is returned when NetSuite responds with an unsuccessful HTTP
+// status. NetSuite describes errors in an envelope holding a list of details.
+// https://docs.oracle.com/en/cloud/saas/netsuite/ns-online-help/section_1545222128.html
+type NetSuiteError struct {
+	StatusCode int
+	Title      string
+	Details    []ErrorDetail
+	Body       string
+
+	// Method and Path identify the endpoint that failed. The path includes
+	// the query string, but not the account-specific host.
+	Method string
+	Path   string
+}
+
+// ErrorDetail is a single entry of the "o:errorDetails" array.
+type ErrorDetail struct {
+	Detail     string `json:"detail"`
+	ErrorCode  string `json:"o:errorCode"`
+	QueryParam string `json:"o:errorQueryParam,omitempty"`
+	Path       string `json:"o:errorPath,omitempty"`
+}
+
+func newNetSuiteError(response *http.Response, body []byte) *NetSuiteError {
+	nsErr := &NetSuiteError{
+		StatusCode: response.StatusCode,
+		Body:       string(body),
+	}
+
+	if response.Request != nil {
+		nsErr.Method = response.Request.Method
+		nsErr.Path = response.Request.URL.RequestURI()
+	}
+
+	var envelope struct {
+		Title   string        `json:"title"`
+		Details []ErrorDetail `json:"o:errorDetails"`
+	}
+	if err := json.Unmarshal(body, &envelope); err == nil {
+		nsErr.Title = envelope.Title
+		nsErr.Details = envelope.Details
+	}
+
+	return nsErr
+}
+
+func (e *NetSuiteError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("invalid HTTP response status %d: %s", e.StatusCode, e.Body)
+	}
+
+	return fmt.Sprintf(
+		"invalid HTTP response status %d from %s %s: %s",
+		e.StatusCode,
+		e.Method,
+		e.Path,
+		e.Body,
+	)
+}
+
+// ErrGovernanceExceeded matches NetSuite errors reporting that a request ran
+// out of governance, such as the time or usage limit of a SuiteQL query. Use
+// errors.Is to check for it.
+var ErrGovernanceExceeded = errors.New("NetSuite governance limit exceeded")
+
+// governanceErrorCodes are the error codes NetSuite reports when governance
+// is exhausted.
+var governanceErrorCodes = map[string]struct{}{
+	"SSS_TIME_LIMIT_EXCEEDED":  {},
+	"SSS_USAGE_LIMIT_EXCEEDED": {},
+}
+
+// Is reports whether the error matches target. It makes errors.Is match
+// ErrGovernanceExceeded for governance error codes.
+func (e *NetSuiteError) Is(target error) bool {
+	if target != ErrGovernanceExceeded {
+		return false
+	}
+
+	for _, detail := range e.Details {
+		if _, ok := governanceErrorCodes[detail.ErrorCode]; ok {
+			return true
+		}
+
+		for code := range governanceErrorCodes {
+			if strings.Contains(detail.Detail, code) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// FieldError describes a validation failure of a single field of a record
+// that NetSuite rejected on create or update.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
+}
+
+// FieldErrors returns the error details that point at a specific field, as
+// reported by NetSuite through "o:errorPath" when it rejects a record.
+func (e *NetSuiteError) FieldErrors() []FieldError {
+	var fieldErrors []FieldError
+	for _, detail := range e.Details {
+		if detail.Path == "" {
+			continue
+		}
+
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   detail.Path,
+			Message: detail.Detail,
+			Code:    detail.ErrorCode,
+		})
+	}
+
+	return fieldErrors
+}
+
+// ErrNonJSONResponse is returned when NetSuite responds with something other
+// than JSON, typically an HTML page served during maintenance or by a WAF.
+var ErrNonJSONResponse = errors.New("unexpected non-JSON response from NetSuite (maybe maintenance/WAF)")
+
+// nonJSONSnippetLength is the number of bytes of a non-JSON body included in
+// the error at most.
+const nonJSONSnippetLength = 200
+
+// checkJSONResponse returns ErrNonJSONResponse if the response declares a
+// content type that is not JSON.
+func checkJSONResponse(response *http.Response, body []byte) error {
+	contentType := response.Header.Get("Content-Type")
+	if contentType == "" {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")) {
+		return nil
+	}
+
+	var endpoint string
+	if response.Request != nil {
+		endpoint = fmt.Sprintf(" from %s %s", response.Request.Method, response.Request.URL.RequestURI())
+	}
+
+	return fmt.Errorf(
+		"%w: status %d%s, content type %s: %s",
+		ErrNonJSONResponse,
+		response.StatusCode,
+		endpoint,
+		contentType,
+		nonJSONSnippet(body),
+	)
+}
+
+// nonJSONSnippet returns the start of a body, cut on a rune boundary so that
+// multi-byte characters are never split.
+func nonJSONSnippet(body []byte) string {
+	if len(body) <= nonJSONSnippetLength {
+		return string(body)
+	}
+
+	end := nonJSONSnippetLength
+	for end > 0 && !utf8.RuneStart(body[end]) {
+		end--
+	}
+
+	return string(body[:end]) + "..."
+}
+
+// SyntaxError describes a SuiteQL query that NetSuite failed to parse.
+type SyntaxError struct {
+	Message string `json:"message"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Token   string `json:"token,omitempty"`
+}
+
+var (
+	syntaxErrorPrefix   = "Invalid search query. Detailed unprocessed description follows. "
+	syntaxErrorPosition = regexp.MustCompile(`line (\d+):(\d+)`)
+	syntaxErrorToken    = regexp.MustCompile(`(?:input|token|near) '([^']*)'`)
+)
+
+// SyntaxError extracts the SuiteQL syntax error from the error details, or
+// returns nil if NetSuite did not reject the query itself.
+func (e *NetSuiteError) SyntaxError() *SyntaxError {
+	for _, detail := range e.Details {
+		if detail.QueryParam != "q" && !strings.HasPrefix(detail.Detail, syntaxErrorPrefix) {
+			continue
+		}
+
+		syntaxErr := &SyntaxError{
+			Message: strings.TrimPrefix(detail.Detail, syntaxErrorPrefix),
+		}
+
+		if match := syntaxErrorPosition.FindStringSubmatch(detail.Detail); match != nil {
+			syntaxErr.Line, _ = strconv.Atoi(match[1])
+			syntaxErr.Column, _ = strconv.Atoi(match[2])
+		}
+
+		if match := syntaxErrorToken.FindStringSubmatch(detail.Detail); match != nil {
+			syntaxErr.Token = match[1]
+		}
+
+		return syntaxErr
+	}
+
+	return nil
+}
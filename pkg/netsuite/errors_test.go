@@ -0,0 +1,137 @@
+package netsuite
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestNetSuiteErrorFieldErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []FieldError
+	}{
+		{
+			name: "multiple fields",
+			body: `{
+				"type": "https://www.rfc-editor.org/rfc/rfc9110.html#section-15.5.1",
+				"title": "Bad Request",
+				"status": 400,
+				"o:errorDetails": [
+					{"detail": "Please enter value(s) for: Company Name.", "o:errorPath": "companyName", "o:errorCode": "USER_ERROR"},
+					{"detail": "Invalid email address.", "o:errorPath": "email", "o:errorCode": "INVALID_FLD_VALUE"},
+					{"detail": "Invalid subsidiary reference key 99.", "o:errorPath": "subsidiary.id", "o:errorCode": "INVALID_KEY_OR_REF"}
+				]
+			}`,
+			want: []FieldError{
+				{Field: "companyName", Message: "Please enter value(s) for: Company Name.", Code: "USER_ERROR"},
+				{Field: "email", Message: "Invalid email address.", Code: "INVALID_FLD_VALUE"},
+				{Field: "subsidiary.id", Message: "Invalid subsidiary reference key 99.", Code: "INVALID_KEY_OR_REF"},
+			},
+		},
+		{
+			name: "details without a path are skipped",
+			body: `{
+				"title": "Bad Request",
+				"o:errorDetails": [
+					{"detail": "Record has been changed.", "o:errorCode": "RCRD_HAS_BEEN_CHANGED"},
+					{"detail": "Invalid email address.", "o:errorPath": "email", "o:errorCode": "INVALID_FLD_VALUE"}
+				]
+			}`,
+			want: []FieldError{
+				{Field: "email", Message: "Invalid email address.", Code: "INVALID_FLD_VALUE"},
+			},
+		},
+		{
+			name: "no field errors",
+			body: `{"title": "Bad Request", "o:errorDetails": [{"detail": "Invalid search query.", "o:errorCode": "INVALID_PARAMETER"}]}`,
+			want: nil,
+		},
+		{
+			name: "not JSON",
+			body: `<html>Service Unavailable</html>`,
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nsErr := newNetSuiteError(&http.Response{StatusCode: http.StatusBadRequest}, []byte(tt.body))
+			if got := nsErr.FieldErrors(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FieldErrors() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNonJSONSnippet(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{name: "short", body: "<html>Maintenance</html>", want: "<html>Maintenance</html>"},
+		{name: "exact length", body: strings.Repeat("a", nonJSONSnippetLength), want: strings.Repeat("a", nonJSONSnippetLength)},
+		{name: "ascii", body: strings.Repeat("a", nonJSONSnippetLength+1), want: strings.Repeat("a", nonJSONSnippetLength) + "..."},
+		{
+			name: "multi-byte rune across the limit",
+			body: strings.Repeat("a", nonJSONSnippetLength-1) + "é" + "b",
+			want: strings.Repeat("a", nonJSONSnippetLength-1) + "...",
+		},
+		{
+			name: "multi-byte runes",
+			body: strings.Repeat("日", nonJSONSnippetLength),
+			want: strings.Repeat("日", nonJSONSnippetLength/3) + "...",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nonJSONSnippet([]byte(tt.body))
+			if got != tt.want {
+				t.Errorf("nonJSONSnippet() = %q, want %q", got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("nonJSONSnippet() = %q, which is not valid UTF-8", got)
+			}
+		})
+	}
+}
+
+func TestNetSuiteErrorIsGovernanceExceeded(t *testing.T) {
+	type test struct {
+		name    string
+		details []ErrorDetail
+		want    bool
+	}
+
+	tests := []test{
+		{name: "no details", want: false},
+		{name: "other code", details: []ErrorDetail{{ErrorCode: "INVALID_PARAMETER", Detail: "Invalid search query."}}, want: false},
+		{name: "detail mentioning a code in lower case", details: []ErrorDetail{{ErrorCode: "USER_ERROR", Detail: "sss_time_limit_exceeded"}}, want: false},
+		{name: "governance code after another detail", details: []ErrorDetail{{ErrorCode: "USER_ERROR"}, {ErrorCode: "SSS_USAGE_LIMIT_EXCEEDED"}}, want: true},
+	}
+	for code := range governanceErrorCodes {
+		tests = append(tests,
+			test{name: code + " code", details: []ErrorDetail{{ErrorCode: code, Detail: "Limit exceeded."}}, want: true},
+			test{name: code + " in detail", details: []ErrorDetail{{ErrorCode: "UNEXPECTED_ERROR", Detail: "Script Execution " + code + "."}}, want: true},
+		)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error = fmt.Errorf("failed to run query: %w", &NetSuiteError{StatusCode: http.StatusBadRequest, Details: tt.details})
+			if got := errors.Is(err, ErrGovernanceExceeded); got != tt.want {
+				t.Errorf("errors.Is(err, ErrGovernanceExceeded) = %v, want %v", got, tt.want)
+			}
+			if errors.Is(err, ErrNonJSONResponse) {
+				t.Errorf("errors.Is(err, ErrNonJSONResponse) = true, want false")
+			}
+		})
+	}
+}
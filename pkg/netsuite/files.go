@@ -0,0 +1,132 @@
+package netsuite
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ErrFileTooLarge is returned for files larger than the client's file size
+// limit, whose content is not read.
+var ErrFileTooLarge = errors.New("file too large")
+
+// DefaultMaxFileBytes is the size of the largest file DownloadFile returns by
+// default.
+const DefaultMaxFileBytes = 10 << 20
+
+// fileRecordOverhead is the room left in a file record response for the
+// fields besides the content.
+const fileRecordOverhead = 64 << 10
+
+// File is a file of the File Cabinet with its content.
+type File struct {
+	ID          int
+	Name        string
+	ContentType string
+	Content     []byte
+}
+
+// fileRecord is the part of a file record DownloadFile reads. The content of
+// text files is returned as is and that of other files base64-encoded, as with
+// the contents of a file in SuiteScript.
+type fileRecord struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// DownloadFile returns a file of the File Cabinet by internal ID, such as a
+// file attached to a record, with its content decoded. The content type is
+// derived from the file name. Files larger than MaxFileBytes fail with
+// ErrFileTooLarge without being read in full.
+func (c *Client) DownloadFile(ctx context.Context, fileID int) (*File, error) {
+	endpoint := "/record/v1/file/" + strconv.Itoa(fileID)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	response, err := c.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to GET %s: %w", request.URL.Path, err)
+	}
+	defer response.Body.Close()
+
+	// Binary content grows by a third when base64-encoded
+	limit := int64(base64.StdEncoding.EncodedLen(c.maxFileBytes) + fileRecordOverhead)
+	bodyBytes, err := io.ReadAll(io.LimitReader(response.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if int64(len(bodyBytes)) > limit {
+		return nil, fmt.Errorf("%w: file %d is larger than the limit of %d bytes", ErrFileTooLarge, fileID, c.maxFileBytes)
+	}
+
+	if err := checkJSONResponse(response, bodyBytes); err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, newNetSuiteError(response, bodyBytes)
+	}
+
+	var record fileRecord
+	if err := json.Unmarshal(bodyBytes, &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	file := &File{
+		ID:          fileID,
+		Name:        record.Name,
+		ContentType: fileContentType(record.Name),
+	}
+
+	if IsTextContentType(file.ContentType) {
+		file.Content = []byte(record.Content)
+	} else {
+		file.Content, err = base64.StdEncoding.DecodeString(record.Content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode content of file %d: %w", fileID, err)
+		}
+	}
+
+	if len(file.Content) > c.maxFileBytes {
+		return nil, fmt.Errorf("%w: file %d is %d bytes, over the limit of %d bytes", ErrFileTooLarge, fileID, len(file.Content), c.maxFileBytes)
+	}
+
+	return file, nil
+}
+
+// fileContentType returns the media type of a file from the extension of its
+// name, or application/octet-stream if it is not known.
+func fileContentType(name string) string {
+	contentType := mime.TypeByExtension(strings.ToLower(path.Ext(name)))
+	if contentType == "" {
+		return "application/octet-stream"
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "application/octet-stream"
+	}
+
+	return mediaType
+}
+
+// IsTextContentType reports whether files of the media type hold text, whose
+// content NetSuite returns as is rather than base64-encoded.
+func IsTextContentType(mediaType string) bool {
+	switch mediaType {
+	case "application/json", "application/xml", "application/javascript", "application/x-javascript":
+		return true
+	}
+
+	return strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml")
+}
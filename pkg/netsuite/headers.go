@@ -0,0 +1,74 @@
+package netsuite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrForbiddenHeader is returned for extra headers that would interfere with
+// authentication or the framing of requests.
+var ErrForbiddenHeader = errors.New("header cannot be overridden")
+
+// forbiddenHeaders are managed by the client and the HTTP transport.
+var forbiddenHeaders = map[string]struct{}{
+	"Authorization":       {},
+	"Proxy-Authorization": {},
+	"Cookie":              {},
+	"Host":                {},
+	"Connection":          {},
+	"Content-Length":      {},
+	"Content-Type":        {},
+	"Transfer-Encoding":   {},
+}
+
+// ValidateHeaders returns ErrForbiddenHeader if any of the headers is managed
+// by the client, such as Authorization.
+func ValidateHeaders(header http.Header) error {
+	for name := range header {
+		if _, ok := forbiddenHeaders[http.CanonicalHeaderKey(name)]; ok {
+			return fmt.Errorf("%w: %s", ErrForbiddenHeader, name)
+		}
+	}
+
+	return nil
+}
+
+type headersKey struct{}
+
+// WithHeaders returns a context whose requests to NetSuite carry the extra
+// headers, in addition to ClientOptions.Headers. The headers must pass
+// ValidateHeaders.
+func WithHeaders(ctx context.Context, header http.Header) context.Context {
+	return context.WithValue(ctx, headersKey{}, header)
+}
+
+// headerTransport adds the extra headers of the client and of the request
+// context to every request, except for token exchanges.
+type headerTransport struct {
+	next   http.RoundTripper
+	header http.Header
+}
+
+func (transport *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	contextHeader, _ := req.Context().Value(headersKey{}).(http.Header)
+	if isTokenRequest(req) || (len(transport.header) == 0 && len(contextHeader) == 0) {
+		return transport.next.RoundTrip(req)
+	}
+
+	if err := ValidateHeaders(contextHeader); err != nil {
+		return nil, err
+	}
+
+	req = req.Clone(req.Context())
+	for _, header := range []http.Header{transport.header, contextHeader} {
+		for name, values := range header {
+			for _, value := range values {
+				req.Header.Add(name, value)
+			}
+		}
+	}
+
+	return transport.next.RoundTrip(req)
+}
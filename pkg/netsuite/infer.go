@@ -0,0 +1,124 @@
+package netsuite
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/glints-dev/mcp-netsuite/pkg/jsonschematree"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// decimalPattern matches the strings NetSuite returns monetary and decimal
+// values as, such as "1250.00" or "-.5".
+var decimalPattern = regexp.MustCompile(`^-?\d*\.\d+$`)
+
+// integerPattern matches the strings of whole amounts, which a decimal
+// column may hold alongside fractional ones.
+var integerPattern = regexp.MustCompile(`^-?\d+$`)
+
+// InferSchema builds an object schema from sample rows. Each column is typed
+// after the values seen in it, falling back to string when the values
+// disagree or are all null. String columns whose values all look like
+// decimals, at least one with a fraction, get the "decimal" format, since
+// NetSuite returns amounts as strings. Every column is nullable, since a
+// sample cannot tell otherwise.
+func InferSchema(items []json.RawMessage) (*jsonschematree.Schema, error) {
+	columnTypes := make(map[string]map[string]struct{})
+	// decimalColumns is whether every string value seen in a column looks
+	// like a decimal and one had a fraction; columns not yet decided are
+	// absent
+	decimalColumns := make(map[string]bool)
+	for _, item := range items {
+		decoder := json.NewDecoder(bytes.NewReader(item))
+		decoder.UseNumber()
+
+		var row map[string]interface{}
+		if err := decoder.Decode(&row); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+		}
+
+		for column, value := range row {
+			if columnTypes[column] == nil {
+				columnTypes[column] = make(map[string]struct{})
+			}
+
+			if valueType := inferredType(value); valueType != gojsonschema.TYPE_NULL {
+				columnTypes[column][valueType] = struct{}{}
+			}
+
+			if text, ok := value.(string); ok {
+				switch {
+				case decimalPattern.MatchString(text):
+					if _, seen := decimalColumns[column]; !seen {
+						decimalColumns[column] = true
+					}
+				case integerPattern.MatchString(text):
+				default:
+					decimalColumns[column] = false
+				}
+			}
+		}
+	}
+
+	properties := make(map[string]*jsonschematree.Schema, len(columnTypes))
+	for column, types := range columnTypes {
+		columnType := gojsonschema.TYPE_STRING
+		if len(types) == 1 {
+			for onlyType := range types {
+				columnType = onlyType
+			}
+		}
+
+		properties[column] = jsonschematree.PrepareDummySchema(
+			[]string{columnType, gojsonschema.TYPE_NULL},
+		)
+		if columnType == gojsonschema.TYPE_STRING && decimalColumns[column] {
+			properties[column].Format = "decimal"
+		}
+	}
+
+	schema := jsonschematree.PrepareDummySchema([]string{gojsonschema.TYPE_OBJECT})
+	schema.Properties = properties
+
+	return schema, nil
+}
+
+func inferredType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return gojsonschema.TYPE_NULL
+	case bool:
+		return gojsonschema.TYPE_BOOLEAN
+	case json.Number:
+		return gojsonschema.TYPE_NUMBER
+	case []interface{}:
+		return gojsonschema.TYPE_ARRAY
+	case map[string]interface{}:
+		return gojsonschema.TYPE_OBJECT
+	default:
+		return gojsonschema.TYPE_STRING
+	}
+}
+
+// Preview returns the first n rows of a record type along with the schema
+// inferred from them.
+func (c *Client) Preview(ctx context.Context, recordType string, n int) (*SuiteQLResponse, *jsonschematree.Schema, error) {
+	if !identifierPattern.MatchString(recordType) {
+		return nil, nil, fmt.Errorf("invalid record type \"%s\"", recordType)
+	}
+
+	results, err := c.SuiteQLContext(ctx, fmt.Sprintf("SELECT * FROM %s", QuoteIdentifier(recordType)), n, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	schema, err := InferSchema(results.Items)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return results, schema, nil
+}
@@ -0,0 +1,68 @@
+package netsuite
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestInferSchema(t *testing.T) {
+	tests := []struct {
+		name       string
+		items      []string
+		wantType   map[string]string
+		wantFormat map[string]string
+	}{
+		{
+			name:       "decimal strings",
+			items:      []string{`{"id": "12", "foreigntotal": "1250.00", "memo": "Acme"}`, `{"id": "13", "foreigntotal": "-.5", "memo": null}`},
+			wantType:   map[string]string{"id": "string", "foreigntotal": "string", "memo": "string"},
+			wantFormat: map[string]string{"id": "", "foreigntotal": "decimal", "memo": ""},
+		},
+		{
+			name:       "whole amounts alongside fractions",
+			items:      []string{`{"amount": "10"}`, `{"amount": "10.25"}`},
+			wantType:   map[string]string{"amount": "string"},
+			wantFormat: map[string]string{"amount": "decimal"},
+		},
+		{
+			name:       "text after a decimal",
+			items:      []string{`{"code": "1.5"}`, `{"code": "A-1"}`, `{"code": "2.5"}`},
+			wantType:   map[string]string{"code": "string"},
+			wantFormat: map[string]string{"code": ""},
+		},
+		{
+			name:       "numbers and booleans",
+			items:      []string{`{"count": 3, "isinactive": false}`},
+			wantType:   map[string]string{"count": "number", "isinactive": "boolean"},
+			wantFormat: map[string]string{"count": "", "isinactive": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := make([]json.RawMessage, len(tt.items))
+			for i, item := range tt.items {
+				items[i] = json.RawMessage(item)
+			}
+
+			schema, err := InferSchema(items)
+			if err != nil {
+				t.Fatalf("InferSchema() error = %v", err)
+			}
+
+			gotType := make(map[string]string, len(schema.Properties))
+			gotFormat := make(map[string]string, len(schema.Properties))
+			for column, property := range schema.Properties {
+				gotType[column] = property.BaseType()
+				gotFormat[column] = property.Format
+			}
+			if !reflect.DeepEqual(gotType, tt.wantType) {
+				t.Errorf("types = %v, want %v", gotType, tt.wantType)
+			}
+			if !reflect.DeepEqual(gotFormat, tt.wantFormat) {
+				t.Errorf("formats = %v, want %v", gotFormat, tt.wantFormat)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package netsuite
+
+import (
+	"context"
+	"iter"
+)
+
+// maxPageSize is the largest page NetSuite returns for a SuiteQL query.
+const maxPageSize = 1000
+
+// SuiteQLSeq returns an iterator over every row of a SuiteQL query, fetching
+// pages of pageSize rows lazily as the loop advances. Pages after the first
+// follow the "next" link NetSuite returns, falling back to offsets without
+// one. Breaking out of the loop stops fetching further pages. If a page
+// fails, the error is yielded once with a nil row and the iteration ends. A
+// non-positive page size fetches the largest pages NetSuite allows.
+//
+// The context bounds the whole iteration rather than each page: every page is
+// requested with it, and once it is done no further page is fetched and its
+// error is yielded.
+//
+//	for row, err := range client.SuiteQLSeq(ctx, query, 1000) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (c *Client) SuiteQLSeq(ctx context.Context, query string, pageSize int) iter.Seq2[map[string]interface{}, error] {
+	// NetSuite expects the query to be posted again to the next link
+	return paginate(ctx, pageSize, func(ctx context.Context, limit int, offset int) (*SuiteQLResponse, error) {
+		return c.SuiteQLContext(ctx, query, limit, offset)
+	}, func(ctx context.Context, endpoint string) (*SuiteQLResponse, error) {
+		return c.suiteQLNext(ctx, query, endpoint)
+	})
+}
+
+// RecordsSeq returns an iterator over the references to the records of a
+// record type matching the filter, as returned by QueryRecords, with the same
+// semantics as SuiteQLSeq. Pages after the first are fetched by following the
+// "next" link NetSuite returns, so paging does not rely on offset arithmetic.
+func (c *Client) RecordsSeq(ctx context.Context, recordType string, filter string, pageSize int) iter.Seq2[map[string]interface{}, error] {
+	return paginate(ctx, pageSize, func(ctx context.Context, limit int, offset int) (*SuiteQLResponse, error) {
+		return c.QueryRecords(ctx, recordType, filter, limit, offset)
+	}, func(ctx context.Context, endpoint string) (*SuiteQLResponse, error) {
+		var page SuiteQLResponse
+		if err := c.getJSON(ctx, endpoint, &page); err != nil {
+			return nil, err
+		}
+		return &page, nil
+	})
+}
+
+// paginate returns an iterator over the rows of a paginated collection. The
+// first page is fetched at offset 0. Every further page is fetched by
+// following the "next" link of the previous one when followNext is given and
+// NetSuite provided the link, and at the offset after the previous page
+// otherwise.
+func paginate(
+	ctx context.Context,
+	pageSize int,
+	fetch func(ctx context.Context, limit int, offset int) (*SuiteQLResponse, error),
+	followNext func(ctx context.Context, endpoint string) (*SuiteQLResponse, error),
+) iter.Seq2[map[string]interface{}, error] {
+	if pageSize <= 0 {
+		pageSize = maxPageSize
+	}
+
+	return func(yield func(map[string]interface{}, error) bool) {
+		var page *SuiteQLResponse
+		for offset := 0; ; offset += len(page.Items) {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			var err error
+			switch {
+			case page == nil:
+				page, err = fetch(ctx, pageSize, 0)
+			case followNext != nil && page.next != "":
+				page, err = followNext(ctx, page.next)
+			default:
+				page, err = fetch(ctx, pageSize, offset)
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			rows, err := page.Rows()
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for _, row := range rows {
+				if !yield(row, nil) {
+					return
+				}
+			}
+
+			if !page.HasMore || len(rows) == 0 {
+				return
+			}
+		}
+	}
+}
+
+// SuiteQLAll fetches every row of a SuiteQL query. If a page fails, or the
+// context's deadline passes between pages, the rows collected so far are
+// returned along with the error, which matches context.DeadlineExceeded with
+// errors.Is when time ran out.
+func (c *Client) SuiteQLAll(ctx context.Context, query string, pageSize int) ([]map[string]interface{}, error) {
+	var rows []map[string]interface{}
+	for row, err := range c.SuiteQLSeq(ctx, query, pageSize) {
+		if err != nil {
+			return rows, err
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,203 @@
+package netsuite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// KeysetResponse is a page of results fetched with keyset pagination along
+// with the cursor of the next page.
+type KeysetResponse struct {
+	*SuiteQLResponse
+
+	// NextCursor holds the key column values of the last row, to be passed
+	// as "after" to fetch the next page. It is nil when the page is empty.
+	NextCursor []interface{} `json:"nextCursor"`
+}
+
+// SuiteQLKeyset fetches a page of the query ordered by the key columns,
+// starting after the row identified by the cursor. Unlike offsets, keysets
+// stay correct when rows are inserted between pages, and they support
+// composite keys such as (transaction, id) for tables like transactionline.
+// A nil cursor fetches the first page.
+func (c *Client) SuiteQLKeyset(query string, keyColumns []string, after []interface{}, limit int) (*KeysetResponse, error) {
+	return c.SuiteQLKeysetContext(context.Background(), query, keyColumns, after, limit)
+}
+
+// SuiteQLKeysetContext is like SuiteQLKeyset, but the request is bound to the
+// context.
+func (c *Client) SuiteQLKeysetContext(ctx context.Context, query string, keyColumns []string, after []interface{}, limit int) (*KeysetResponse, error) {
+	if len(keyColumns) == 0 {
+		return nil, fmt.Errorf("at least one key column is required")
+	}
+
+	if after != nil && len(after) != len(keyColumns) {
+		return nil, fmt.Errorf(
+			"cursor has %d values, but there are %d key columns",
+			len(after),
+			len(keyColumns),
+		)
+	}
+
+	if err := checkProjection(query, keyColumns); err != nil {
+		return nil, err
+	}
+
+	keysetQuery, err := keysetQuery(query, keyColumns, after)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := c.SuiteQLContext(ctx, keysetQuery, limit, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &KeysetResponse{SuiteQLResponse: results}
+
+	if len(results.Items) > 0 {
+		var lastRow map[string]interface{}
+		if err := json.Unmarshal(results.Items[len(results.Items)-1], &lastRow); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+		}
+
+		for _, keyColumn := range keyColumns {
+			response.NextCursor = append(response.NextCursor, lastRow[strings.ToLower(keyColumnName(keyColumn))])
+		}
+	}
+
+	return response, nil
+}
+
+// keyColumnName returns the name a key column is selected as. Key columns may
+// be qualified with the alias of their table within the query, which is out
+// of scope in the query wrapping it.
+func keyColumnName(keyColumn string) string {
+	if index := strings.LastIndex(keyColumn, "."); index >= 0 {
+		return keyColumn[index+1:]
+	}
+
+	return keyColumn
+}
+
+// keysetQuery wraps the query so that it only returns rows after the cursor
+// in key order. For keys (a, b) the condition is a > x OR (a = x AND b > y).
+func keysetQuery(query string, keyColumns []string, after []interface{}) (string, error) {
+	names := make([]string, len(keyColumns))
+	for i, keyColumn := range keyColumns {
+		names[i] = QuoteIdentifier(keyColumnName(keyColumn))
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "SELECT * FROM (%s)", query)
+
+	if after != nil {
+		var alternatives []string
+		for i := range keyColumns {
+			var conditions []string
+			for j := 0; j < i; j++ {
+				literal, err := keysetLiteral(after[j])
+				if err != nil {
+					return "", err
+				}
+				conditions = append(conditions, fmt.Sprintf("%s = %s", names[j], literal))
+			}
+
+			literal, err := keysetLiteral(after[i])
+			if err != nil {
+				return "", err
+			}
+			conditions = append(conditions, fmt.Sprintf("%s > %s", names[i], literal))
+
+			alternatives = append(alternatives, "("+strings.Join(conditions, " AND ")+")")
+		}
+
+		fmt.Fprintf(&builder, " WHERE %s", strings.Join(alternatives, " OR "))
+	}
+
+	fmt.Fprintf(&builder, " ORDER BY %s", strings.Join(names, ", "))
+
+	return builder.String(), nil
+}
+
+// keysetLiteral formats a cursor value as a SuiteQL literal. The JSON type of
+// the value decides the literal, so that strings such as "00123" stay strings.
+// NetSuite returns IDs as strings, which compare correctly with numeric
+// columns when quoted.
+func keysetLiteral(value interface{}) (string, error) {
+	switch value := value.(type) {
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	case json.Number:
+		return value.String(), nil
+	case string:
+		return QuoteLiteral(value), nil
+	default:
+		return "", fmt.Errorf("unsupported cursor value %v", value)
+	}
+}
+
+var projectionPattern = regexp.MustCompile(`(?is)^\s*SELECT\s+(?:DISTINCT\s+)?(.*?)\s+FROM\s`)
+
+// checkProjection returns an error if a key column is not selected by the
+// query, or if the projection has an empty expression. Queries selecting "*"
+// are accepted as is.
+func checkProjection(query string, keyColumns []string) error {
+	match := projectionPattern.FindStringSubmatch(query)
+	if match == nil {
+		return fmt.Errorf("unable to find the projection of the query")
+	}
+
+	selected := make(map[string]struct{})
+	for _, expression := range splitTopLevel(match[1]) {
+		expression = strings.TrimSpace(expression)
+		if expression == "*" || strings.HasSuffix(expression, ".*") {
+			return nil
+		}
+
+		fields := strings.Fields(expression)
+		if len(fields) == 0 {
+			return fmt.Errorf("the projection of the query has an empty expression")
+		}
+
+		selected[strings.ToLower(keyColumnName(fields[len(fields)-1]))] = struct{}{}
+	}
+
+	for _, keyColumn := range keyColumns {
+		if _, ok := selected[strings.ToLower(keyColumnName(keyColumn))]; !ok {
+			return fmt.Errorf("key column %s is not selected by the query", keyColumn)
+		}
+	}
+
+	return nil
+}
+
+// splitTopLevel splits a list of expressions on commas that are not nested
+// within parentheses or string literals.
+func splitTopLevel(list string) []string {
+	var parts []string
+	depth := 0
+	inLiteral := false
+	start := 0
+
+	for i, r := range list {
+		switch {
+		case r == '\'':
+			inLiteral = !inLiteral
+		case inLiteral:
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			parts = append(parts, list[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, list[start:])
+}
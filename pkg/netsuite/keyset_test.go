@@ -0,0 +1,105 @@
+package netsuite
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestKeysetQuery(t *testing.T) {
+	tests := []struct {
+		name       string
+		keyColumns []string
+		after      []interface{}
+		want       string
+	}{
+		{
+			name:       "first page",
+			keyColumns: []string{"id"},
+			want:       "SELECT * FROM (SELECT id FROM customer) ORDER BY id",
+		},
+		{
+			name:       "composite key",
+			keyColumns: []string{"transaction", "id"},
+			after:      []interface{}{json.Number("12"), json.Number("3")},
+			want:       "SELECT * FROM (SELECT id FROM customer) WHERE (transaction > 12) OR (transaction = 12 AND id > 3) ORDER BY transaction, id",
+		},
+		{
+			name:       "qualified key column",
+			keyColumns: []string{"t.id"},
+			after:      []interface{}{float64(42)},
+			want:       "SELECT * FROM (SELECT id FROM customer) WHERE (id > 42) ORDER BY id",
+		},
+		{
+			name:       "numeric string",
+			keyColumns: []string{"tranid"},
+			after:      []interface{}{"00123"},
+			want:       "SELECT * FROM (SELECT id FROM customer) WHERE (tranid > '00123') ORDER BY tranid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := keysetQuery("SELECT id FROM customer", tt.keyColumns, tt.after)
+			if err != nil {
+				t.Fatalf("keysetQuery() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("keysetQuery() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeysetLiteral(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    string
+		wantErr bool
+	}{
+		{name: "number", value: float64(42), want: "42"},
+		{name: "json number", value: json.Number("42"), want: "42"},
+		{name: "numeric string", value: "00123", want: "'00123'"},
+		{name: "string", value: "O'Brien", want: "'O''Brien'"},
+		{name: "null", value: nil, wantErr: true},
+		{name: "boolean", value: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := keysetLiteral(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("keysetLiteral(%v) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("keysetLiteral(%v) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckProjection(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		keyColumns []string
+		wantErr    bool
+	}{
+		{name: "selected", query: "SELECT id, entityid FROM customer", keyColumns: []string{"id"}},
+		{name: "star", query: "SELECT * FROM customer", keyColumns: []string{"id"}},
+		{name: "aliased", query: "SELECT t.id AS tid FROM transaction t", keyColumns: []string{"tid"}},
+		{name: "qualified", query: "SELECT t.id FROM transaction t", keyColumns: []string{"t.id"}},
+		{name: "not selected", query: "SELECT entityid FROM customer", keyColumns: []string{"id"}, wantErr: true},
+		{name: "empty expression", query: "SELECT id, , entityid FROM customer", keyColumns: []string{"id"}, wantErr: true},
+		{name: "trailing comma", query: "SELECT id, FROM customer", keyColumns: []string{"id"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkProjection(tt.query, tt.keyColumns)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkProjection() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
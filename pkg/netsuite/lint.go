@@ -0,0 +1,219 @@
+package netsuite
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LintIssue is a likely mistake found in a SuiteQL query by LintSuiteQL.
+type LintIssue struct {
+	Column  string `json:"column"`
+	Message string `json:"message"`
+
+	// Suggestions holds similar columns of the table, for unknown columns.
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// LintResult is the outcome of LintSuiteQL.
+type LintResult struct {
+	Table  string      `json:"table"`
+	Issues []LintIssue `json:"issues"`
+
+	// Warnings explain what was not checked.
+	Warnings []string `json:"warnings"`
+}
+
+// lintKeywords are words other than reserved words and column names that
+// appear bare in SuiteQL queries, including pseudocolumns such as
+// CURRENT_DATE. Reserved words such as SYSDATE and ROWNUM are covered by
+// reservedWords.
+var lintKeywords = map[string]struct{}{
+	"CASE": {}, "WHEN": {}, "END": {}, "JOIN": {}, "LEFT": {}, "RIGHT": {},
+	"INNER": {}, "OUTER": {}, "FULL": {}, "CROSS": {}, "LIMIT": {},
+	"OFFSET": {}, "FETCH": {}, "FIRST": {}, "NEXT": {}, "ONLY": {},
+	"TRUE": {}, "FALSE": {}, "ESCAPE": {}, "INTERVAL": {}, "DAY": {},
+	"MONTH": {}, "YEAR": {}, "HOUR": {}, "MINUTE": {}, "SECOND": {},
+	"NULLS": {}, "LAST": {}, "OVER": {}, "PARTITION": {}, "SOME": {},
+	"BUILTIN": {}, "DUAL": {}, "CURRENT_DATE": {}, "CURRENT_TIMESTAMP": {},
+	"SYSTIMESTAMP": {}, "LOCALTIMESTAMP": {}, "DBTIMEZONE": {},
+	"SESSIONTIMEZONE": {}, "TIMESTAMP": {}, "RANGE": {}, "UNBOUNDED": {},
+	"PRECEDING": {}, "FOLLOWING": {}, "WITHIN": {},
+}
+
+var (
+	lintComment       = regexp.MustCompile(`(?s)--[^\n]*|/\*.*?\*/`)
+	lintQuotedName    = regexp.MustCompile(`"(?:[^"]|"")*"`)
+	lintIdentifier    = regexp.MustCompile(`(?:\b[A-Za-z_][A-Za-z0-9_]*\s*\.\s*)?\b[A-Za-z_][A-Za-z0-9_]*\b\s*\(?`)
+	lintAlias         = regexp.MustCompile(`(?i)\bAS\s+([A-Za-z_][A-Za-z0-9_]*)`)
+	lintTableAlias    = regexp.MustCompile(`(?i)\b(?:FROM|JOIN)\s+([A-Za-z_][A-Za-z0-9_]*)(?:\s+(?:AS\s+)?([A-Za-z_][A-Za-z0-9_]*))?`)
+	lintJoin          = regexp.MustCompile(`(?i)\bJOIN\b|\bFROM\s+[A-Za-z_][A-Za-z0-9_]*(?:\s+[A-Za-z_][A-Za-z0-9_]*)?\s*,`)
+	lintParameter     = regexp.MustCompile(`:[A-Za-z_][A-Za-z0-9_]*`)
+	lintComparison    = regexp.MustCompile(`(?i)\b((?:[A-Za-z_][A-Za-z0-9_]*\.)?[A-Za-z_][A-Za-z0-9_]*)\s*(=|<>|!=|<=|>=|<|>)\s*('(?:[^']|'')*'|-?\d+(?:\.\d+)?)`)
+	lintNumericString = regexp.MustCompile(`^'-?\d+(?:\.\d+)?'$`)
+)
+
+// LintSuiteQL checks the columns a query refers to against the columns of
+// its FROM table, flagging unknown columns and comparisons of a column with a
+// literal of the wrong type, so that mistakes are caught without spending a
+// request. It is best-effort: with joins, only columns qualified with the
+// FROM table or its alias are checked, and subqueries are not told apart.
+func (c *Client) LintSuiteQL(query string) (*LintResult, error) {
+	table := SourceTable(query)
+	if table == "" {
+		return nil, fmt.Errorf("unable to find the FROM table of the query")
+	}
+
+	columns, err := c.FieldCatalog(table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the columns of %s: %w", table, err)
+	}
+
+	return lintQuery(query, table, columns), nil
+}
+
+// lintQuery checks the column references of a query against the columns of
+// its FROM table.
+func lintQuery(query string, table string, columns []Column) *LintResult {
+	known := make(map[string]Column, len(columns))
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		known[column.Name] = column
+		names = append(names, column.Name)
+	}
+
+	result := &LintResult{Table: table, Issues: []LintIssue{}, Warnings: []string{}}
+
+	// Literals and comments cannot hold column references
+	stripped := stripLiteralsAndComments(query)
+	stripped = lintQuotedName.ReplaceAllString(stripped, " ")
+	stripped = lintParameter.ReplaceAllString(stripped, " ")
+
+	// Names that are not columns of the table: tables, their aliases, and
+	// the aliases of selected expressions
+	ignored := make(map[string]struct{})
+	tableAlias := table
+	for _, match := range lintTableAlias.FindAllStringSubmatch(stripped, -1) {
+		ignored[strings.ToLower(match[1])] = struct{}{}
+		if match[2] == "" || isKeyword(match[2]) {
+			continue
+		}
+
+		alias := strings.ToLower(match[2])
+		ignored[alias] = struct{}{}
+		if strings.EqualFold(match[1], table) && tableAlias == table {
+			tableAlias = alias
+		}
+	}
+	for _, match := range lintAlias.FindAllStringSubmatch(stripped, -1) {
+		ignored[strings.ToLower(match[1])] = struct{}{}
+	}
+
+	hasJoins := lintJoin.MatchString(stripped)
+	if hasJoins {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("The query joins other tables, so only columns qualified with %s were checked", tableAlias))
+	}
+
+	reported := make(map[string]struct{})
+	for _, reference := range lintIdentifier.FindAllString(stripped, -1) {
+		// Function calls such as COUNT( or BUILTIN.DF( are not columns
+		if strings.HasSuffix(reference, "(") {
+			continue
+		}
+
+		reference = strings.ToLower(strings.Join(strings.Fields(reference), ""))
+		qualifier, column, qualified := strings.Cut(reference, ".")
+		if !qualified {
+			column, qualifier = qualifier, ""
+		}
+
+		switch {
+		case qualified && qualifier != tableAlias && qualifier != table:
+			continue
+		case !qualified && hasJoins:
+			continue
+		case !qualified && (isKeyword(column) || isIgnored(ignored, column)):
+			continue
+		}
+
+		if _, ok := known[column]; ok {
+			continue
+		}
+		if _, ok := reported[column]; ok {
+			continue
+		}
+		reported[column] = struct{}{}
+
+		result.Issues = append(result.Issues, LintIssue{
+			Column:      column,
+			Message:     fmt.Sprintf("column %s not found on table %s", column, table),
+			Suggestions: closestNames(column, names, maxColumnSuggestions),
+		})
+	}
+
+	// Compare columns with literals of their type, using the query with its
+	// literals intact
+	for _, match := range lintComparison.FindAllStringSubmatch(lintComment.ReplaceAllString(query, " "), -1) {
+		reference := strings.ToLower(match[1])
+		qualifier, columnName, qualified := strings.Cut(reference, ".")
+		if !qualified {
+			columnName, qualifier = qualifier, ""
+		}
+		if (qualified && qualifier != tableAlias && qualifier != table) || (!qualified && hasJoins) {
+			continue
+		}
+
+		column, ok := known[columnName]
+		if !ok {
+			continue
+		}
+
+		if message := literalMismatch(column, match[3]); message != "" {
+			result.Issues = append(result.Issues, LintIssue{
+				Column:  columnName,
+				Message: fmt.Sprintf("%s %s %s: %s", match[1], match[2], match[3], message),
+			})
+		}
+	}
+
+	return result
+}
+
+// literalMismatch explains why a literal cannot be compared with a column, or
+// returns an empty string if it can.
+func literalMismatch(column Column, literal string) string {
+	isString := strings.HasPrefix(literal, "'")
+
+	switch column.Type {
+	case "integer", "number":
+		if isString && !lintNumericString.MatchString(literal) {
+			return fmt.Sprintf("%s is a numeric column, but is compared with a non-numeric string", column.Name)
+		}
+	case "boolean":
+		if literal != "'T'" && literal != "'F'" {
+			return fmt.Sprintf("%s is a boolean column, which SuiteQL compares with 'T' or 'F'", column.Name)
+		}
+	case "string":
+		if column.Format == "date" || column.Format == "date-time" {
+			if _, err := strconv.ParseFloat(literal, 64); err == nil {
+				return fmt.Sprintf("%s is a date column, but is compared with a number; use TO_DATE or a date string", column.Name)
+			}
+		}
+	}
+
+	return ""
+}
+
+func isKeyword(word string) bool {
+	upper := strings.ToUpper(word)
+	_, reserved := reservedWords[upper]
+	_, keyword := lintKeywords[upper]
+
+	return reserved || keyword
+}
+
+func isIgnored(ignored map[string]struct{}, word string) bool {
+	_, ok := ignored[word]
+	return ok
+}
@@ -0,0 +1,64 @@
+package netsuite
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLintQuery(t *testing.T) {
+	columns := []Column{
+		{Name: "duedate", Type: "string", Format: "date"},
+		{Name: "entity", Type: "integer", Reference: "customer"},
+		{Name: "foreignamountremaining", Type: "number"},
+		{Name: "foreigntotal", Type: "number"},
+		{Name: "id", Type: "integer"},
+		{Name: "trandate", Type: "string", Format: "date"},
+		{Name: "tranid", Type: "string"},
+		{Name: "type", Type: "string"},
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name: "overdue invoices report",
+			query: `SELECT t.id, t.tranid, BUILTIN.DF(t.entity) AS customer, t.trandate, t.duedate, t.foreigntotal, t.foreignamountremaining
+FROM transaction t
+WHERE t.type = 'CustInvc' AND t.foreignamountremaining > 0 AND t.duedate < CURRENT_DATE
+ORDER BY t.duedate`,
+		},
+		{
+			name:  "sysdate and rownum",
+			query: "SELECT id FROM transaction WHERE trandate > SYSDATE - 30 AND ROWNUM <= 10",
+		},
+		{
+			name:  "current timestamp and systimestamp",
+			query: "SELECT id, CURRENT_TIMESTAMP AS now FROM transaction WHERE trandate < SYSTIMESTAMP",
+		},
+		{
+			name:  "lower case pseudocolumn",
+			query: "select id from transaction where duedate < current_date",
+		},
+		{
+			name:  "unknown column",
+			query: "SELECT id, tranid, duedat FROM transaction WHERE duedat < CURRENT_DATE",
+			want:  []string{"duedat"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := lintQuery(tt.query, "transaction", columns)
+
+			var got []string
+			for _, issue := range result.Issues {
+				got = append(got, issue.Column)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lintQuery() issues = %+v, want issues for %v", result.Issues, tt.want)
+			}
+		})
+	}
+}
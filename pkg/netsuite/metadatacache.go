@@ -0,0 +1,168 @@
+package netsuite
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/glints-dev/mcp-netsuite/pkg/jsonschematree"
+)
+
+// DefaultMetadataCacheSize is the number of schemas kept in the metadata
+// cache by default.
+const DefaultMetadataCacheSize = 500
+
+// metadataCache keeps the most recently used schemas, evicting the least
+// recently used one once it holds maxEntries. With a TTL, schemas also expire
+// once they are older than it. It is safe for concurrent use.
+//
+// The catalog documents schemas are fetched with are kept apart from the
+// schemas, keyed by the record type they were fetched for, so that references
+// within a document resolve without fetching it again even once the schemas
+// of the document were evicted. Documents only expire with the TTL, and are
+// only replaced by a newer document of the same record type.
+type metadataCache struct {
+	maxEntries int
+	ttl        time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+
+	documents map[string]*metadataDocument
+
+	// documentOf names the record type whose document holds a schema, for
+	// schemas that were not fetched with a document of their own.
+	documentOf map[string]string
+}
+
+type metadataCacheEntry struct {
+	recordType string
+	schema     *jsonschematree.Schema
+
+	// hash is the schema's Hash, to tell whether a fetched schema changed.
+	// It is empty if the schema could not be hashed.
+	hash string
+
+	storedAt time.Time
+}
+
+// metadataDocument holds every schema of a catalog document.
+type metadataDocument struct {
+	schemas  map[string]*jsonschematree.Schema
+	storedAt time.Time
+}
+
+func newMetadataCache(maxEntries int, ttl time.Duration) *metadataCache {
+	return &metadataCache{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+		documents:  make(map[string]*metadataDocument),
+		documentOf: make(map[string]string),
+	}
+}
+
+// get returns the cached entry of a record type, if it has not expired.
+func (cache *metadataCache) get(recordType string) (*metadataCacheEntry, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	element, ok := cache.entries[recordType]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*metadataCacheEntry)
+	if cache.ttl > 0 && time.Since(entry.storedAt) > cache.ttl {
+		cache.order.Remove(element)
+		delete(cache.entries, recordType)
+		return nil, false
+	}
+
+	cache.order.MoveToFront(element)
+
+	return entry, true
+}
+
+// document returns the catalog document holding the schema of a record type:
+// the one it was fetched with, or else the one it was cached as part of. It
+// returns nil if neither is cached or has not expired.
+func (cache *metadataCache) document(recordType string) map[string]*jsonschematree.Schema {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	for _, owner := range []string{recordType, cache.documentOf[recordType]} {
+		document, ok := cache.documents[owner]
+		if !ok {
+			continue
+		}
+		if cache.ttl > 0 && time.Since(document.storedAt) > cache.ttl {
+			delete(cache.documents, owner)
+			continue
+		}
+
+		return document.schemas
+	}
+
+	return nil
+}
+
+// putDocument caches the catalog document a record type was fetched with,
+// replacing its previous document. Every schema of the document is also
+// cached as an entry of its own.
+func (cache *metadataCache) putDocument(recordType string, document map[string]*jsonschematree.Schema) {
+	for name, schema := range document {
+		cache.put(name, schema)
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.documents[recordType] = &metadataDocument{
+		schemas:  document,
+		storedAt: time.Now(),
+	}
+	for name := range document {
+		if name != recordType {
+			cache.documentOf[name] = recordType
+		}
+	}
+}
+
+// put caches the schema of a record type, replacing any previous entry.
+func (cache *metadataCache) put(recordType string, schema *jsonschematree.Schema) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	// Schemas that cannot be hashed are always taken as changed
+	hash, _ := schema.Hash()
+	entry := &metadataCacheEntry{
+		recordType: recordType,
+		schema:     schema,
+		hash:       hash,
+		storedAt:   time.Now(),
+	}
+
+	if element, ok := cache.entries[recordType]; ok {
+		// An unchanged schema keeps its cached copy, so that schemas already
+		// handed out stay identical to it, and only its age is reset
+		previous := element.Value.(*metadataCacheEntry)
+		if entry.hash != "" && previous.hash == entry.hash {
+			previous.storedAt = entry.storedAt
+		} else {
+			element.Value = entry
+		}
+		cache.order.MoveToFront(element)
+		return
+	}
+
+	cache.entries[recordType] = cache.order.PushFront(entry)
+
+	for cache.order.Len() > cache.maxEntries {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*metadataCacheEntry).recordType)
+	}
+}
@@ -0,0 +1,59 @@
+package netsuite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glints-dev/mcp-netsuite/pkg/jsonschematree"
+)
+
+func TestMetadataCacheDocuments(t *testing.T) {
+	customer := &jsonschematree.Schema{Title: "customer"}
+	subsidiary := &jsonschematree.Schema{Title: "subsidiary"}
+	salesOrder := &jsonschematree.Schema{Title: "salesorder"}
+	changedCustomer := &jsonschematree.Schema{Title: "customer", Description: "changed"}
+
+	customerDocument := map[string]*jsonschematree.Schema{"customer": customer, "subsidiary": subsidiary}
+	salesOrderDocument := map[string]*jsonschematree.Schema{"salesorder": salesOrder, "customer": changedCustomer}
+
+	tests := []struct {
+		name       string
+		maxEntries int
+		ttl        time.Duration
+		wait       time.Duration
+		documents  []string
+		recordType string
+		want       map[string]*jsonschematree.Schema
+	}{
+		{name: "own document", maxEntries: 10, documents: []string{"customer"}, recordType: "customer", want: customerDocument},
+		{name: "document cached as part of", maxEntries: 10, documents: []string{"customer"}, recordType: "subsidiary", want: customerDocument},
+		{name: "kept after the entries are evicted", maxEntries: 1, documents: []string{"customer", "salesorder"}, recordType: "subsidiary", want: customerDocument},
+		{name: "own document kept over another one", maxEntries: 10, documents: []string{"customer", "salesorder"}, recordType: "customer", want: customerDocument},
+		{name: "not cached", maxEntries: 10, documents: []string{"customer"}, recordType: "invoice", want: nil},
+		{name: "expired", maxEntries: 10, ttl: time.Millisecond, wait: 2 * time.Millisecond, documents: []string{"customer"}, recordType: "customer", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := newMetadataCache(tt.maxEntries, tt.ttl)
+			for _, recordType := range tt.documents {
+				document := customerDocument
+				if recordType == "salesorder" {
+					document = salesOrderDocument
+				}
+				cache.putDocument(recordType, document)
+			}
+			time.Sleep(tt.wait)
+
+			got := cache.document(tt.recordType)
+			if len(got) != len(tt.want) {
+				t.Fatalf("document(%q) has %d schemas, want %d", tt.recordType, len(got), len(tt.want))
+			}
+			for name, schema := range tt.want {
+				if got[name] != schema {
+					t.Errorf("document(%q)[%q] = %p, want %p", tt.recordType, name, got[name], schema)
+				}
+			}
+		})
+	}
+}
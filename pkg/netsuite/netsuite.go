@@ -3,12 +3,16 @@ package netsuite
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"path"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/glints-dev/mcp-netsuite/pkg/jsonschematree"
@@ -20,25 +24,91 @@ import (
 // Client is the type representing a NetSuite REST client
 type Client struct {
 	*http.Client
+
+	tokenSource    oauth2.TokenSource
+	transient      bool
+	suiteQLRetries int
+	defaultLimit   int
+	maxFileBytes   int
+
+	preferences      *Preferences
+	preferencesMutex sync.Mutex
+
+	recordTypes      map[string]struct{}
+	recordTypesMutex sync.Mutex
+
+	fieldOptions      map[string][]FieldOption
+	fieldOptionsMutex sync.Mutex
+
+	customFields      map[string]CustomField
+	customFieldsMutex sync.Mutex
+
+	subsidiary        int
+	subsidiaries      map[int]struct{}
+	subsidiariesMutex sync.Mutex
+
+	metadataCache *metadataCache
 }
 
 type netsuiteAPIHTTPTransport struct {
-	accountID string
+	baseURL *url.URL
+	next    http.RoundTripper
+}
+
+// accountHost returns the hostname label of an account. Account IDs are
+// issued in upper case with an underscore for sandboxes (e.g. "1234567_SB1"),
+// while the hostname is lower case and hyphenated (e.g. "1234567-sb1"). The
+// account ID itself must be used verbatim everywhere else.
+func accountHost(accountID string) string {
+	return strings.ToLower(strings.ReplaceAll(accountID, "_", "-"))
+}
+
+// apiBaseURL returns the URL of the REST services root requests are sent to.
+// The host is derived from the account ID unless it is overridden, either
+// with a bare host, reached over HTTPS, or with a URL including the scheme,
+// e.g. "http://localhost:8080" for a local mock.
+func apiBaseURL(options ClientOptions) (*url.URL, error) {
+	base := fmt.Sprintf("https://%s.suitetalk.api.netsuite.com", accountHost(options.AccountID))
+	if options.APIHostOverride != "" {
+		base = options.APIHostOverride
+		if !strings.Contains(base, "://") {
+			base = "https://" + base
+		}
+	}
+
+	parsed, err := url.Parse(base)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid API host %q", base)
+	}
+
+	parsed.Path = strings.TrimSuffix(parsed.Path, "/") + "/services/rest"
+	parsed.RawPath = ""
+
+	return parsed, nil
 }
 
+// RoundTrip sends the request to the REST services root. The target URL is
+// built from the parts of the request URL rather than its string form, so
+// that the escaping of the path and the query string are kept as they are.
+// Fragments are never sent.
 func (transport *netsuiteAPIHTTPTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	fullURL, err := url.Parse(fmt.Sprintf(
-		"https://%s.suitetalk.api.netsuite.com/services/rest%s",
-		transport.accountID,
-		req.URL.String(),
-	))
-	if err != nil {
-		return nil, fmt.Errorf("unable to parse URL: %w", err)
+	target := *req.URL
+	target.Scheme = transport.baseURL.Scheme
+	target.Host = transport.baseURL.Host
+	target.User = transport.baseURL.User
+	target.Path = transport.baseURL.Path + req.URL.Path
+	if req.URL.RawPath != "" {
+		target.RawPath = transport.baseURL.EscapedPath() + req.URL.RawPath
 	}
+	target.Fragment = ""
+	target.RawFragment = ""
 
-	req.URL = fullURL
+	// Round trippers must not modify the request they are given
+	outgoing := req.Clone(req.Context())
+	outgoing.URL = &target
+	outgoing.Host = ""
 
-	return http.DefaultTransport.RoundTrip(req)
+	return transport.next.RoundTrip(outgoing)
 }
 
 type ClientOptions struct {
@@ -48,21 +118,196 @@ type ClientOptions struct {
 	CertificateID      string
 	PrivateKeyBytes    []byte
 	PrivateKeyPassword string
+
+	// SigningAlgorithm is the algorithm the client assertion is signed with,
+	// which must match the certificate uploaded to NetSuite. Defaults to
+	// SigningAlgorithmPS256.
+	SigningAlgorithm SigningAlgorithm
+
+	// DisableTransientQueries stops sending "Prefer: transient" with SuiteQL
+	// queries, so that NetSuite uses its default execution mode.
+	DisableTransientQueries bool
+
+	// CassettePath, when set, records every interaction with NetSuite to the
+	// file or replays them from it, depending on CassetteMode. Authorization
+	// headers and token exchanges are redacted in recordings.
+	CassettePath string
+	CassetteMode CassetteMode
+
+	// TraceRequests logs a line to stderr for every request sent to NetSuite.
+	TraceRequests bool
+
+	// MaxConcurrency caps the number of requests in flight to NetSuite, so
+	// that concurrent tools stay within the account's concurrency governance
+	// limit. Defaults to DefaultMaxConcurrency.
+	MaxConcurrency int
+
+	// MaxIdleConnsPerHost is the number of idle connections kept open to
+	// NetSuite for reuse. Defaults to the concurrency limit, so that every
+	// request let through by the semaphore can reuse a connection instead of
+	// opening a new one.
+	MaxIdleConnsPerHost int
+
+	// IdleConnTimeout is how long an idle connection is kept open. Defaults
+	// to DefaultIdleConnTimeout.
+	IdleConnTimeout time.Duration
+
+	// DisableHTTP2 stops attempting HTTP/2, which is otherwise negotiated
+	// with NetSuite. With HTTP/2, concurrent requests are multiplexed over a
+	// single connection, so the semaphore rather than the connection pool
+	// bounds them.
+	DisableHTTP2 bool
+
+	// CircuitBreakerThreshold is the number of consecutive failed requests
+	// after which requests fail fast with ErrCircuitOpen instead of waiting
+	// on an unavailable NetSuite. Defaults to
+	// DefaultCircuitBreakerThreshold; a negative value disables the breaker.
+	CircuitBreakerThreshold int
+
+	// CircuitBreakerCooldown is how long requests fail fast before one is let
+	// through to test recovery. Defaults to DefaultCircuitBreakerCooldown.
+	CircuitBreakerCooldown time.Duration
+
+	// MetadataCacheSize is the number of schemas kept in memory, evicting
+	// the least recently used ones beyond it. Defaults to
+	// DefaultMetadataCacheSize.
+	MetadataCacheSize int
+
+	// MetadataCacheTTL is how long a cached schema is used before it is
+	// fetched again. Schemas do not expire by default.
+	MetadataCacheTTL time.Duration
+
+	// SuiteQLRetries is the number of times a SuiteQL query is resent after
+	// a network error such as a reset connection. Queries are read-only, so
+	// resending them is safe. Defaults to DefaultSuiteQLRetries; a negative
+	// value disables retries.
+	SuiteQLRetries int
+
+	// APIHostOverride replaces the host derived from AccountID
+	// ({account}.suitetalk.api.netsuite.com) that requests are sent to, e.g.
+	// to point integration tests at a mock. It is either a host, reached over
+	// HTTPS, or a URL with a scheme such as "http://localhost:8080".
+	APIHostOverride string
+
+	// DefaultLimit is the number of rows returned when a tool is not given a
+	// limit. Defaults to DefaultLimit, and is capped at MaxLimit.
+	DefaultLimit int
+
+	// TokenSource, when set, supplies the access tokens of requests, e.g.
+	// from a credentials broker, instead of the client assertion signed with
+	// the private key. ClientID, ClientSecret, CertificateID,
+	// PrivateKeyBytes, PrivateKeyPassword, and SigningAlgorithm are then
+	// ignored.
+	TokenSource oauth2.TokenSource
+
+	// RequestBudget caps the number of requests sent to NetSuite per
+	// RequestBudgetWindow, so that a single caller cannot use up the
+	// account's governance. Requests beyond it fail with ErrBudgetExceeded
+	// until the window ends. There is no budget by default.
+	RequestBudget int
+
+	// RequestBudgetWindow is the window RequestBudget applies to. Defaults
+	// to DefaultRequestBudgetWindow.
+	RequestBudgetWindow time.Duration
+
+	// MaxFileBytes is the size of the largest file DownloadFile returns.
+	// Defaults to DefaultMaxFileBytes.
+	MaxFileBytes int
+
+	// Subsidiary is the internal ID of the subsidiary records are written
+	// for, unless overridden with WithSubsidiary. It is set on the records
+	// that have a subsidiary field and do not set it themselves, once it is
+	// found to exist. There is no default subsidiary by default.
+	Subsidiary int
+
+	// Headers are added to every request, to toggle NetSuite features such
+	// as X-NetSuite-PropertyNameValidation. Headers managed by the client,
+	// such as Authorization, are rejected.
+	Headers http.Header
 }
 
-func NewClient(options ClientOptions) (*Client, error) {
+// DefaultLimit is the number of rows returned when a query is not limited.
+const DefaultLimit = 100
+
+// MaxLimit is the number of rows NetSuite returns in a single page at most.
+const MaxLimit = 1000
+
+// DefaultMaxConcurrency is the concurrency limit NetSuite grants accounts
+// without SuiteCloud Plus licenses.
+const DefaultMaxConcurrency = 5
+
+// DefaultIdleConnTimeout is how long idle connections to NetSuite are kept
+// open by default.
+const DefaultIdleConnTimeout = 90 * time.Second
+
+// newHTTPTransport returns the transport used to reach NetSuite, tuned for a
+// pool of at most maxConcurrency requests in flight.
+func newHTTPTransport(options ClientOptions, maxConcurrency int) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	transport.MaxIdleConnsPerHost = options.MaxIdleConnsPerHost
+	if transport.MaxIdleConnsPerHost <= 0 {
+		transport.MaxIdleConnsPerHost = maxConcurrency
+	}
+
+	transport.IdleConnTimeout = options.IdleConnTimeout
+	if transport.IdleConnTimeout <= 0 {
+		transport.IdleConnTimeout = DefaultIdleConnTimeout
+	}
+
+	if options.DisableHTTP2 {
+		// A non-nil, empty map disables the automatic HTTP/2 upgrade
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	} else {
+		transport.ForceAttemptHTTP2 = true
+	}
+
+	return transport
+}
+
+// governanceTransport holds requests back while MaxConcurrency requests are
+// already in flight.
+type governanceTransport struct {
+	next      http.RoundTripper
+	semaphore chan struct{}
+}
+
+func (transport *governanceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case transport.semaphore <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	defer func() { <-transport.semaphore }()
+
+	return transport.next.RoundTrip(req)
+}
+
+// newAssertionConfig returns the client credentials configuration exchanging
+// a client assertion signed with the private key for access tokens.
+func newAssertionConfig(options ClientOptions) (*clientcredentials.Config, error) {
 	tokenEndpoint := "/auth/oauth2/v1/token"
 
-	key, err := jwt.ParseRSAPrivateKeyFromPEM(
-		options.PrivateKeyBytes,
-	)
+	method, err := signingMethod(options.SigningAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := parsePrivateKey(options.PrivateKeyBytes)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse private key: %w", err)
 	}
 
-	// NetSuite supports multiple signing methods, but PS256 is recommended
-	// over RS256. See https://www.scottbrady91.com/jose/jwts-which-signing-algorithm-should-i-use
-	token := jwt.NewWithClaims(jwt.SigningMethodPS256, jwt.MapClaims{
+	if err := checkKeyType(method, key); err != nil {
+		return nil, err
+	}
+
+	if err := checkCertificate(options.PrivateKeyBytes, key, options.CertificateID); err != nil {
+		return nil, err
+	}
+
+	token := jwt.NewWithClaims(method, jwt.MapClaims{
 		"iss":   options.ClientID,
 		"scope": []string{"rest_webservices"},
 		"aud":   tokenEndpoint,
@@ -76,7 +321,7 @@ func NewClient(options ClientOptions) (*Client, error) {
 		return nil, fmt.Errorf("failed to get signed token: %w", err)
 	}
 
-	clientConfig := clientcredentials.Config{
+	clientConfig := &clientcredentials.Config{
 		ClientID:     options.ClientID,
 		ClientSecret: options.ClientSecret,
 		TokenURL:     tokenEndpoint,
@@ -88,53 +333,428 @@ func NewClient(options ClientOptions) (*Client, error) {
 		},
 	}
 
+	return clientConfig, nil
+}
+
+func NewClient(options ClientOptions) (*Client, error) {
+	// A token source given in the options replaces the client assertion flow
+	var clientConfig *clientcredentials.Config
+	var err error
+	if options.TokenSource == nil {
+		clientConfig, err = newAssertionConfig(options)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	maxConcurrency := options.MaxConcurrency
+	if maxConcurrency <= 0 {
+		maxConcurrency = DefaultMaxConcurrency
+	}
+
+	var baseTransport http.RoundTripper = newHTTPTransport(options, maxConcurrency)
+	if options.CassettePath != "" {
+		switch options.CassetteMode {
+		case CassetteModeRecord:
+			baseTransport, err = newRecordingTransport(baseTransport, options.CassettePath)
+		case CassetteModeReplay:
+			baseTransport, err = newReplayTransport(options.CassettePath)
+		default:
+			err = fmt.Errorf("unknown cassette mode \"%s\"", options.CassetteMode)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if options.TraceRequests {
+		baseTransport = &tracingTransport{next: baseTransport}
+	}
+
+	baseTransport = &governanceTransport{
+		next:      baseTransport,
+		semaphore: make(chan struct{}, maxConcurrency),
+	}
+
+	// The breaker wraps the semaphore, so that requests fail fast while it is
+	// open instead of queueing for a slot
+	if options.CircuitBreakerThreshold >= 0 {
+		threshold := options.CircuitBreakerThreshold
+		if threshold == 0 {
+			threshold = DefaultCircuitBreakerThreshold
+		}
+
+		cooldown := options.CircuitBreakerCooldown
+		if cooldown <= 0 {
+			cooldown = DefaultCircuitBreakerCooldown
+		}
+
+		baseTransport = &circuitBreakerTransport{
+			next:      baseTransport,
+			threshold: threshold,
+			cooldown:  cooldown,
+		}
+	}
+
+	if err := ValidateHeaders(options.Headers); err != nil {
+		return nil, err
+	}
+	baseTransport = &headerTransport{
+		next:   baseTransport,
+		header: options.Headers.Clone(),
+	}
+
+	// The budget wraps the breaker, so that rejected requests do not count
+	// as failures
+	if options.RequestBudget > 0 {
+		window := options.RequestBudgetWindow
+		if window <= 0 {
+			window = DefaultRequestBudgetWindow
+		}
+
+		baseTransport = &budgetTransport{
+			next:   baseTransport,
+			limit:  options.RequestBudget,
+			window: window,
+		}
+	}
+
+	metadataCacheSize := options.MetadataCacheSize
+	if metadataCacheSize <= 0 {
+		metadataCacheSize = DefaultMetadataCacheSize
+	}
+
+	defaultLimit := min(options.DefaultLimit, MaxLimit)
+	if defaultLimit <= 0 {
+		defaultLimit = DefaultLimit
+	}
+
+	maxFileBytes := options.MaxFileBytes
+	if maxFileBytes <= 0 {
+		maxFileBytes = DefaultMaxFileBytes
+	}
+
+	suiteQLRetries := options.SuiteQLRetries
+	if suiteQLRetries == 0 {
+		suiteQLRetries = DefaultSuiteQLRetries
+	}
+
+	baseURL, err := apiBaseURL(options)
+	if err != nil {
+		return nil, err
+	}
+
 	ctx := context.WithValue(
 		context.Background(),
 		oauth2.HTTPClient,
 		&http.Client{
 			Transport: &netsuiteAPIHTTPTransport{
-				accountID: options.AccountID,
+				baseURL: baseURL,
+				next:    baseTransport,
 			},
 		},
 	)
 
+	// Tokens from a given token source are reused until they expire
+	var tokenSource oauth2.TokenSource
+	if clientConfig != nil {
+		tokenSource = clientConfig.TokenSource(ctx)
+	} else {
+		tokenSource = oauth2.ReuseTokenSource(nil, options.TokenSource)
+	}
+
 	return &Client{
-		Client: clientConfig.Client(ctx),
+		Client:         oauth2.NewClient(ctx, tokenSource),
+		tokenSource:    tokenSource,
+		transient:      !options.DisableTransientQueries,
+		suiteQLRetries: suiteQLRetries,
+		defaultLimit:   defaultLimit,
+		maxFileBytes:   maxFileBytes,
+		subsidiary:     options.Subsidiary,
+
+		metadataCache: newMetadataCache(metadataCacheSize, options.MetadataCacheTTL),
 	}, nil
 }
 
-var metadataCache = map[string]*jsonschematree.Schema{}
+// DefaultLimit returns the number of rows returned when a tool is not given a
+// limit.
+func (c *Client) DefaultLimit() int {
+	return c.defaultLimit
+}
+
+// Token returns the access token used to authenticate requests, exchanging
+// the signed client assertion for a new one, or getting one from the token
+// source given in the options, if needed.
+func (c *Client) Token() (*oauth2.Token, error) {
+	return c.tokenSource.Token()
+}
+
+// pingQuery is the cheapest SuiteQL query, which reads no table.
+const pingQuery = "SELECT 1 AS ok FROM DUAL"
 
-// Metadata returns the schema for a given record type.
+// Ping checks that NetSuite accepts the credentials by exchanging them for an
+// access token and running a trivial SuiteQL query. Since the token is cached
+// and the connection is kept alive, it also warms the client up: the next
+// request skips the token exchange and the TCP and TLS handshakes.
+func (c *Client) Ping(ctx context.Context) error {
+	if _, err := c.Token(); err != nil {
+		return fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	if _, err := c.SuiteQLContext(ctx, pingQuery, 1, 0); err != nil {
+		return fmt.Errorf("failed to run %s: %w", pingQuery, err)
+	}
+
+	return nil
+}
+
+// Metadata returns the schema for a given record type, which is matched
+// case-insensitively against the metadata catalog. It is safe for concurrent
+// use, and schemas are cached per client.
 // https://docs.oracle.com/en/cloud/saas/netsuite/ns-o
 func (c *Client) Metadata(recordType string, includedFields []string) (*jsonschematree.Schema, error) {
-	if cachedMetadata, ok := metadataCache[recordType]; ok {
-		return cachedMetadata, nil
+	recordType = c.canonicalRecordType(recordType)
+
+	if entry, ok := c.metadataCache.get(recordType); ok {
+		return entry.schema, nil
+	}
+
+	// A schema evicted from the cache may still be held by a cached document
+	if schema, ok := c.metadataCache.document(recordType)[recordType]; ok {
+		c.metadataCache.put(recordType, schema)
+		return schema, nil
+	}
+
+	parsedBody, err := c.getMetadata(recordType)
+	if err != nil || parsedBody.Components.Schemas[recordType] == nil {
+		parsedBody, err = c.schemaForSchemaless(recordType, includedFields)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// A partial schema depends on the requested fields, so it is not cached.
+	if parsedBody.partial {
+		return parsedBody.Components.Schemas[recordType], nil
+	}
+
+	// The other schemas of the document are cached as well, since they are
+	// usually the targets of references from the record type.
+	c.metadataCache.putDocument(recordType, parsedBody.Components.Schemas)
+
+	return parsedBody.Components.Schemas[recordType], nil
+}
+
+// PreloadMetadata fetches the schemas of the record types concurrently into
+// the cache, so that later calls to Metadata for them are served without a
+// request. It returns the errors of the record types that could not be
+// fetched, by record type.
+func (c *Client) PreloadMetadata(recordTypes []string) map[string]error {
+	failures := make(map[string]error)
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for _, recordType := range recordTypes {
+		wg.Add(1)
+		go func(recordType string) {
+			defer wg.Done()
+
+			if _, err := c.Metadata(recordType, nil); err != nil {
+				mutex.Lock()
+				defer mutex.Unlock()
+				failures[recordType] = err
+			}
+		}(recordType)
 	}
+	wg.Wait()
+
+	return failures
+}
+
+// ResolvedMetadata returns the schema for a given record type with every
+// reference replaced by the schema it refers to.
+func (c *Client) ResolvedMetadata(recordType string) (*jsonschematree.Schema, error) {
+	recordType = c.canonicalRecordType(recordType)
 
-	parsedBody, _ := c.getMetadata(recordType)
-	if _, ok := parsedBody.Components.Schemas[recordType]; !ok {
-		parsedBody, _ = c.schemaForSchemaless(recordType, includedFields)
+	metadata, err := c.Metadata(recordType, nil)
+	if err != nil {
+		return nil, err
+	}
+	if metadata == nil {
+		return nil, fmt.Errorf("no metadata found for record type %s", recordType)
 	}
 
-	for recordType, schema := range parsedBody.Components.Schemas {
-		metadataCache[recordType] = schema
+	return metadata.Resolved(c.resolverFor(recordType))
+}
+
+// References returns the reference fields of a record type along with the
+// record types they point at, resolving the references within the record
+// type's document.
+func (c *Client) References(recordType string) ([]jsonschematree.Reference, error) {
+	recordType = c.canonicalRecordType(recordType)
+
+	metadata, err := c.Metadata(recordType, nil)
+	if err != nil {
+		return nil, err
+	}
+	if metadata == nil {
+		return nil, fmt.Errorf("no metadata found for record type %s", recordType)
 	}
 
-	return metadataCache[recordType], nil
+	return metadata.References(c.resolverFor(recordType))
+}
+
+// CodegenSchema returns the schema for a given record type normalized for
+// code generators, with references to other records kept as reference fields
+// and every other reference resolved.
+func (c *Client) CodegenSchema(recordType string) (*jsonschematree.CodegenType, error) {
+	recordType = c.canonicalRecordType(recordType)
+
+	metadata, err := c.Metadata(recordType, nil)
+	if err != nil {
+		return nil, err
+	}
+	if metadata == nil {
+		return nil, fmt.Errorf("no metadata found for record type %s", recordType)
+	}
+
+	return jsonschematree.Codegen(metadata, recordType, c.resolverFor(recordType))
+}
+
+// resolverFor returns a resolver for the references of a record type's
+// schema, which looks up the other schemas of its document when cached.
+func (c *Client) resolverFor(recordType string) *referenceResolver {
+	return &referenceResolver{
+		client:   c,
+		document: c.metadataCache.document(recordType),
+	}
+}
+
+// referenceResolver resolves references within a metadata catalog document.
+// NetSuite refers to the other schemas of the same document through JSON
+// Pointers, such as "#/components/schemas/customer-addressBookCollection", so
+// those are looked up in the document. Only references to other documents, or
+// references made without a document at hand, are fetched from the metadata
+// catalog.
+type referenceResolver struct {
+	client   *Client
+	document map[string]*jsonschematree.Schema
+}
+
+func (r *referenceResolver) Resolve(id string) (*jsonschematree.Schema, error) {
+	ref, err := jsonschematree.ParseRef(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// References to other documents, such as a metadata catalog URL, name
+	// the record type in their last path segment
+	name, pointer, ok := ref.ComponentSchema()
+	if !ok {
+		if ref.Document == "" {
+			return nil, fmt.Errorf("unsupported reference %s", id)
+		}
+		name = path.Base(ref.Document)
+		pointer = ref.Pointer
+	}
+
+	var schema *jsonschematree.Schema
+	if ref.Document == "" && r.document != nil {
+		schema, ok = r.document[name]
+		if !ok {
+			return nil, fmt.Errorf("schema %s not found in the catalog document", name)
+		}
+	} else {
+		schema, err = r.client.Metadata(name, nil)
+		if err != nil {
+			return nil, err
+		}
+		if schema == nil {
+			return nil, fmt.Errorf("no metadata found for %s", id)
+		}
+	}
+
+	return schema.At(pointer)
+}
+
+// RecordTypes returns the names of the record types available in the
+// metadata catalog.
+func (c *Client) RecordTypes() ([]string, error) {
+	request, err := http.NewRequest(http.MethodGet, "/record/v1/metadata-catalog", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	request.Header.Add("Accept", "application/json")
+
+	response, err := c.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to GET /record/v1/metadata-catalog: %w",
+			err,
+		)
+	}
+
+	defer response.Body.Close()
+
+	bodyBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if err := checkJSONResponse(response, bodyBytes); err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, newNetSuiteError(response, bodyBytes)
+	}
+
+	var parsedBody struct {
+		Items []struct {
+			Name string `json:"name"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(bodyBytes, &parsedBody); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	recordTypes := make([]string, 0, len(parsedBody.Items))
+	for _, item := range parsedBody.Items {
+		recordTypes = append(recordTypes, item.Name)
+	}
+
+	return recordTypes, nil
 }
 
 type metadataCatalogResponse struct {
 	Components struct {
 		Schemas map[string]*jsonschematree.Schema `json:"schemas"`
 	} `json:"components"`
+
+	// partial is set when the schema could only be built from the fields
+	// requested by the caller.
+	partial bool
 }
 
 // SuiteQL executes a SuiteQL query and returns the result of the query.
 // https://docs.oracle.com/en/cloud/saas/netsuite/ns-online-help/section_157909186990.html
 func (c *Client) SuiteQL(q string, limit int, offset int) (*SuiteQLResponse, error) {
+	return c.SuiteQLContext(context.Background(), q, limit, offset)
+}
+
+// SuiteQLContext is like SuiteQL, but the request is bound to the context.
+func (c *Client) SuiteQLContext(ctx context.Context, q string, limit int, offset int) (*SuiteQLResponse, error) {
+	return c.suiteQL(ctx, q, nil, limit, offset)
+}
+
+// suiteQL executes a SuiteQL query, binding the params to its positional
+// "?" placeholders in order.
+func (c *Client) suiteQL(ctx context.Context, q string, params []interface{}, limit int, offset int) (*SuiteQLResponse, error) {
 	requestBody := make(map[string]interface{})
 	requestBody["q"] = q
+	if len(params) > 0 {
+		requestBody["params"] = params
+	}
 
 	requestBodyJSON, err := json.Marshal(requestBody)
 	if err != nil {
@@ -152,35 +772,83 @@ func (c *Client) SuiteQL(q string, limit int, offset int) (*SuiteQLResponse, err
 		query.Add("offset", strconv.Itoa(offset))
 	}
 
+	if date := asOfDate(ctx); date != "" {
+		query.Add("asOfDate", date)
+	}
+
 	endpoint.RawQuery = query.Encode()
 
-	request, err := http.NewRequest(
-		http.MethodPost,
-		endpoint.String(),
-		bytes.NewReader(requestBodyJSON),
-	)
+	return c.postSuiteQL(ctx, endpoint.String(), requestBodyJSON)
+}
+
+// suiteQLNext fetches the page of a SuiteQL query at the "next" link of the
+// previous page. NetSuite expects the query to be posted again, and the link
+// keeps the point in time of the query only if NetSuite carried it over.
+func (c *Client) suiteQLNext(ctx context.Context, q string, next string) (*SuiteQLResponse, error) {
+	requestBodyJSON, err := json.Marshal(map[string]interface{}{"q": q})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
 	}
 
-	request.Header.Add("Prefer", "transient")
-	response, err := c.Do(request)
+	endpoint, err := url.Parse(next)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get list of records: %w", err)
+		return nil, fmt.Errorf("unable to parse URL: %w", err)
 	}
-	defer response.Body.Close()
 
-	bodyBytes, err := io.ReadAll(response.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get body bytes: %w", err)
+	if date := asOfDate(ctx); date != "" {
+		query := endpoint.Query()
+		if !query.Has("asOfDate") {
+			query.Set("asOfDate", date)
+			endpoint.RawQuery = query.Encode()
+		}
 	}
 
-	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(
-			"invalid HTTP response status %d: %s",
-			response.StatusCode,
-			string(bodyBytes),
+	return c.postSuiteQL(ctx, endpoint.String(), requestBodyJSON)
+}
+
+// postSuiteQL posts a SuiteQL request body to a SuiteQL endpoint, such as the
+// "next" link of a previous page.
+func (c *Client) postSuiteQL(ctx context.Context, endpoint string, requestBodyJSON []byte) (*SuiteQLResponse, error) {
+	// Each attempt sends a new request, so that the body is read from the
+	// start again
+	bodyBytes, err := retryTransient(ctx, c.suiteQLRetries, func() ([]byte, error) {
+		request, err := http.NewRequestWithContext(
+			ctx,
+			http.MethodPost,
+			endpoint,
+			bytes.NewReader(requestBodyJSON),
 		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		if c.transient {
+			request.Header.Add("Prefer", "transient")
+		}
+
+		response, err := c.Do(request)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get list of records: %w", err)
+		}
+		defer response.Body.Close()
+
+		bodyBytes, err := io.ReadAll(response.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get body bytes: %w", err)
+		}
+
+		if err := checkJSONResponse(response, bodyBytes); err != nil {
+			return nil, err
+		}
+
+		if response.StatusCode != http.StatusOK {
+			return nil, newNetSuiteError(response, bodyBytes)
+		}
+
+		return bodyBytes, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	var parsedBody SuiteQLResponse
@@ -197,6 +865,50 @@ type SuiteQLResponse struct {
 	TotalResults int               `json:"totalResults"`
 	HasMore      bool              `json:"hasMore"`
 	Items        []json.RawMessage `json:"items"`
+
+	// Links are the links NetSuite returned with the page, such as "self",
+	// "next", and "last", e.g. to verify paging.
+	Links []Link `json:"links,omitempty"`
+
+	// next is the endpoint of the next page from the "next" link, relative
+	// to the REST services root, if NetSuite provided one.
+	next string
+}
+
+// Link is a link of a NetSuite response, such as the "next" page.
+type Link struct {
+	Rel  string `json:"rel"`
+	Href string `json:"href"`
+}
+
+// UnmarshalJSON decodes a paginated NetSuite collection. Most endpoints wrap
+// rows under "items", but some use "data" instead, so both are accepted.
+func (r *SuiteQLResponse) UnmarshalJSON(data []byte) error {
+	type suiteQLResponse SuiteQLResponse
+	var parsedBody struct {
+		suiteQLResponse
+		Data []json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &parsedBody); err != nil {
+		return err
+	}
+
+	*r = SuiteQLResponse(parsedBody.suiteQLResponse)
+	if r.Items == nil && parsedBody.Data != nil {
+		r.Items = parsedBody.Data
+	}
+
+	for _, link := range r.Links {
+		if link.Rel != "next" {
+			continue
+		}
+
+		if endpoint, ok := restEndpoint(link.Href); ok {
+			r.next = endpoint
+		}
+	}
+
+	return nil
 }
 
 func (c *Client) getMetadata(recordType string) (*metadataCatalogResponse, error) {
@@ -227,6 +939,14 @@ func (c *Client) getMetadata(recordType string) (*metadataCatalogResponse, error
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
+	if err := checkJSONResponse(response, bodyBytes); err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, newNetSuiteError(response, bodyBytes)
+	}
+
 	var parsedBody metadataCatalogResponse
 	if err := json.Unmarshal(bodyBytes, &parsedBody); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
@@ -236,38 +956,55 @@ func (c *Client) getMetadata(recordType string) (*metadataCatalogResponse, error
 }
 
 func (c *Client) getSingleRow(recordType string) (*SuiteQLResponse, error) {
-	query := fmt.Sprintf("SELECT * FROM %s", recordType)
+	query := fmt.Sprintf("SELECT * FROM %s", QuoteIdentifier(recordType))
 	return c.SuiteQL(query, 1, 0)
 }
 
 func (c *Client) schemaForSchemaless(recordType string, includedFields []string) (*metadataCatalogResponse, error) {
-	var singleRow *SuiteQLResponse
-	var columnMap map[string]json.RawMessage
-	var columnStruct map[string]*jsonschematree.Schema
-	var schemaStruct *jsonschematree.Schema
-	var Schemas map[string]*jsonschematree.Schema
+	var inferredSchema *jsonschematree.Schema
 
-	singleRow, _ = c.getSingleRow(recordType)
-	json.Unmarshal(singleRow.Items[0], &columnMap)
+	singleRow, err := c.getSingleRow(recordType)
+	if err == nil && len(singleRow.Items) == 0 {
+		err = fmt.Errorf("no rows found for record type %s", recordType)
+	}
+	if err == nil {
+		inferredSchema, err = InferSchema(singleRow.Items)
+	}
+
+	// Without any discovered columns, the schema can still be built from
+	// the fields requested by the caller.
+	if err != nil && len(includedFields) == 0 {
+		return nil, fmt.Errorf("failed to discover columns: %w", err)
+	}
 
-	columnStruct = make(map[string]*jsonschematree.Schema)
+	columnStruct := make(map[string]*jsonschematree.Schema)
 	dummyType := []string{"string", "null"}
 	for _, includedField := range includedFields {
 		columnStruct[includedField] = jsonschematree.PrepareDummySchema(dummyType)
 	}
 
-	for columnName := range columnMap {
-		columnStruct[columnName] = jsonschematree.PrepareDummySchema(dummyType)
+	if inferredSchema != nil {
+		for columnName, columnSchema := range inferredSchema.Properties {
+			columnStruct[columnName] = columnSchema
+		}
 	}
 
 	dummyType = []string{"object"}
-	schemaStruct = jsonschematree.PrepareDummySchema(dummyType)
+	schemaStruct := jsonschematree.PrepareDummySchema(dummyType)
 	schemaStruct.Properties = columnStruct
 
-	Schemas = make(map[string]*jsonschematree.Schema)
-	Schemas[recordType] = schemaStruct
-	return &metadataCatalogResponse{Components: struct {
-		Schemas map[string]*jsonschematree.Schema `json:"schemas"`
-	}{Schemas: Schemas}}, nil
+	var parsedBody metadataCatalogResponse
+	parsedBody.Components.Schemas = map[string]*jsonschematree.Schema{
+		recordType: schemaStruct,
+	}
 
+	if err != nil {
+		schemaStruct.Description = fmt.Sprintf(
+			"Column discovery failed (%v); schema was built from the included fields only.",
+			err,
+		)
+		parsedBody.partial = true
+	}
+
+	return &parsedBody, nil
 }
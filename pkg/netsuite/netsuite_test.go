@@ -0,0 +1,228 @@
+package netsuite
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/glints-dev/mcp-netsuite/pkg/jsonschematree"
+)
+
+func TestAccountHost(t *testing.T) {
+	tests := []struct {
+		name      string
+		accountID string
+		want      string
+	}{
+		{name: "production", accountID: "1234567", want: "1234567"},
+		{name: "sandbox", accountID: "1234567_SB1", want: "1234567-sb1"},
+		{name: "mixed case sandbox", accountID: "1234567_Sb2", want: "1234567-sb2"},
+		{name: "lower case sandbox", accountID: "1234567_sb1", want: "1234567-sb1"},
+		{name: "release preview", accountID: "1234567_RP", want: "1234567-rp"},
+		{name: "already hyphenated", accountID: "1234567-SB1", want: "1234567-sb1"},
+		{name: "alphanumeric", accountID: "TSTDRV1234567", want: "tstdrv1234567"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := accountHost(tt.accountID); got != tt.want {
+				t.Errorf("accountHost(%q) = %q, want %q", tt.accountID, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNetsuiteAPIHTTPTransportRoundTrip(t *testing.T) {
+	tests := []struct {
+		name         string
+		hostOverride string
+		endpoint     string
+		want         string
+	}{
+		{
+			name:     "plain path",
+			endpoint: "/record/v1/customer/1234",
+			want:     "https://1234567-sb1.suitetalk.api.netsuite.com/services/rest/record/v1/customer/1234",
+		},
+		{
+			name:     "escaped slash in segment",
+			endpoint: "/record/v1/customer/eid:" + url.PathEscape("ACME/001"),
+			want:     "https://1234567-sb1.suitetalk.api.netsuite.com/services/rest/record/v1/customer/eid:ACME%2F001",
+		},
+		{
+			name:     "escaped space and percent in segment",
+			endpoint: "/record/v1/customer/eid:" + url.PathEscape("ACME 100%"),
+			want:     "https://1234567-sb1.suitetalk.api.netsuite.com/services/rest/record/v1/customer/eid:ACME%20100%25",
+		},
+		{
+			name:     "escaped query string",
+			endpoint: "/record/v1/customer?" + url.Values{"q": {`email START_WITH "a&b"`}, "limit": {"10"}}.Encode(),
+			want:     "https://1234567-sb1.suitetalk.api.netsuite.com/services/rest/record/v1/customer?limit=10&q=email+START_WITH+%22a%26b%22",
+		},
+		{
+			name:     "query string kept verbatim",
+			endpoint: "/query/v1/suiteql?limit=5&offset=10",
+			want:     "https://1234567-sb1.suitetalk.api.netsuite.com/services/rest/query/v1/suiteql?limit=5&offset=10",
+		},
+		{
+			name:     "fragment dropped",
+			endpoint: "/record/v1/metadata-catalog/customer#/properties/entity",
+			want:     "https://1234567-sb1.suitetalk.api.netsuite.com/services/rest/record/v1/metadata-catalog/customer",
+		},
+		{
+			name:         "override with scheme and path",
+			hostOverride: "http://localhost:8080/mock/",
+			endpoint:     "/record/v1/customer/eid:" + url.PathEscape("a/b") + "?expandSubResources=true",
+			want:         "http://localhost:8080/mock/services/rest/record/v1/customer/eid:a%2Fb?expandSubResources=true",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseURL, err := apiBaseURL(ClientOptions{AccountID: "1234567_SB1", APIHostOverride: tt.hostOverride})
+			if err != nil {
+				t.Fatalf("apiBaseURL() error = %v", err)
+			}
+
+			var got string
+			transport := &netsuiteAPIHTTPTransport{
+				baseURL: baseURL,
+				next: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+					got = req.URL.String()
+					return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+				}),
+			}
+
+			req, err := http.NewRequest(http.MethodGet, tt.endpoint, nil)
+			if err != nil {
+				t.Fatalf("http.NewRequest() error = %v", err)
+			}
+			original := req.URL.String()
+
+			if _, err := transport.RoundTrip(req); err != nil {
+				t.Fatalf("RoundTrip() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RoundTrip() sent %s, want %s", got, tt.want)
+			}
+			if req.URL.String() != original {
+				t.Errorf("RoundTrip() modified the request URL to %s, want %s", req.URL, original)
+			}
+		})
+	}
+}
+
+// failingTransport fails the test on any request, for code that must not
+// reach NetSuite.
+type failingTransport struct {
+	t *testing.T
+}
+
+func (transport failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport.t.Errorf("unexpected request to %s", req.URL)
+	return nil, errors.New("unexpected request")
+}
+
+func TestReferenceResolverResolveWithinDocument(t *testing.T) {
+	var document map[string]*jsonschematree.Schema
+	if err := json.Unmarshal([]byte(`{
+		"customer": {
+			"type": "object",
+			"properties": {
+				"subsidiary": {"$ref": "#/components/schemas/subsidiary"},
+				"addressBook": {"type": "array", "items": {"$ref": "#/components/schemas/customer-addressBookCollection"}}
+			}
+		},
+		"subsidiary": {
+			"type": "object",
+			"properties": {
+				"id": {"type": "string"},
+				"refName": {"type": "string"}
+			}
+		}
+	}`), &document); err != nil {
+		t.Fatalf("failed to unmarshal document: %v", err)
+	}
+
+	resolver := &referenceResolver{
+		client:   &Client{Client: &http.Client{Transport: failingTransport{t}}},
+		document: document,
+	}
+
+	tests := []struct {
+		name    string
+		ref     string
+		want    *jsonschematree.Schema
+		wantErr bool
+	}{
+		{name: "component schema", ref: "#/components/schemas/subsidiary", want: document["subsidiary"]},
+		{name: "pointer into component schema", ref: "#/components/schemas/customer/properties/subsidiary", want: document["customer"].Properties["subsidiary"]},
+		{name: "missing component schema", ref: "#/components/schemas/customer-addressBookCollection", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolver.Resolve(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve(%q) error = %v, wantErr %v", tt.ref, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Resolve(%q) = %p, want %p", tt.ref, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuiteQLResponseUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantItems []string
+		wantNext  string
+	}{
+		{
+			name:      "items",
+			body:      `{"count": 1, "hasMore": false, "items": [{"id": "1"}]}`,
+			wantItems: []string{`{"id": "1"}`},
+		},
+		{
+			name:      "data",
+			body:      `{"count": 2, "hasMore": false, "data": [{"id": "1"}, {"id": "2"}]}`,
+			wantItems: []string{`{"id": "1"}`, `{"id": "2"}`},
+		},
+		{
+			name:      "empty items win over data",
+			body:      `{"count": 0, "hasMore": false, "items": [], "data": [{"id": "1"}]}`,
+			wantItems: []string{},
+		},
+		{
+			name:      "next link",
+			body:      `{"count": 1, "hasMore": true, "items": [{"id": "1"}], "links": [{"rel": "next", "href": "https://123456.suitetalk.api.netsuite.com/services/rest/query/v1/suiteql?limit=1&offset=1"}]}`,
+			wantItems: []string{`{"id": "1"}`},
+			wantNext:  "/query/v1/suiteql?limit=1&offset=1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got SuiteQLResponse
+			if err := json.Unmarshal([]byte(tt.body), &got); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+
+			if got.Items == nil || len(got.Items) != len(tt.wantItems) {
+				t.Fatalf("Items = %v, want %v", got.Items, tt.wantItems)
+			}
+			for i, item := range got.Items {
+				if string(item) != tt.wantItems[i] {
+					t.Errorf("Items[%d] = %s, want %s", i, item, tt.wantItems[i])
+				}
+			}
+			if got.next != tt.wantNext {
+				t.Errorf("next = %q, want %q", got.next, tt.wantNext)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package netsuite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// maxFieldOptions is the number of options ListOptions returns at most. Select
+// fields pointing at records such as customers are not meant to be listed in
+// full.
+const maxFieldOptions = 1000
+
+// FieldOption is a value a select field can hold.
+type FieldOption struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+}
+
+// ListOptions returns the values a select field of a record type can hold,
+// ordered by internal ID. The field is looked up in the metadata catalog, and
+// the options are read with SuiteQL from the list or record type it draws
+// from, which must have a name column. Lists rarely change, so the options are
+// cached per field for the lifetime of the client.
+func (c *Client) ListOptions(ctx context.Context, recordType string, field string) ([]FieldOption, error) {
+	key := strings.ToLower(recordType) + "." + strings.ToLower(field)
+
+	c.fieldOptionsMutex.Lock()
+	options, ok := c.fieldOptions[key]
+	c.fieldOptionsMutex.Unlock()
+	if ok {
+		return options, nil
+	}
+
+	source, err := c.optionSource(recordType, field)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT id, name AS label FROM %s ORDER BY id", QuoteIdentifier(source))
+	results, err := c.SuiteQLContext(ctx, query, maxFieldOptions, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the options of %s: %w", source, err)
+	}
+	if results.HasMore {
+		return nil, fmt.Errorf("field %s draws from %s, which has more than %d options; query it with SuiteQL instead", field, source, maxFieldOptions)
+	}
+
+	var rows []struct {
+		ID    interface{} `json:"id"`
+		Label string      `json:"label"`
+	}
+	if err := results.Scan(&rows); err != nil {
+		return nil, fmt.Errorf("failed to read the options of %s: %w", source, err)
+	}
+
+	options = make([]FieldOption, 0, len(rows))
+	for _, row := range rows {
+		options = append(options, FieldOption{
+			ID:    fmt.Sprint(row.ID),
+			Label: row.Label,
+		})
+	}
+
+	c.fieldOptionsMutex.Lock()
+	if c.fieldOptions == nil {
+		c.fieldOptions = make(map[string][]FieldOption)
+	}
+	c.fieldOptions[key] = options
+	c.fieldOptionsMutex.Unlock()
+
+	return options, nil
+}
+
+// optionSource returns the list or record type a select field of a record
+// type draws its options from.
+func (c *Client) optionSource(recordType string, field string) (string, error) {
+	metadata, err := c.Metadata(recordType, nil)
+	if err != nil {
+		return "", err
+	}
+	if metadata == nil {
+		return "", fmt.Errorf("no metadata found for record type %s", recordType)
+	}
+
+	for property, schema := range metadata.Properties {
+		if !strings.EqualFold(property, field) {
+			continue
+		}
+
+		if !schema.IsReference() {
+			return "", fmt.Errorf("field %s of record type %s is not a select field", field, recordType)
+		}
+
+		source := referenceTarget(schema)
+		if source == "" {
+			return "", fmt.Errorf("the metadata does not say which list field %s of record type %s draws from", field, recordType)
+		}
+
+		return source, nil
+	}
+
+	return "", fmt.Errorf("field %s not found on record type %s", field, recordType)
+}
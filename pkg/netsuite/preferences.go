@@ -0,0 +1,159 @@
+package netsuite
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Preferences holds the company preferences that affect how NetSuite formats
+// values. Each preference is looked up separately, and the ones that could not
+// be determined are explained in Warnings.
+type Preferences struct {
+	BaseCurrency *Currency `json:"baseCurrency,omitempty"`
+
+	// DateFormat is the pattern NetSuite uses to format dates in query
+	// results (e.g. "MM/DD/YYYY"), derived from DateExample.
+	DateFormat  string `json:"dateFormat,omitempty"`
+	DateExample string `json:"dateExample,omitempty"`
+
+	TimeZone string `json:"timeZone,omitempty"`
+
+	Warnings []string `json:"warnings"`
+}
+
+// Currency identifies a currency of the account.
+type Currency struct {
+	Symbol string `json:"symbol"`
+	Name   string `json:"name"`
+}
+
+// Preferences returns the company preferences. They rarely change, so the
+// first complete result is cached for the lifetime of the client.
+func (c *Client) Preferences(ctx context.Context) (*Preferences, error) {
+	c.preferencesMutex.Lock()
+	defer c.preferencesMutex.Unlock()
+
+	if c.preferences != nil {
+		return c.preferences, nil
+	}
+
+	preferences := &Preferences{Warnings: []string{}}
+
+	var currencies []Currency
+	if err := c.queryInto(ctx, "SELECT symbol, name FROM currency WHERE isbasecurrency = 'T'", &currencies); err != nil {
+		return nil, fmt.Errorf("failed to get base currency: %w", err)
+	}
+	if len(currencies) > 0 {
+		preferences.BaseCurrency = &currencies[0]
+	} else {
+		preferences.Warnings = append(preferences.Warnings, "No base currency found")
+	}
+
+	var dates []struct {
+		Formatted string `json:"formatted"`
+		ISO       string `json:"iso"`
+	}
+	if err := c.queryInto(ctx, "SELECT SYSDATE AS formatted, TO_CHAR(SYSDATE, 'YYYY-MM-DD') AS iso FROM DUAL", &dates); err != nil {
+		preferences.Warnings = append(preferences.Warnings, fmt.Sprintf("Unable to determine the date format: %v", err))
+	} else if len(dates) > 0 {
+		preferences.DateExample = dates[0].Formatted
+		preferences.DateFormat = deriveDateFormat(dates[0].Formatted, dates[0].ISO)
+		if preferences.DateFormat == "" {
+			preferences.Warnings = append(preferences.Warnings, "The date format is ambiguous; see the date example")
+		}
+	}
+
+	var timeZones []struct {
+		TimeZone string `json:"timezone"`
+	}
+	if err := c.queryInto(ctx, "SELECT SESSIONTIMEZONE AS timezone FROM DUAL", &timeZones); err != nil {
+		preferences.Warnings = append(preferences.Warnings, fmt.Sprintf("Unable to determine the time zone: %v", err))
+	} else if len(timeZones) > 0 {
+		preferences.TimeZone = timeZones[0].TimeZone
+	}
+
+	// Numbers are returned unformatted by both SuiteQL and REST, regardless
+	// of the number format preference.
+	preferences.Warnings = append(preferences.Warnings, "Numbers are returned unformatted, with '.' as the decimal separator")
+
+	if len(preferences.Warnings) == 1 {
+		c.preferences = preferences
+	}
+
+	return preferences, nil
+}
+
+// queryInto runs a SuiteQL query and unmarshals its rows into dest, which
+// must be a pointer to a slice.
+func (c *Client) queryInto(ctx context.Context, query string, dest interface{}) error {
+	results, err := c.SuiteQLContext(ctx, query, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	return results.Scan(dest)
+}
+
+// deriveDateFormat turns a date formatted by NetSuite into its pattern by
+// matching its parts against the same date in ISO format. It returns an empty
+// string when the day and month cannot be told apart.
+func deriveDateFormat(formatted string, iso string) string {
+	date, err := time.Parse(time.DateOnly, iso)
+	if err != nil {
+		return ""
+	}
+
+	year, month, day := date.Year(), int(date.Month()), date.Day()
+	if month == day {
+		return ""
+	}
+
+	var pattern strings.Builder
+	for _, token := range splitDateTokens(formatted) {
+		value, err := strconv.Atoi(token)
+		switch {
+		case err != nil && strings.EqualFold(token, date.Month().String()):
+			pattern.WriteString("Month")
+		case err != nil && strings.EqualFold(token, date.Month().String()[:3]):
+			pattern.WriteString("Mon")
+		case err != nil:
+			pattern.WriteString(token)
+		case value == year:
+			pattern.WriteString("YYYY")
+		case len(token) == 2 && value == year%100 && value != month && value != day:
+			pattern.WriteString("YY")
+		case value == month:
+			pattern.WriteString(strings.Repeat("M", len(token)))
+		case value == day:
+			pattern.WriteString(strings.Repeat("D", len(token)))
+		default:
+			return ""
+		}
+	}
+
+	return pattern.String()
+}
+
+// splitDateTokens splits a formatted date into runs of digits, runs of
+// letters, and individual separators.
+func splitDateTokens(formatted string) []string {
+	var tokens []string
+	runes := []rune(formatted)
+	for start := 0; start < len(runes); {
+		end := start + 1
+		for end < len(runes) &&
+			((unicode.IsDigit(runes[start]) && unicode.IsDigit(runes[end])) ||
+				(unicode.IsLetter(runes[start]) && unicode.IsLetter(runes[end]))) {
+			end++
+		}
+
+		tokens = append(tokens, string(runes[start:end]))
+		start = end
+	}
+
+	return tokens
+}
@@ -0,0 +1,66 @@
+package netsuite
+
+import "strings"
+
+// reservedWords are the words SuiteQL, like Oracle SQL, does not accept as
+// bare identifiers.
+var reservedWords = map[string]struct{}{
+	"ACCESS": {}, "ADD": {}, "ALL": {}, "ALTER": {}, "AND": {}, "ANY": {},
+	"AS": {}, "ASC": {}, "AUDIT": {}, "BETWEEN": {}, "BY": {}, "CHAR": {},
+	"CHECK": {}, "CLUSTER": {}, "COLUMN": {}, "COMMENT": {}, "COMPRESS": {},
+	"CONNECT": {}, "CREATE": {}, "CURRENT": {}, "DATE": {}, "DECIMAL": {},
+	"DEFAULT": {}, "DELETE": {}, "DESC": {}, "DISTINCT": {}, "DROP": {},
+	"ELSE": {}, "EXCLUSIVE": {}, "EXISTS": {}, "FILE": {}, "FLOAT": {},
+	"FOR": {}, "FROM": {}, "GRANT": {}, "GROUP": {}, "HAVING": {},
+	"IDENTIFIED": {}, "IMMEDIATE": {}, "IN": {}, "INCREMENT": {}, "INDEX": {},
+	"INITIAL": {}, "INSERT": {}, "INTEGER": {}, "INTERSECT": {}, "INTO": {},
+	"IS": {}, "LEVEL": {}, "LIKE": {}, "LOCK": {}, "LONG": {},
+	"MAXEXTENTS": {}, "MINUS": {}, "MODE": {}, "MODIFY": {}, "NOAUDIT": {},
+	"NOCOMPRESS": {}, "NOT": {}, "NOWAIT": {}, "NULL": {}, "NUMBER": {},
+	"OF": {}, "OFFLINE": {}, "ON": {}, "ONLINE": {}, "OPTION": {}, "OR": {},
+	"ORDER": {}, "PCTFREE": {}, "PRIOR": {}, "PUBLIC": {}, "RAW": {},
+	"RENAME": {}, "RESOURCE": {}, "REVOKE": {}, "ROW": {}, "ROWID": {},
+	"ROWNUM": {}, "ROWS": {}, "SELECT": {}, "SESSION": {}, "SET": {},
+	"SHARE": {}, "SIZE": {}, "SMALLINT": {}, "START": {}, "SUCCESSFUL": {},
+	"SYNONYM": {}, "SYSDATE": {}, "TABLE": {}, "THEN": {}, "TO": {},
+	"TRIGGER": {}, "UID": {}, "UNION": {}, "UNIQUE": {}, "UPDATE": {},
+	"USER": {}, "VALIDATE": {}, "VALUES": {}, "VARCHAR": {}, "VARCHAR2": {},
+	"VIEW": {}, "WHENEVER": {}, "WHERE": {}, "WITH": {},
+}
+
+// QuoteIdentifier returns a table or column name as it can be written in a
+// SuiteQL query. Qualified names such as "t.trandate" are quoted part by part,
+// so names cannot contain dots themselves. Plain names such as "trandate" are
+// returned as is, since quoting makes them case-sensitive. Reserved words are
+// upper-cased and enclosed in double quotes, matching how unquoted names are
+// stored, and names with other characters are enclosed in double quotes with
+// embedded double quotes doubled.
+func QuoteIdentifier(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = quoteIdentifierPart(part)
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// quoteIdentifierPart quotes a single part of a qualified name.
+func quoteIdentifierPart(part string) string {
+	if identifierPattern.MatchString(part) {
+		upper := strings.ToUpper(part)
+		if _, reserved := reservedWords[upper]; !reserved {
+			return part
+		}
+
+		return `"` + upper + `"`
+	}
+
+	return `"` + strings.ReplaceAll(part, `"`, `""`) + `"`
+}
+
+// QuoteLiteral returns a string as a SuiteQL string literal, enclosed in
+// single quotes with embedded single quotes doubled. Prefer binding values as
+// parameters, e.g. with SuiteQLNamed, where the query allows it.
+func QuoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,56 @@
+package netsuite
+
+import "testing"
+
+func TestQuoteIdentifier(t *testing.T) {
+	tests := []struct {
+		name       string
+		identifier string
+		want       string
+	}{
+		{name: "plain name", identifier: "trandate", want: "trandate"},
+		{name: "mixed case name", identifier: "tranDate", want: "tranDate"},
+		{name: "custom field", identifier: "custbody_approval_2", want: "custbody_approval_2"},
+		{name: "reserved word", identifier: "date", want: `"DATE"`},
+		{name: "upper case reserved word", identifier: "NUMBER", want: `"NUMBER"`},
+		{name: "mixed case reserved word", identifier: "Comment", want: `"COMMENT"`},
+		{name: "space", identifier: "line total", want: `"line total"`},
+		{name: "leading digit", identifier: "1st", want: `"1st"`},
+		{name: "embedded double quote", identifier: `a"b`, want: `"a""b"`},
+		{name: "only double quote", identifier: `"`, want: `""""`},
+		{name: "qualified name", identifier: "t.trandate", want: "t.trandate"},
+		{name: "qualified reserved word", identifier: "t.date", want: `t."DATE"`},
+		{name: "qualified reserved table alias", identifier: "user.id", want: `"USER".id`},
+		{name: "qualified name with embedded double quote", identifier: `t.a"b`, want: `t."a""b"`},
+		{name: "three parts", identifier: "s.t.number", want: `s.t."NUMBER"`},
+		{name: "empty part", identifier: "t.", want: `t.""`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := QuoteIdentifier(tt.identifier); got != tt.want {
+				t.Errorf("QuoteIdentifier(%q) = %s, want %s", tt.identifier, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuoteLiteral(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "plain value", value: "Acme", want: "'Acme'"},
+		{name: "empty value", value: "", want: "''"},
+		{name: "embedded single quote", value: "O'Brien", want: "'O''Brien'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := QuoteLiteral(tt.value); got != tt.want {
+				t.Errorf("QuoteLiteral(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,599 @@
+package netsuite
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// QueryRecords returns a page of references to the records of a record type
+// matching the filter, which is written in the REST record query language
+// (e.g. `email START_WITH "barbara"`). An empty filter matches every record.
+func (c *Client) QueryRecords(ctx context.Context, recordType string, filter string, limit int, offset int) (*SuiteQLResponse, error) {
+	recordType = c.canonicalRecordType(recordType)
+	endpoint, _ := url.Parse(fmt.Sprintf("/record/v1/%s", url.PathEscape(recordType)))
+	query := endpoint.Query()
+
+	if filter != "" {
+		query.Add("q", filter)
+	}
+
+	if limit != 0 {
+		query.Add("limit", strconv.Itoa(limit))
+	}
+
+	if offset != 0 {
+		query.Add("offset", strconv.Itoa(offset))
+	}
+
+	endpoint.RawQuery = query.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	response, err := c.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get list of records: %w", err)
+	}
+	defer response.Body.Close()
+
+	bodyBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get body bytes: %w", err)
+	}
+
+	if err := checkJSONResponse(response, bodyBytes); err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, newNetSuiteError(response, bodyBytes)
+	}
+
+	var parsedBody SuiteQLResponse
+	if err := json.Unmarshal(bodyBytes, &parsedBody); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	return &parsedBody, nil
+}
+
+// ErrUnknownRecordType is returned when a record type is not listed in the
+// metadata catalog.
+var ErrUnknownRecordType = errors.New("unknown record type")
+
+// ListRecords is like QueryRecords, but checks the record type against the
+// metadata catalog first so that a typo gets a clear error.
+func (c *Client) ListRecords(ctx context.Context, recordType string, filter string, limit int, offset int) (*SuiteQLResponse, error) {
+	recordType, err := c.checkRecordType(recordType)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.QueryRecords(ctx, recordType, filter, limit, offset)
+}
+
+// checkRecordType returns ErrUnknownRecordType if the record type is not in
+// the metadata catalog, suggesting the closest record types for typos.
+// Otherwise it returns the record type as the catalog spells it, which may
+// differ in case from the given one.
+func (c *Client) checkRecordType(recordType string) (string, error) {
+	recordTypes, err := c.catalogRecordTypes()
+	if err != nil {
+		return "", err
+	}
+
+	if _, ok := recordTypes[recordType]; ok {
+		return recordType, nil
+	}
+
+	names := make([]string, 0, len(recordTypes))
+	for name := range recordTypes {
+		if strings.EqualFold(name, recordType) {
+			return name, nil
+		}
+		names = append(names, name)
+	}
+
+	if suggestions := closestNames(strings.ToLower(recordType), names, maxTableSuggestions); len(suggestions) > 0 {
+		return "", fmt.Errorf("%w: %s (did you mean %s?)", ErrUnknownRecordType, recordType, strings.Join(suggestions, ", "))
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrUnknownRecordType, recordType)
+}
+
+// canonicalRecordType returns the record type as the metadata catalog spells
+// it, since NetSuite does not find record types given in the wrong case.
+// Standard record types are spelled in lower case, so those without upper
+// case letters are returned as is without consulting the catalog. Custom
+// record types keep the case of their script ID, so they are always looked
+// up. Record types the catalog does not list, such as SuiteQL-only tables,
+// are returned as is, as is any record type if the catalog cannot be fetched.
+func (c *Client) canonicalRecordType(recordType string) string {
+	lower := strings.ToLower(recordType)
+	if recordType == lower && !strings.HasPrefix(lower, "customrecord") {
+		return recordType
+	}
+
+	canonical, err := c.checkRecordType(recordType)
+	if err != nil {
+		return recordType
+	}
+
+	return canonical
+}
+
+// catalogRecordTypes returns the record types of the metadata catalog, which
+// is fetched once per client. The returned map must not be modified.
+func (c *Client) catalogRecordTypes() (map[string]struct{}, error) {
+	c.recordTypesMutex.Lock()
+	defer c.recordTypesMutex.Unlock()
+
+	if c.recordTypes == nil {
+		recordTypes, err := c.RecordTypes()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get record types: %w", err)
+		}
+
+		c.recordTypes = make(map[string]struct{}, len(recordTypes))
+		for _, name := range recordTypes {
+			c.recordTypes[name] = struct{}{}
+		}
+	}
+
+	return c.recordTypes, nil
+}
+
+// HasInactiveColumn reports whether the record type has an isinactive field,
+// according to its catalog or inferred schema. Records whose isinactive is
+// true are archived, and usually not wanted.
+func (c *Client) HasInactiveColumn(recordType string) (bool, error) {
+	metadata, err := c.Metadata(recordType, nil)
+	if err != nil {
+		return false, err
+	}
+	if metadata == nil {
+		return false, nil
+	}
+
+	for property := range metadata.Properties {
+		if strings.EqualFold(property, "isinactive") {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// restQueryOrPattern matches the string values of the REST record query
+// language, which are enclosed in double quotes, and the OR operator.
+var restQueryOrPattern = regexp.MustCompile(`(?i)"(?:[^"\\]|\\.)*"|\bOR\b`)
+
+// hasOr reports whether a REST record query filter combines conditions with
+// OR outside of its string values.
+func hasOr(filter string) bool {
+	for _, match := range restQueryOrPattern.FindAllString(filter, -1) {
+		if strings.EqualFold(match, "OR") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ExcludeInactive narrows a REST record query filter to the active records of
+// the record type. If the record type has no isinactive field, the filter is
+// returned unchanged and the returned bool is false. Filters using OR are
+// rejected, since the query language cannot group them to apply the condition
+// to every alternative.
+func (c *Client) ExcludeInactive(recordType string, filter string) (string, bool, error) {
+	hasInactive, err := c.HasInactiveColumn(recordType)
+	if err != nil || !hasInactive {
+		return filter, false, err
+	}
+
+	if filter == "" {
+		return "isInactive IS false", true, nil
+	}
+
+	if hasOr(filter) {
+		return "", false, errors.New("filters using OR cannot be narrowed to active records; add isInactive IS false to each alternative instead")
+	}
+
+	return filter + " AND isInactive IS false", true, nil
+}
+
+// ExcludeInactiveQuery narrows a SuiteQL query to the active rows of the
+// table it selects from, by filtering its rows on isinactive, which the query
+// must select. If the table has no isinactive field, the query is returned
+// unchanged and the returned bool is false.
+func (c *Client) ExcludeInactiveQuery(query string) (string, bool, error) {
+	table := SourceTable(query)
+	if table == "" {
+		return "", false, errors.New("cannot determine the table the query selects from")
+	}
+
+	hasInactive, err := c.HasInactiveColumn(table)
+	if err != nil || !hasInactive {
+		return query, false, err
+	}
+
+	return fmt.Sprintf("SELECT * FROM (%s) WHERE isinactive = 'F'", query), true, nil
+}
+
+// CountRecords returns the number of records of a record type matching the
+// filter, without retrieving the records themselves.
+func (c *Client) CountRecords(ctx context.Context, recordType string, filter string) (int, error) {
+	// NetSuite requires a limit of at least 1, but the total is reported
+	// regardless of the page size.
+	results, err := c.QueryRecords(ctx, recordType, filter, 1, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	return results.TotalResults, nil
+}
+
+// MaxExpandDepth is the deepest level of sub-resources GetRecord expands.
+const MaxExpandDepth = 3
+
+// GetRecord returns a single record. Sub-resources such as sublists and
+// subrecords are expanded up to expandDepth levels: 0 returns only links to
+// them, 1 expands them with NetSuite's expandSubResources parameter, and every
+// further level fetches the sub-resources that are still collapsed, again with
+// expandSubResources. The depth is capped at MaxExpandDepth.
+func (c *Client) GetRecord(ctx context.Context, recordType string, id string, expandDepth int) (map[string]interface{}, error) {
+	recordType = c.canonicalRecordType(recordType)
+	endpoint := fmt.Sprintf(
+		"/record/v1/%s/%s",
+		url.PathEscape(recordType),
+		url.PathEscape(id),
+	)
+
+	expandDepth = max(0, min(expandDepth, MaxExpandDepth))
+
+	return c.getResource(ctx, endpoint, expandDepth)
+}
+
+// GetSubList returns a page of the lines of a sublist of a record, such as
+// the "item" sublist of a sales order, without fetching the record itself.
+// The lines are expanded, so that their fields are returned rather than links
+// to them.
+func (c *Client) GetSubList(ctx context.Context, recordType string, id string, sublistName string, limit int, offset int) (*SuiteQLResponse, error) {
+	recordType = c.canonicalRecordType(recordType)
+	endpoint, _ := url.Parse(fmt.Sprintf(
+		"/record/v1/%s/%s/%s",
+		url.PathEscape(recordType),
+		url.PathEscape(id),
+		url.PathEscape(sublistName),
+	))
+	query := endpoint.Query()
+	query.Set("expandSubResources", "true")
+
+	if limit != 0 {
+		query.Add("limit", strconv.Itoa(limit))
+	}
+
+	if offset != 0 {
+		query.Add("offset", strconv.Itoa(offset))
+	}
+
+	endpoint.RawQuery = query.Encode()
+
+	var sublist SuiteQLResponse
+	if err := c.getJSON(ctx, endpoint.String(), &sublist); err != nil {
+		return nil, err
+	}
+
+	return &sublist, nil
+}
+
+// FlattenSubList returns a page of the lines of a sublist of a record like
+// GetSubList, with the chosen fields of the record copied onto every line
+// under a "parent_" prefix, so that each line can be read on its own. The
+// internal ID of the record is always included. Fields the record does not
+// have are null, and the links of reference fields are dropped.
+func (c *Client) FlattenSubList(ctx context.Context, recordType string, id string, sublistName string, parentFields []string, limit int, offset int) (*SuiteQLResponse, error) {
+	record, err := c.GetRecord(ctx, recordType, id, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s record %s: %w", recordType, id, err)
+	}
+
+	sublist, err := c.GetSubList(ctx, recordType, id, sublistName, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sublist %s: %w", sublistName, err)
+	}
+
+	parent := map[string]interface{}{"parent_id": id}
+	for _, field := range parentFields {
+		value := record[field]
+		if reference, ok := value.(map[string]interface{}); ok {
+			delete(reference, "links")
+		}
+		parent["parent_"+field] = value
+	}
+
+	for i, item := range sublist.Items {
+		var line map[string]interface{}
+		if err := json.Unmarshal(item, &line); err != nil {
+			return nil, fmt.Errorf("failed to decode sublist line: %w", err)
+		}
+
+		delete(line, "links")
+		for key, value := range parent {
+			line[key] = value
+		}
+
+		flattened, err := json.Marshal(line)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode sublist line: %w", err)
+		}
+		sublist.Items[i] = flattened
+	}
+
+	return sublist, nil
+}
+
+func (c *Client) getResource(ctx context.Context, endpoint string, expandDepth int) (map[string]interface{}, error) {
+	resourceURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse URL: %w", err)
+	}
+
+	if expandDepth > 0 {
+		query := resourceURL.Query()
+		query.Set("expandSubResources", "true")
+		resourceURL.RawQuery = query.Encode()
+	}
+
+	var resource map[string]interface{}
+	if err := c.getJSON(ctx, resourceURL.String(), &resource); err != nil {
+		return nil, err
+	}
+
+	if expandDepth > 1 {
+		if err := c.expandCollapsed(ctx, resource, expandDepth-1); err != nil {
+			return nil, err
+		}
+	}
+
+	return resource, nil
+}
+
+// expandCollapsed replaces every sub-resource that NetSuite returned as links
+// only with the resource itself.
+func (c *Client) expandCollapsed(ctx context.Context, value interface{}, expandDepth int) error {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if endpoint, ok := collapsedResourceEndpoint(child); ok {
+				resource, err := c.getResource(ctx, endpoint, expandDepth)
+				if err != nil {
+					return err
+				}
+
+				value[key] = resource
+				continue
+			}
+
+			if err := c.expandCollapsed(ctx, child, expandDepth); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, child := range value {
+			if err := c.expandCollapsed(ctx, child, expandDepth); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// collapsedResourceEndpoint returns the endpoint of a sub-resource that only
+// consists of links, relative to the REST services root. References to other
+// records also carry links, but have an id as well and are not expanded.
+func collapsedResourceEndpoint(value interface{}) (string, bool) {
+	object, ok := value.(map[string]interface{})
+	if !ok || len(object) != 1 {
+		return "", false
+	}
+
+	links, ok := object["links"].([]interface{})
+	if !ok {
+		return "", false
+	}
+
+	for _, link := range links {
+		link, ok := link.(map[string]interface{})
+		if !ok || link["rel"] != "self" {
+			continue
+		}
+
+		href, ok := link["href"].(string)
+		if !ok {
+			continue
+		}
+
+		return restEndpoint(href)
+	}
+
+	return "", false
+}
+
+// restEndpoint returns the endpoint of an absolute NetSuite link relative to
+// the REST services root, keeping its query string.
+func restEndpoint(href string) (string, bool) {
+	hrefURL, err := url.Parse(href)
+	if err != nil {
+		return "", false
+	}
+
+	_, path, found := strings.Cut(hrefURL.Path, "/services/rest")
+	if !found {
+		return "", false
+	}
+
+	hrefURL.Scheme = ""
+	hrefURL.Host = ""
+	hrefURL.Path = path
+
+	return hrefURL.String(), true
+}
+
+// UpsertResult describes the outcome of UpsertRecord.
+type UpsertResult struct {
+	// Created is true if the record did not exist and was created, and false
+	// if an existing record was replaced.
+	Created bool `json:"created"`
+
+	// ID is the internal ID of the record, taken from the Location header.
+	// It is empty if NetSuite did not return one.
+	ID string `json:"id,omitempty"`
+
+	// JobID is the asynchronous job writing the record, with
+	// WithRespondAsync. Created and ID are then unknown until the job
+	// completed.
+	JobID string `json:"jobId,omitempty"`
+}
+
+// UpsertRecord creates the record with the external ID, or replaces it if it
+// already exists. The subsidiary of the context is set on records that do not
+// set one. NetSuite reports field validation failures through
+// NetSuiteError.FieldErrors.
+func (c *Client) UpsertRecord(ctx context.Context, recordType string, externalID string, body map[string]interface{}) (*UpsertResult, error) {
+	recordType = c.canonicalRecordType(recordType)
+
+	body, err := c.applySubsidiary(ctx, recordType, body)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf(
+		"/record/v1/%s/eid:%s",
+		url.PathEscape(recordType),
+		url.PathEscape(externalID),
+	)
+
+	written, err := c.sendRecord(ctx, http.MethodPut, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UpsertResult{
+		Created: written.statusCode == http.StatusCreated,
+		ID:      written.id,
+		JobID:   written.jobID,
+	}, nil
+}
+
+// writtenRecord is the response to a record payload sent by sendRecord.
+type writtenRecord struct {
+	statusCode int
+
+	// id is the internal ID of the written record, taken from the Location
+	// header. It is empty if NetSuite did not return one.
+	id string
+
+	// jobID is the asynchronous job NetSuite accepted the write as, with
+	// WithRespondAsync.
+	jobID string
+}
+
+// sendRecord sends a record payload to the endpoint and returns the response
+// status along with the internal ID of the written record, or with
+// WithRespondAsync, the ID of the job NetSuite accepted the write as.
+func (c *Client) sendRecord(ctx context.Context, method string, endpoint string, body map[string]interface{}) (*writtenRecord, error) {
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal record: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(bodyJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if respondAsync(ctx) {
+		request.Header.Set("Prefer", "respond-async")
+	}
+
+	response, err := c.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s %s: %w", method, request.URL.Path, err)
+	}
+	defer response.Body.Close()
+
+	bodyBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	written := &writtenRecord{statusCode: response.StatusCode}
+	location := response.Header.Get("Location")
+
+	switch response.StatusCode {
+	case http.StatusCreated, http.StatusOK, http.StatusNoContent:
+		if location != "" {
+			written.id = path.Base(location)
+		}
+	case http.StatusAccepted:
+		// The Location header points at the job processing the request
+		if location == "" {
+			return nil, fmt.Errorf("NetSuite accepted the %s %s as a job, but returned no Location header to poll the job with", method, request.URL.Path)
+		}
+		written.jobID = path.Base(location)
+	default:
+		if err := checkJSONResponse(response, bodyBytes); err != nil {
+			return nil, err
+		}
+		return nil, newNetSuiteError(response, bodyBytes)
+	}
+
+	return written, nil
+}
+
+// getJSON sends a GET request to the endpoint and unmarshals the response.
+func (c *Client) getJSON(ctx context.Context, endpoint string, v interface{}) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	response, err := c.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to GET %s: %w", request.URL.Path, err)
+	}
+	defer response.Body.Close()
+
+	bodyBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if err := checkJSONResponse(response, bodyBytes); err != nil {
+		return err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return newNetSuiteError(response, bodyBytes)
+	}
+
+	if err := json.Unmarshal(bodyBytes, v); err != nil {
+		return fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package netsuite
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/glints-dev/mcp-netsuite/pkg/jsonschematree"
+)
+
+// newInactiveTestClient returns a client whose metadata cache holds customer,
+// which has an isInactive field, and employee, which has none.
+func newInactiveTestClient(t *testing.T) *Client {
+	cache := newMetadataCache(10, 0)
+	cache.putDocument("customer", map[string]*jsonschematree.Schema{
+		"customer": {Properties: map[string]*jsonschematree.Schema{"id": {}, "isInactive": {}}},
+		"employee": {Properties: map[string]*jsonschematree.Schema{"id": {}}},
+	})
+
+	return &Client{
+		Client:        &http.Client{Transport: failingTransport{t}},
+		metadataCache: cache,
+	}
+}
+
+func TestHasOr(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter string
+		want   bool
+	}{
+		{name: "single condition", filter: `email START_WITH "barbara"`, want: false},
+		{name: "and", filter: `email START_WITH "barbara" AND isPerson IS true`, want: false},
+		{name: "or", filter: `email START_WITH "barbara" OR email START_WITH "bob"`, want: true},
+		{name: "lower case or", filter: `id EQUAL 1 or id EQUAL 2`, want: true},
+		{name: "or in string value", filter: `companyName IS "Black OR White"`, want: false},
+		{name: "or in string value with escaped quote", filter: `companyName IS "The \"OR\" Company"`, want: false},
+		{name: "or after string value", filter: `companyName IS "a" OR id EQUAL 1`, want: true},
+		{name: "or within word", filter: `category IS "1" AND orderStatus ANY_OF "A"`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasOr(tt.filter); got != tt.want {
+				t.Errorf("hasOr(%q) = %v, want %v", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExcludeInactive(t *testing.T) {
+	tests := []struct {
+		name         string
+		recordType   string
+		filter       string
+		want         string
+		wantExcluded bool
+		wantErr      bool
+	}{
+		{name: "no filter", recordType: "customer", want: "isInactive IS false", wantExcluded: true},
+		{name: "filter", recordType: "customer", filter: `email START_WITH "a"`, want: `email START_WITH "a" AND isInactive IS false`, wantExcluded: true},
+		{name: "or in string value", recordType: "customer", filter: `companyName IS "A OR B"`, want: `companyName IS "A OR B" AND isInactive IS false`, wantExcluded: true},
+		{name: "filter with or", recordType: "customer", filter: `id EQUAL 1 OR id EQUAL 2`, wantErr: true},
+		{name: "no isinactive field", recordType: "employee", filter: `id EQUAL 1 OR id EQUAL 2`, want: `id EQUAL 1 OR id EQUAL 2`, wantExcluded: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newInactiveTestClient(t)
+
+			got, excluded, err := client.ExcludeInactive(tt.recordType, tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExcludeInactive() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want || excluded != tt.wantExcluded {
+				t.Errorf("ExcludeInactive() = %q, %v, want %q, %v", got, excluded, tt.want, tt.wantExcluded)
+			}
+		})
+	}
+}
+
+func TestExcludeInactiveQuery(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		want         string
+		wantExcluded bool
+		wantErr      bool
+	}{
+		{
+			name:         "table with isinactive",
+			query:        "SELECT id, isinactive FROM customer WHERE id > 1",
+			want:         "SELECT * FROM (SELECT id, isinactive FROM customer WHERE id > 1) WHERE isinactive = 'F'",
+			wantExcluded: true,
+		},
+		{
+			name:         "table without isinactive",
+			query:        "SELECT id FROM employee",
+			want:         "SELECT id FROM employee",
+			wantExcluded: false,
+		},
+		{
+			name:    "no table",
+			query:   "SELECT 1",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newInactiveTestClient(t)
+
+			got, excluded, err := client.ExcludeInactiveQuery(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExcludeInactiveQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want || excluded != tt.wantExcluded {
+				t.Errorf("ExcludeInactiveQuery() = %q, %v, want %q, %v", got, excluded, tt.want, tt.wantExcluded)
+			}
+		})
+	}
+}
+
+func TestSendRecord(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		location   string
+		body       string
+		want       *writtenRecord
+		wantErr    bool
+	}{
+		{
+			name:       "created",
+			statusCode: http.StatusCreated,
+			location:   "https://123456.suitetalk.api.netsuite.com/services/rest/record/v1/customer/1234",
+			want:       &writtenRecord{statusCode: http.StatusCreated, id: "1234"},
+		},
+		{
+			name:       "replaced without location",
+			statusCode: http.StatusNoContent,
+			want:       &writtenRecord{statusCode: http.StatusNoContent},
+		},
+		{
+			name:       "accepted as job",
+			statusCode: http.StatusAccepted,
+			location:   "https://123456.suitetalk.api.netsuite.com/services/rest/async/v1/job/42",
+			want:       &writtenRecord{statusCode: http.StatusAccepted, jobID: "42"},
+		},
+		{
+			name:       "accepted as job without location",
+			statusCode: http.StatusAccepted,
+			wantErr:    true,
+		},
+		{
+			name:       "rejected",
+			statusCode: http.StatusBadRequest,
+			body:       `{"title": "Bad Request", "o:errorDetails": [{"detail": "Invalid value.", "o:errorCode": "USER_ERROR", "o:errorPath": "email"}]}`,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Client{Client: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				header := http.Header{"Content-Type": []string{"application/json"}}
+				if tt.location != "" {
+					header.Set("Location", tt.location)
+				}
+				return &http.Response{
+					StatusCode: tt.statusCode,
+					Header:     header,
+					Body:       io.NopCloser(strings.NewReader(tt.body)),
+					Request:    req,
+				}, nil
+			})}}
+
+			got, err := client.sendRecord(context.Background(), http.MethodPost, "/record/v1/customer", map[string]interface{}{"companyName": "Acme"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sendRecord() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if *got != *tt.want {
+				t.Errorf("sendRecord() = %+v, want %+v", *got, *tt.want)
+			}
+		})
+	}
+}
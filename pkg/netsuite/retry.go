@@ -0,0 +1,56 @@
+package netsuite
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+	"time"
+)
+
+const (
+	// DefaultSuiteQLRetries is the number of times a SuiteQL query is resent
+	// after a network error by default.
+	DefaultSuiteQLRetries = 2
+
+	// suiteQLRetryBackoff is how long to wait before the first retry. Each
+	// further retry waits twice as long as the previous one.
+	suiteQLRetryBackoff = 200 * time.Millisecond
+)
+
+// isTransientNetworkError reports whether an error is a dropped connection,
+// after which a read-only request can safely be sent again. Timeouts are not
+// transient, since resending a slow query is likely to time out again, and
+// neither are errors from the context or the circuit breaker.
+func isTransientNetworkError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+
+	return errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+// retryTransient calls attempt until it succeeds, fails with an error that is
+// not a transient network error, or has been retried retries times. The wait
+// between attempts doubles every time, and is cut short by the context.
+func retryTransient[T any](ctx context.Context, retries int, attempt func() (T, error)) (T, error) {
+	backoff := suiteQLRetryBackoff
+	for retry := 0; ; retry++ {
+		result, err := attempt()
+		if retry >= retries || !isTransientNetworkError(err) {
+			return result, err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return result, err
+		}
+		backoff *= 2
+	}
+}
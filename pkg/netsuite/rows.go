@@ -0,0 +1,122 @@
+package netsuite
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/glints-dev/mcp-netsuite/pkg/jsonschematree"
+)
+
+// Row is a single result row keyed by column name.
+type Row map[string]interface{}
+
+// Rows decodes the result items into rows. Numbers are kept as json.Number so
+// that no precision is lost before the caller decides how to interpret them.
+func (r *SuiteQLResponse) Rows() ([]Row, error) {
+	rows := make([]Row, 0, len(r.Items))
+	for _, item := range r.Items {
+		decoder := json.NewDecoder(bytes.NewReader(item))
+		decoder.UseNumber()
+
+		var row Row
+		if err := decoder.Decode(&row); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// Scan decodes the result items into dest, which must be a pointer to a slice
+// of structs or maps. Columns are matched to struct fields by their json tag
+// or name regardless of case, since SuiteQL lowercases column names: a column
+// "companyname" fills a field tagged `json:"companyName"`.
+func (r *SuiteQLResponse) Scan(dest interface{}) error {
+	value := reflect.ValueOf(dest)
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("destination must be a non-nil pointer to a slice, got %T", dest)
+	}
+
+	itemsJSON, err := json.Marshal(r.Items)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	if err := json.Unmarshal(itemsJSON, dest); err != nil {
+		return fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	return nil
+}
+
+// Decimal returns the value of a monetary or decimal column. NetSuite returns
+// these as strings to preserve precision, so they should never be parsed as
+// float64. A nil value is returned for a missing or null column.
+func (r Row) Decimal(column string) (*big.Rat, error) {
+	var text string
+	switch value := r[column].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		text = value
+	case json.Number:
+		text = value.String()
+	case *big.Rat:
+		return value, nil
+	default:
+		return nil, fmt.Errorf("column \"%s\" is not a decimal: %v", column, value)
+	}
+
+	rat, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return nil, fmt.Errorf("column \"%s\" is not a decimal: %s", column, text)
+	}
+
+	return rat, nil
+}
+
+// Typed returns a copy of the row in which every column the schema marks as
+// a decimal is converted to a *big.Rat. Columns are matched to properties
+// regardless of case, since SuiteQL lowercases the column names of catalog
+// properties such as "foreignTotal".
+func (r Row) Typed(schema *jsonschematree.Schema) (Row, error) {
+	if schema == nil {
+		return nil, errors.New("no schema given")
+	}
+
+	properties := make(map[string]*jsonschematree.Schema, len(schema.Properties))
+	for name, property := range schema.Properties {
+		properties[strings.ToLower(name)] = property
+	}
+
+	typed := make(Row, len(r))
+	for column, value := range r {
+		typed[column] = value
+
+		property, ok := schema.Properties[column]
+		if !ok {
+			property, ok = properties[strings.ToLower(column)]
+		}
+		if !ok || property == nil || !property.IsDecimal() {
+			continue
+		}
+
+		rat, err := r.Decimal(column)
+		if err != nil {
+			return nil, err
+		}
+
+		if rat != nil {
+			typed[column] = rat
+		}
+	}
+
+	return typed, nil
+}
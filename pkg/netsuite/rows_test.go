@@ -0,0 +1,106 @@
+package netsuite
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/glints-dev/mcp-netsuite/pkg/jsonschematree"
+)
+
+func TestRowDecimal(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    *big.Rat
+		wantErr bool
+	}{
+		{name: "string", value: "1250.10", want: big.NewRat(125010, 100)},
+		{name: "leading point", value: "-.5", want: big.NewRat(-1, 2)},
+		{name: "number", value: json.Number("3"), want: big.NewRat(3, 1)},
+		{name: "rat", value: big.NewRat(1, 3), want: big.NewRat(1, 3)},
+		{name: "null", value: nil},
+		{name: "not a number", value: "Acme", wantErr: true},
+		{name: "boolean", value: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Row{"amount": tt.value}.Decimal("amount")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decimal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && got.Cmp(tt.want) != 0) {
+				t.Errorf("Decimal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRowTyped(t *testing.T) {
+	schema := &jsonschematree.Schema{Properties: map[string]*jsonschematree.Schema{
+		"foreignTotal": {Type: []string{"number"}, Format: "double"},
+		"memo":         {Type: []string{"string"}},
+	}}
+
+	tests := []struct {
+		name        string
+		row         Row
+		schema      *jsonschematree.Schema
+		wantDecimal map[string]*big.Rat
+		wantErr     bool
+	}{
+		{
+			name:        "lower case column",
+			row:         Row{"foreigntotal": "99.95", "memo": "12.5"},
+			schema:      schema,
+			wantDecimal: map[string]*big.Rat{"foreigntotal": big.NewRat(9995, 100)},
+		},
+		{
+			name:        "exact column",
+			row:         Row{"foreignTotal": json.Number("10")},
+			schema:      schema,
+			wantDecimal: map[string]*big.Rat{"foreignTotal": big.NewRat(10, 1)},
+		},
+		{
+			name:        "null decimal",
+			row:         Row{"foreigntotal": nil},
+			schema:      schema,
+			wantDecimal: map[string]*big.Rat{},
+		},
+		{
+			name:    "invalid decimal",
+			row:     Row{"foreigntotal": "n/a"},
+			schema:  schema,
+			wantErr: true,
+		},
+		{
+			name:    "no schema",
+			row:     Row{"foreigntotal": "99.95"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.row.Typed(tt.schema)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Typed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			for column, value := range got {
+				rat, isRat := value.(*big.Rat)
+				want, wantRat := tt.wantDecimal[column]
+				if isRat != wantRat || (isRat && rat.Cmp(want) != 0) {
+					t.Errorf("Typed()[%q] = %v, want %v", column, value, want)
+				}
+				if !isRat && value != tt.row[column] {
+					t.Errorf("Typed()[%q] = %v, want it unchanged", column, value)
+				}
+			}
+		})
+	}
+}
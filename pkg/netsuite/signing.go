@@ -0,0 +1,116 @@
+package netsuite
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// SigningAlgorithm is the algorithm the client assertion is signed with. It
+// must match the certificate uploaded to NetSuite.
+type SigningAlgorithm string
+
+const (
+	// SigningAlgorithmPS256 is recommended over RS256 by NetSuite. See
+	// https://www.scottbrady91.com/jose/jwts-which-signing-algorithm-should-i-use
+	SigningAlgorithmPS256 SigningAlgorithm = "PS256"
+	SigningAlgorithmRS256 SigningAlgorithm = "RS256"
+	SigningAlgorithmES256 SigningAlgorithm = "ES256"
+)
+
+// ErrKeyAlgorithmMismatch is returned when the private key cannot be used
+// with the signing algorithm, such as an RSA key with ES256.
+var ErrKeyAlgorithmMismatch = errors.New("private key does not match the signing algorithm")
+
+// signingMethod returns the JWT signing method for the algorithm, which
+// defaults to PS256.
+func signingMethod(algorithm SigningAlgorithm) (jwt.SigningMethod, error) {
+	switch SigningAlgorithm(strings.ToUpper(string(algorithm))) {
+	case "", SigningAlgorithmPS256:
+		return jwt.SigningMethodPS256, nil
+	case SigningAlgorithmRS256:
+		return jwt.SigningMethodRS256, nil
+	case SigningAlgorithmES256:
+		return jwt.SigningMethodES256, nil
+	default:
+		return nil, fmt.Errorf(
+			"unsupported signing algorithm \"%s\": expected %s, %s, or %s",
+			algorithm,
+			SigningAlgorithmPS256,
+			SigningAlgorithmRS256,
+			SigningAlgorithmES256,
+		)
+	}
+}
+
+// checkKeyType returns ErrKeyAlgorithmMismatch if the key cannot sign with
+// the method, including EC keys on a curve other than the method's, such as
+// a P-384 key with ES256.
+func checkKeyType(method jwt.SigningMethod, key interface{}) error {
+	switch method := method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		if _, ok := key.(*rsa.PrivateKey); !ok {
+			return fmt.Errorf("%w: %s requires an RSA key, got %s", ErrKeyAlgorithmMismatch, method.Alg(), keyTypeName(key))
+		}
+	case *jwt.SigningMethodECDSA:
+		ecKey, ok := key.(*ecdsa.PrivateKey)
+		if !ok {
+			return fmt.Errorf("%w: %s requires an EC key, got %s", ErrKeyAlgorithmMismatch, method.Alg(), keyTypeName(key))
+		}
+		if params := ecKey.Curve.Params(); params.BitSize != method.CurveBits {
+			return fmt.Errorf("%w: %s requires a P-%d key, got a %s key", ErrKeyAlgorithmMismatch, method.Alg(), method.CurveBits, params.Name)
+		}
+	}
+
+	return nil
+}
+
+// keyTypeName describes the type of a private key for error messages.
+func keyTypeName(key interface{}) string {
+	switch key.(type) {
+	case *rsa.PrivateKey:
+		return "an RSA key"
+	case *ecdsa.PrivateKey:
+		return "an EC key"
+	default:
+		return fmt.Sprintf("a %T", key)
+	}
+}
+
+// parsePrivateKey parses the first private key in the PEM data, which may be
+// an RSA key ("RSA PRIVATE KEY"), an EC key ("EC PRIVATE KEY"), or either of
+// them in PKCS #8 ("PRIVATE KEY"). Other blocks, such as a bundled
+// certificate, are skipped.
+func parsePrivateKey(pemBytes []byte) (crypto.Signer, error) {
+	for block, rest := pem.Decode(pemBytes); block != nil; block, rest = pem.Decode(rest) {
+		switch block.Type {
+		case "RSA PRIVATE KEY":
+			return x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			return x509.ParseECPrivateKey(block.Bytes)
+		case "PRIVATE KEY":
+			key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+
+			switch key := key.(type) {
+			case *rsa.PrivateKey:
+				return key, nil
+			case *ecdsa.PrivateKey:
+				return key, nil
+			default:
+				return nil, fmt.Errorf("unsupported private key type %T: expected an RSA or EC key", key)
+			}
+		}
+	}
+
+	return nil, errors.New("no private key found in PEM data")
+}
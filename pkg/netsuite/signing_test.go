@@ -0,0 +1,148 @@
+package netsuite
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// testKeys generates an RSA key, and EC keys on the P-256 and P-384 curves.
+func testKeys(t *testing.T) (*rsa.PrivateKey, *ecdsa.PrivateKey, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate P-256 key: %v", err)
+	}
+
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate P-384 key: %v", err)
+	}
+
+	return rsaKey, p256Key, p384Key
+}
+
+func encodePEM(blockType string, bytes []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: bytes})
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	rsaKey, p256Key, _ := testKeys(t)
+
+	pkcs8RSA, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	if err != nil {
+		t.Fatalf("failed to marshal RSA key: %v", err)
+	}
+	sec1EC, err := x509.MarshalECPrivateKey(p256Key)
+	if err != nil {
+		t.Fatalf("failed to marshal EC key: %v", err)
+	}
+	pkcs8EC, err := x509.MarshalPKCS8PrivateKey(p256Key)
+	if err != nil {
+		t.Fatalf("failed to marshal EC key: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		pem     []byte
+		want    crypto.PublicKey
+		wantErr bool
+	}{
+		{name: "PKCS #1 RSA", pem: encodePEM("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)), want: &rsaKey.PublicKey},
+		{name: "PKCS #8 RSA", pem: encodePEM("PRIVATE KEY", pkcs8RSA), want: &rsaKey.PublicKey},
+		{name: "SEC 1 EC", pem: encodePEM("EC PRIVATE KEY", sec1EC), want: &p256Key.PublicKey},
+		{name: "PKCS #8 EC", pem: encodePEM("PRIVATE KEY", pkcs8EC), want: &p256Key.PublicKey},
+		{name: "after a certificate", pem: append(encodePEM("CERTIFICATE", []byte("certificate")), encodePEM("EC PRIVATE KEY", sec1EC)...), want: &p256Key.PublicKey},
+		{name: "no private key", pem: encodePEM("CERTIFICATE", []byte("certificate")), wantErr: true},
+		{name: "not PEM", pem: []byte("not a key"), wantErr: true},
+		{name: "corrupt key", pem: encodePEM("RSA PRIVATE KEY", []byte("corrupt")), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := parsePrivateKey(tt.pem)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePrivateKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if publicKey, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !publicKey.Equal(tt.want) {
+				t.Errorf("parsePrivateKey() returned a different key")
+			}
+		})
+	}
+}
+
+func TestCheckKeyType(t *testing.T) {
+	rsaKey, p256Key, p384Key := testKeys(t)
+
+	tests := []struct {
+		name      string
+		algorithm SigningAlgorithm
+		key       crypto.Signer
+		wantErr   bool
+	}{
+		{name: "PS256 with RSA", algorithm: SigningAlgorithmPS256, key: rsaKey},
+		{name: "RS256 with RSA", algorithm: SigningAlgorithmRS256, key: rsaKey},
+		{name: "default with RSA", algorithm: "", key: rsaKey},
+		{name: "ES256 with P-256", algorithm: SigningAlgorithmES256, key: p256Key},
+		{name: "lower case ES256 with P-256", algorithm: "es256", key: p256Key},
+		{name: "ES256 with P-384", algorithm: SigningAlgorithmES256, key: p384Key, wantErr: true},
+		{name: "ES256 with RSA", algorithm: SigningAlgorithmES256, key: rsaKey, wantErr: true},
+		{name: "PS256 with EC", algorithm: SigningAlgorithmPS256, key: p256Key, wantErr: true},
+		{name: "RS256 with EC", algorithm: SigningAlgorithmRS256, key: p256Key, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method, err := signingMethod(tt.algorithm)
+			if err != nil {
+				t.Fatalf("signingMethod(%q) error = %v", tt.algorithm, err)
+			}
+
+			err = checkKeyType(method, tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkKeyType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrKeyAlgorithmMismatch) {
+					t.Errorf("checkKeyType() error = %v, want %v", err, ErrKeyAlgorithmMismatch)
+				}
+				return
+			}
+
+			// Keys that pass the check must sign assertions that verify
+			signed, err := jwt.NewWithClaims(method, jwt.MapClaims{"iss": "client"}).SignedString(tt.key)
+			if err != nil {
+				t.Fatalf("failed to sign: %v", err)
+			}
+			if _, err := jwt.Parse(signed, func(*jwt.Token) (interface{}, error) { return tt.key.Public(), nil }); err != nil {
+				t.Errorf("failed to verify: %v", err)
+			}
+		})
+	}
+}
+
+func TestSigningMethodUnsupported(t *testing.T) {
+	for _, algorithm := range []SigningAlgorithm{"HS256", "ES384", "none"} {
+		if _, err := signingMethod(algorithm); err == nil {
+			t.Errorf("signingMethod(%q) error = nil, want an error", algorithm)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package netsuite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+)
+
+// ErrUnknownSubsidiary is returned when the subsidiary records are written
+// for does not exist.
+var ErrUnknownSubsidiary = errors.New("subsidiary does not exist")
+
+type subsidiaryKey struct{}
+
+// WithSubsidiary returns a context whose record writes default to the
+// subsidiary, overriding ClientOptions.Subsidiary. NetSuite has no
+// per-request subsidiary context, so the subsidiary is set on the records
+// that have a subsidiary field and do not set it themselves.
+func WithSubsidiary(ctx context.Context, id int) context.Context {
+	return context.WithValue(ctx, subsidiaryKey{}, id)
+}
+
+// contextSubsidiary returns the subsidiary of the context, or the default of
+// the client. Zero means no subsidiary.
+func (c *Client) contextSubsidiary(ctx context.Context) int {
+	if id, ok := ctx.Value(subsidiaryKey{}).(int); ok {
+		return id
+	}
+
+	return c.subsidiary
+}
+
+// ValidateSubsidiary returns ErrUnknownSubsidiary if no subsidiary has the
+// internal ID. Subsidiaries found are remembered for the life of the client.
+func (c *Client) ValidateSubsidiary(ctx context.Context, id int) error {
+	c.subsidiariesMutex.Lock()
+	_, known := c.subsidiaries[id]
+	c.subsidiariesMutex.Unlock()
+	if known {
+		return nil
+	}
+
+	var rows []Row
+	if err := c.queryInto(ctx, fmt.Sprintf("SELECT id FROM subsidiary WHERE id = %d", id), &rows); err != nil {
+		return fmt.Errorf("failed to look up subsidiary %d: %w", id, err)
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf("%w: %d", ErrUnknownSubsidiary, id)
+	}
+
+	c.subsidiariesMutex.Lock()
+	if c.subsidiaries == nil {
+		c.subsidiaries = make(map[int]struct{})
+	}
+	c.subsidiaries[id] = struct{}{}
+	c.subsidiariesMutex.Unlock()
+
+	return nil
+}
+
+// applySubsidiary returns the body with its subsidiary set to the subsidiary
+// of the context, if the record type has a subsidiary field the body does not
+// set. The body given is never modified.
+func (c *Client) applySubsidiary(ctx context.Context, recordType string, body map[string]interface{}) (map[string]interface{}, error) {
+	id := c.contextSubsidiary(ctx)
+	if id == 0 {
+		return body, nil
+	}
+	if _, ok := body["subsidiary"]; ok {
+		return body, nil
+	}
+
+	metadata, err := c.Metadata(recordType, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata for record type '%s': %w", recordType, err)
+	}
+	if metadata == nil || metadata.Properties["subsidiary"] == nil {
+		return body, nil
+	}
+
+	if err := c.ValidateSubsidiary(ctx, id); err != nil {
+		return nil, err
+	}
+
+	body = maps.Clone(body)
+	if body == nil {
+		body = make(map[string]interface{})
+	}
+	body["subsidiary"] = map[string]interface{}{"id": strconv.Itoa(id)}
+
+	return body, nil
+}
@@ -0,0 +1,173 @@
+package netsuite
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// maxColumnSuggestions is the number of similar columns suggested for an
+// unknown one.
+const maxColumnSuggestions = 3
+
+// maxTableSuggestions is the number of similar record types suggested for an
+// unknown table.
+const maxTableSuggestions = 3
+
+// unknownColumnPatterns match the error details NetSuite gives for columns
+// that do not exist. The first group is the column, and the second, if any,
+// the table.
+var unknownColumnPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`Field '([^']+)' for record '([^']+)' was not found`),
+	regexp.MustCompile(`(?i)unknown identifier '([^']+)'`),
+	regexp.MustCompile(`(?i)invalid search column:? '?([\w.]+)'?`),
+}
+
+// unknownTablePatterns match the error details NetSuite gives for tables that
+// do not exist in SuiteQL. The first group is the table.
+var unknownTablePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\bRecord '([^']+)' was not found`),
+	regexp.MustCompile(`(?i)unknown (?:table|record type) '?([\w]+)'?`),
+	regexp.MustCompile(`(?i)table '?([\w]+)'? (?:was )?not found`),
+}
+
+// UnknownTable returns the table NetSuite rejected a query for, or an empty
+// string for other errors.
+func (e *NetSuiteError) UnknownTable() string {
+	for _, detail := range e.Details {
+		for _, pattern := range unknownTablePatterns {
+			if match := pattern.FindStringSubmatch(detail.Detail); match != nil {
+				return strings.ToLower(match[1])
+			}
+		}
+	}
+
+	return ""
+}
+
+// TableAlternatives looks up a table SuiteQL does not know among the record
+// types of the REST record service. If the table is one of them, it returns
+// the record type, whose records can be listed with QueryRecords instead.
+// Otherwise it returns the record types closest to the table by edit
+// distance. Both are empty when the record types cannot be listed.
+func (c *Client) TableAlternatives(table string) (recordType string, suggestions []string) {
+	recordTypes, err := c.catalogRecordTypes()
+	if err != nil {
+		return "", nil
+	}
+
+	names := make([]string, 0, len(recordTypes))
+	for name := range recordTypes {
+		if strings.EqualFold(name, table) {
+			return name, nil
+		}
+		names = append(names, strings.ToLower(name))
+	}
+
+	return "", closestNames(strings.ToLower(table), names, maxTableSuggestions)
+}
+
+// UnknownColumn returns the column NetSuite rejected a query for, along with
+// its table if NetSuite named it. It returns empty strings for other errors.
+func (e *NetSuiteError) UnknownColumn() (column string, table string) {
+	for _, detail := range e.Details {
+		for _, pattern := range unknownColumnPatterns {
+			match := pattern.FindStringSubmatch(detail.Detail)
+			if match == nil {
+				continue
+			}
+
+			column = strings.ToLower(match[1])
+			if len(match) > 2 {
+				table = strings.ToLower(match[2])
+			}
+
+			// Qualified columns such as "c.compnyname" name a table alias
+			if i := strings.LastIndexByte(column, '.'); i >= 0 {
+				column = column[i+1:]
+			}
+
+			return column, table
+		}
+	}
+
+	return "", ""
+}
+
+// SuggestColumns returns the columns of a table closest to an unknown column
+// by edit distance, closest first, to correct typos. The columns are taken
+// from the metadata catalog, or sampled from the table if it has no metadata.
+// It returns nil when the table's columns cannot be listed or none is close.
+func (c *Client) SuggestColumns(ctx context.Context, table string, column string) []string {
+	columns, err := c.FieldCatalog(table)
+	if err != nil {
+		columns, err = c.DescribeTable(ctx, table)
+		if err != nil {
+			return nil
+		}
+	}
+
+	names := make([]string, 0, len(columns))
+	for _, candidate := range columns {
+		names = append(names, candidate.Name)
+	}
+
+	return closestNames(strings.ToLower(column), names, maxColumnSuggestions)
+}
+
+// closestNames returns at most n names within a third of the target's length
+// in edit distance (and at least two edits) of the target, closest first.
+func closestNames(target string, names []string, n int) []string {
+	maxDistance := max(2, len(target)/3)
+
+	type match struct {
+		name     string
+		distance int
+	}
+	var matches []match
+	for _, name := range names {
+		if distance := editDistance(target, name); distance <= maxDistance {
+			matches = append(matches, match{name: name, distance: distance})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].name < matches[j].name
+	})
+
+	var closest []string
+	for _, match := range matches[:min(n, len(matches))] {
+		closest = append(closest, match.name)
+	}
+
+	return closest
+}
+
+// editDistance returns the Levenshtein distance between two strings.
+func editDistance(a string, b string) int {
+	source, target := []rune(a), []rune(b)
+
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(target)]
+}
@@ -0,0 +1,181 @@
+package netsuite
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	identifierPattern  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	sourceTablePattern = regexp.MustCompile(`(?i)\bFROM\s+([A-Za-z_][A-Za-z0-9_]*)`)
+	stringLiteral      = regexp.MustCompile(`'(?:[^']|'')*'`)
+	literalOrComment   = regexp.MustCompile(`(?s)'(?:[^']|'')*'|--[^\n]*|/\*.*?\*/`)
+	// literalIdentifierOrComment also matches double-quoted identifiers
+	literalIdentifierOrComment = regexp.MustCompile(`(?s)'(?:[^']|'')*'|"(?:[^"]|"")*"|--[^\n]*|/\*.*?\*/`)
+	pagingPattern              = regexp.MustCompile(`(?i)\b(?:LIMIT\s+\d+|OFFSET\s+\d+|FETCH\s+(?:FIRST|NEXT)\b)`)
+	selectStarPattern          = regexp.MustCompile(`(?i)(?:\bSELECT\s+(?:(?:DISTINCT|ALL)\s+)?|,\s*)(?:[A-Za-z_][A-Za-z0-9_]*\s*\.\s*)?\*`)
+)
+
+// HasPaging reports whether the query pages its own results with LIMIT,
+// OFFSET, or FETCH FIRST/NEXT, ignoring string literals and comments. Such
+// queries conflict with the limit and offset URL parameters of SuiteQL.
+func HasPaging(query string) bool {
+	return pagingPattern.MatchString(stripLiteralsAndComments(query))
+}
+
+// HasSelectStar reports whether the query selects every column with "*" or
+// "alias.*", ignoring string literals and comments. COUNT(*) does not count.
+func HasSelectStar(query string) bool {
+	return selectStarPattern.MatchString(stripLiteralsAndComments(query))
+}
+
+// stripLiteralsAndComments empties the string literals of a query and
+// replaces its comments with a space. Both are matched in a single pass, so
+// that "--" within a literal and quotes within a comment are left alone.
+func stripLiteralsAndComments(query string) string {
+	return literalOrComment.ReplaceAllStringFunc(query, func(match string) string {
+		if strings.HasPrefix(match, "'") {
+			return "''"
+		}
+		return " "
+	})
+}
+
+// SourceTable returns the first table named in the FROM clause of a SuiteQL
+// query, in lower case, or an empty string if none could be found. It is a
+// best-effort heuristic and does not parse subqueries or joins.
+func SourceTable(query string) string {
+	match := sourceTablePattern.FindStringSubmatch(query)
+	if match == nil {
+		return ""
+	}
+
+	return strings.ToLower(match[1])
+}
+
+// DisplayValueExpression returns the SuiteQL expression selecting the display
+// value of a select field instead of its internal ID, e.g. "BUILTIN.DF(entity)".
+func DisplayValueExpression(field string) string {
+	return fmt.Sprintf("BUILTIN.DF(%s)", strings.ToLower(field))
+}
+
+// DisplayValueExpression returns the BUILTIN.DF expression for a field of a
+// record type after checking in the catalog that it is a select field.
+func (c *Client) DisplayValueExpression(recordType string, field string) (string, error) {
+	metadata, err := c.Metadata(recordType, nil)
+	if err != nil {
+		return "", err
+	}
+	if metadata == nil {
+		return "", fmt.Errorf("no metadata found for record type %s", recordType)
+	}
+
+	for property, schema := range metadata.Properties {
+		if !strings.EqualFold(property, field) {
+			continue
+		}
+
+		if !schema.IsReference() {
+			return "", fmt.Errorf("field %s of record type %s is not a select field", field, recordType)
+		}
+
+		return DisplayValueExpression(property), nil
+	}
+
+	return "", fmt.Errorf("field %s not found on record type %s", field, recordType)
+}
+
+// SuiteQLNamed executes a SuiteQL query with named placeholders such as
+// ":since" or ":status". The placeholders are replaced by positional ones and
+// their values are bound in order of appearance, so values are never spliced
+// into the query text. A placeholder may appear more than once.
+func (c *Client) SuiteQLNamed(ctx context.Context, query string, params map[string]interface{}, limit int, offset int) (*SuiteQLResponse, error) {
+	positionalQuery, positionalParams, err := bindNamedParams(query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.suiteQL(ctx, positionalQuery, positionalParams, limit, offset)
+}
+
+// bindNamedParams replaces every named placeholder outside string literals,
+// quoted identifiers, and comments with "?" and returns the values in the
+// order of the placeholders.
+func bindNamedParams(query string, params map[string]interface{}) (string, []interface{}, error) {
+	var builder strings.Builder
+	var positionalParams []interface{}
+	var missing []string
+
+	// bind copies a part of the query without literals, identifiers, or
+	// comments, replacing its placeholders
+	bind := func(part string) {
+		runes := []rune(part)
+		for i := 0; i < len(runes); i++ {
+			r := runes[i]
+
+			isPlaceholder := r == ':' &&
+				i+1 < len(runes) &&
+				isParamStart(runes[i+1]) &&
+				(i == 0 || runes[i-1] != ':')
+			if !isPlaceholder {
+				builder.WriteRune(r)
+				continue
+			}
+
+			end := i + 1
+			for end < len(runes) && isParamPart(runes[end]) {
+				end++
+			}
+
+			name := string(runes[i+1 : end])
+			value, ok := params[name]
+			if !ok {
+				missing = append(missing, name)
+			}
+
+			builder.WriteRune('?')
+			positionalParams = append(positionalParams, value)
+			i = end - 1
+		}
+	}
+
+	last := 0
+	for _, match := range literalIdentifierOrComment.FindAllStringIndex(query, -1) {
+		bind(query[last:match[0]])
+		builder.WriteString(query[match[0]:match[1]])
+		last = match[1]
+	}
+	bind(query[last:])
+
+	if len(missing) > 0 {
+		return "", nil, fmt.Errorf("no value given for placeholders: %s", strings.Join(missing, ", "))
+	}
+
+	return builder.String(), positionalParams, nil
+}
+
+func isParamStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isParamPart(r rune) bool {
+	return isParamStart(r) || unicode.IsDigit(r)
+}
+
+type asOfDateKey struct{}
+
+// WithAsOfDate returns a context that runs SuiteQL queries as of the given
+// date (YYYY-MM-DD), for effective-dated reporting. NetSuite only honours it
+// where historical context is supported, and rejects the query otherwise.
+func WithAsOfDate(ctx context.Context, date string) context.Context {
+	return context.WithValue(ctx, asOfDateKey{}, date)
+}
+
+// asOfDate returns the date set with WithAsOfDate, if any.
+func asOfDate(ctx context.Context) string {
+	date, _ := ctx.Value(asOfDateKey{}).(string)
+	return date
+}
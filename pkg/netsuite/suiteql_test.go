@@ -0,0 +1,158 @@
+package netsuite
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHasPaging(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  bool
+	}{
+		{name: "no paging", query: "SELECT id FROM customer", want: false},
+		{name: "limit", query: "SELECT id FROM customer LIMIT 10", want: true},
+		{name: "lower case limit", query: "select id from customer limit 10", want: true},
+		{name: "offset", query: "SELECT id FROM customer ORDER BY id OFFSET 20 ROWS", want: true},
+		{name: "fetch first", query: "SELECT id FROM customer FETCH FIRST 10 ROWS ONLY", want: true},
+		{name: "fetch next", query: "SELECT id FROM customer OFFSET 10 ROWS FETCH NEXT 10 ROWS ONLY", want: true},
+		{name: "limit in literal", query: "SELECT id FROM customer WHERE memo = 'LIMIT 10'", want: false},
+		{name: "fetch in literal with escaped quote", query: "SELECT id FROM customer WHERE memo = 'don''t FETCH FIRST'", want: false},
+		{name: "limit in line comment", query: "SELECT id FROM customer -- LIMIT 10\nWHERE id > 1", want: false},
+		{name: "limit in block comment", query: "SELECT id /* LIMIT 10 */ FROM customer", want: false},
+		{name: "limit after literal with dashes", query: "SELECT id FROM customer WHERE memo = 'a -- b' LIMIT 10", want: true},
+		{name: "limit after comment with quote", query: "SELECT id FROM customer -- it's paged\nLIMIT 10", want: true},
+		{name: "column named limit", query: "SELECT creditlimit FROM customer", want: false},
+		{name: "limit without count", query: "SELECT id FROM customer WHERE limit_reached = 'T'", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasPaging(tt.query); got != tt.want {
+				t.Errorf("HasPaging(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBindNamedParams(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		params     map[string]interface{}
+		wantQuery  string
+		wantParams []interface{}
+		wantErr    bool
+	}{
+		{
+			name:       "placeholders",
+			query:      "SELECT id FROM transaction WHERE status = :status AND trandate >= :since",
+			params:     map[string]interface{}{"status": "A", "since": "2024-01-01"},
+			wantQuery:  "SELECT id FROM transaction WHERE status = ? AND trandate >= ?",
+			wantParams: []interface{}{"A", "2024-01-01"},
+		},
+		{
+			name:       "repeated placeholder",
+			query:      "SELECT id FROM transaction WHERE createddate >= :since OR lastmodifieddate >= :since",
+			params:     map[string]interface{}{"since": "2024-01-01"},
+			wantQuery:  "SELECT id FROM transaction WHERE createddate >= ? OR lastmodifieddate >= ?",
+			wantParams: []interface{}{"2024-01-01", "2024-01-01"},
+		},
+		{
+			name:      "cast",
+			query:     "SELECT id::varchar FROM customer",
+			wantQuery: "SELECT id::varchar FROM customer",
+		},
+		{
+			name:       "placeholder in literal",
+			query:      "SELECT id FROM customer WHERE memo = 'at :noon' AND id = :id",
+			params:     map[string]interface{}{"id": 1},
+			wantQuery:  "SELECT id FROM customer WHERE memo = 'at :noon' AND id = ?",
+			wantParams: []interface{}{1},
+		},
+		{
+			name:       "placeholder in line comment",
+			query:      "SELECT id FROM transaction -- filter by :status\nWHERE id = :id",
+			params:     map[string]interface{}{"id": 1},
+			wantQuery:  "SELECT id FROM transaction -- filter by :status\nWHERE id = ?",
+			wantParams: []interface{}{1},
+		},
+		{
+			name:       "placeholder in block comment",
+			query:      "SELECT id /* :status */ FROM transaction WHERE id = :id",
+			params:     map[string]interface{}{"id": 1},
+			wantQuery:  "SELECT id /* :status */ FROM transaction WHERE id = ?",
+			wantParams: []interface{}{1},
+		},
+		{
+			name:       "placeholder in quoted identifier",
+			query:      `SELECT id AS "a:b" FROM customer WHERE id = :id`,
+			params:     map[string]interface{}{"id": 1},
+			wantQuery:  `SELECT id AS "a:b" FROM customer WHERE id = ?`,
+			wantParams: []interface{}{1},
+		},
+		{
+			name:    "missing param",
+			query:   "SELECT id FROM transaction WHERE status = :status AND id = :id",
+			params:  map[string]interface{}{"id": 1},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery, gotParams, err := bindNamedParams(tt.query, tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("bindNamedParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotQuery != tt.wantQuery {
+				t.Errorf("bindNamedParams() query = %q, want %q", gotQuery, tt.wantQuery)
+			}
+			if !reflect.DeepEqual(gotParams, tt.wantParams) {
+				t.Errorf("bindNamedParams() params = %v, want %v", gotParams, tt.wantParams)
+			}
+		})
+	}
+}
+
+func TestSuiteQLNamed(t *testing.T) {
+	var got struct {
+		Q      string        `json:"q"`
+		Params []interface{} `json:"params"`
+	}
+	client := &Client{Client: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if err := json.NewDecoder(req.Body).Decode(&got); err != nil {
+			t.Fatalf("failed to decode request body: %v", err)
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"count": 0, "hasMore": false, "items": []}`)),
+			Request:    req,
+		}, nil
+	})}}
+
+	query := "SELECT id FROM transaction -- :ignored\nWHERE status = :status AND entity = :entity"
+	if _, err := client.SuiteQLNamed(context.Background(), query, map[string]interface{}{"status": "A", "entity": "7"}, 10, 0); err != nil {
+		t.Fatalf("SuiteQLNamed() error = %v", err)
+	}
+
+	wantQuery := "SELECT id FROM transaction -- :ignored\nWHERE status = ? AND entity = ?"
+	if got.Q != wantQuery {
+		t.Errorf("query sent = %q, want %q", got.Q, wantQuery)
+	}
+	if !reflect.DeepEqual(got.Params, []interface{}{"A", "7"}) {
+		t.Errorf("params sent = %v, want [A 7]", got.Params)
+	}
+
+	if _, err := client.SuiteQLNamed(context.Background(), "SELECT id FROM transaction WHERE status = :status", nil, 10, 0); err == nil {
+		t.Errorf("SuiteQLNamed() without params error = nil, want an error")
+	}
+}
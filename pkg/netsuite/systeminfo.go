@@ -0,0 +1,130 @@
+package netsuite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// SystemInfoTopic is a kind of information about the account itself, read
+// from a system table with SuiteQL.
+type SystemInfoTopic string
+
+// Supported system information topics
+const (
+	SystemInfoRoles        SystemInfoTopic = "roles"
+	SystemInfoSubsidiaries SystemInfoTopic = "subsidiaries"
+	SystemInfoCurrencies   SystemInfoTopic = "currencies"
+	SystemInfoDepartments  SystemInfoTopic = "departments"
+	SystemInfoLocations    SystemInfoTopic = "locations"
+	SystemInfoClasses      SystemInfoTopic = "classes"
+)
+
+// systemInfoQuery is the query of a topic, and the feature that must be
+// enabled for its table to exist, if any.
+type systemInfoQuery struct {
+	query   string
+	feature string
+}
+
+var systemInfoQueries = map[SystemInfoTopic]systemInfoQuery{
+	SystemInfoRoles: {
+		query: "SELECT id, name, scriptid, isinactive FROM role ORDER BY name",
+	},
+	SystemInfoSubsidiaries: {
+		query:   "SELECT id, name, country, BUILTIN.DF(currency) AS currency, parent, isinactive FROM subsidiary ORDER BY id",
+		feature: "Subsidiaries (NetSuite OneWorld)",
+	},
+	SystemInfoCurrencies: {
+		query: "SELECT id, symbol, name, isbasecurrency, isinactive FROM currency ORDER BY id",
+	},
+	SystemInfoDepartments: {
+		query:   "SELECT id, name, fullname, isinactive FROM department ORDER BY fullname",
+		feature: "Departments",
+	},
+	SystemInfoLocations: {
+		query:   "SELECT id, name, fullname, isinactive FROM location ORDER BY fullname",
+		feature: "Locations",
+	},
+	SystemInfoClasses: {
+		query:   "SELECT id, name, fullname, isinactive FROM classification ORDER BY fullname",
+		feature: "Classes",
+	},
+}
+
+// SystemInfoTopics returns the supported system information topics.
+func SystemInfoTopics() []SystemInfoTopic {
+	return []SystemInfoTopic{
+		SystemInfoRoles,
+		SystemInfoSubsidiaries,
+		SystemInfoCurrencies,
+		SystemInfoDepartments,
+		SystemInfoLocations,
+		SystemInfoClasses,
+	}
+}
+
+// SystemInfoSection holds the rows of a system information topic. Tables that
+// depend on a feature do not exist while the feature is disabled, and tables
+// may be hidden from the role, in which case the section is not available and
+// Reason says why.
+type SystemInfoSection struct {
+	Topic     SystemInfoTopic `json:"topic"`
+	Feature   string          `json:"feature,omitempty"`
+	Available bool            `json:"available"`
+	Reason    string          `json:"reason,omitempty"`
+	Rows      []Row           `json:"rows"`
+	HasMore   bool            `json:"hasMore"`
+}
+
+// SystemInfo returns a section per topic, in the order given, with up to
+// MaxLimit rows each. Topics whose table NetSuite does not know, because
+// their feature is disabled, or that the role is forbidden to read are
+// reported as not available rather than failing the call. Any other error,
+// such as a rate limit, fails it.
+//
+// Enabled features and installed SuiteApps are not topics, since SuiteQL has
+// no documented table for either.
+func (c *Client) SystemInfo(ctx context.Context, topics []SystemInfoTopic) ([]SystemInfoSection, error) {
+	sections := make([]SystemInfoSection, 0, len(topics))
+	for _, topic := range topics {
+		info, ok := systemInfoQueries[topic]
+		if !ok {
+			return nil, fmt.Errorf("unknown system information topic \"%s\"", topic)
+		}
+
+		section := SystemInfoSection{
+			Topic:   topic,
+			Feature: info.feature,
+			Rows:    []Row{},
+		}
+
+		results, err := c.SuiteQLContext(ctx, info.query, MaxLimit, 0)
+		if err != nil {
+			var nsErr *NetSuiteError
+			if !errors.As(err, &nsErr) || (nsErr.UnknownTable() == "" && nsErr.StatusCode != http.StatusForbidden) {
+				return nil, fmt.Errorf("failed to get %s: %w", topic, err)
+			}
+
+			section.Reason = nsErr.Error()
+			if info.feature != "" {
+				section.Reason = fmt.Sprintf("the %s feature may be disabled, or the role may lack the permission: %v", info.feature, nsErr)
+			}
+			sections = append(sections, section)
+			continue
+		}
+
+		rows, err := results.Rows()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", topic, err)
+		}
+
+		section.Available = true
+		section.Rows = append(section.Rows, rows...)
+		section.HasMore = results.HasMore
+		sections = append(sections, section)
+	}
+
+	return sections, nil
+}
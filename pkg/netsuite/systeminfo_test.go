@@ -0,0 +1,87 @@
+package netsuite
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSystemInfo(t *testing.T) {
+	tests := []struct {
+		name          string
+		statusCode    int
+		body          string
+		wantAvailable bool
+		wantErr       bool
+	}{
+		{
+			name:          "available",
+			statusCode:    http.StatusOK,
+			body:          `{"count": 1, "hasMore": false, "items": [{"id": "1", "name": "Sales"}]}`,
+			wantAvailable: true,
+		},
+		{
+			name:       "unknown table",
+			statusCode: http.StatusBadRequest,
+			body:       `{"title": "Bad Request", "o:errorDetails": [{"detail": "Invalid search query. Detailed unprocessed description follows. Search error occurred: Record 'department' was not found.", "o:errorCode": "INVALID_PARAMETER"}]}`,
+		},
+		{
+			name:       "forbidden",
+			statusCode: http.StatusForbidden,
+			body:       `{"title": "Forbidden", "o:errorDetails": [{"detail": "Permission Violation: You need the 'Departments' permission.", "o:errorCode": "INSUFFICIENT_PERMISSION"}]}`,
+		},
+		{
+			name:       "rate limited",
+			statusCode: http.StatusTooManyRequests,
+			body:       `{"title": "Too Many Requests", "o:errorDetails": [{"detail": "Request limit exceeded.", "o:errorCode": "CONCURRENCY_LIMIT_EXCEEDED"}]}`,
+			wantErr:    true,
+		},
+		{
+			name:       "invalid query",
+			statusCode: http.StatusBadRequest,
+			body:       `{"title": "Bad Request", "o:errorDetails": [{"detail": "Invalid search query. Field 'fullname' was not found.", "o:errorCode": "INVALID_PARAMETER"}]}`,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Client{Client: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				var body struct {
+					Q string `json:"q"`
+				}
+				if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+					t.Fatalf("failed to decode request body: %v", err)
+				}
+				if table := SourceTable(body.Q); table != "department" {
+					t.Errorf("query reads %q, want department", table)
+				}
+
+				return &http.Response{
+					StatusCode: tt.statusCode,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(tt.body)),
+					Request:    req,
+				}, nil
+			})}}
+
+			sections, err := client.SystemInfo(context.Background(), []SystemInfoTopic{SystemInfoDepartments})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SystemInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if len(sections) != 1 {
+				t.Fatalf("SystemInfo() returned %d sections, want 1", len(sections))
+			}
+			if got := sections[0]; got.Available != tt.wantAvailable || (got.Reason == "") != tt.wantAvailable {
+				t.Errorf("SystemInfo() = %+v, want available %v", got, tt.wantAvailable)
+			}
+		})
+	}
+}
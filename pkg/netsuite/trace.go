@@ -0,0 +1,84 @@
+package netsuite
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// maxTracedQueryLength is the number of characters of a query string kept in
+// a trace line, since it may hold whole filter expressions.
+const maxTracedQueryLength = 80
+
+// tracingTransport logs one line per request to stderr with the method, path,
+// status, duration, and size of the response body. Hosts, headers, and
+// request bodies are never logged, and query strings are truncated.
+type tracingTransport struct {
+	next http.RoundTripper
+}
+
+func (transport *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	target := tracedTarget(req)
+
+	response, err := transport.next.RoundTrip(req)
+	if err != nil {
+		log.Printf("netsuite: %s %s error after %s: %v", req.Method, target, time.Since(start).Round(time.Millisecond), err)
+		return nil, err
+	}
+
+	response.Body = &tracedBody{
+		ReadCloser: response.Body,
+		method:     req.Method,
+		target:     target,
+		status:     response.StatusCode,
+		duration:   time.Since(start),
+	}
+
+	return response, nil
+}
+
+func tracedTarget(req *http.Request) string {
+	query := req.URL.RawQuery
+	if len(query) > maxTracedQueryLength {
+		query = query[:maxTracedQueryLength] + "..."
+	}
+
+	if query == "" {
+		return req.URL.Path
+	}
+
+	return req.URL.Path + "?" + query
+}
+
+// tracedBody counts the bytes read from a response body and writes the trace
+// line once the body is closed.
+type tracedBody struct {
+	io.ReadCloser
+
+	method   string
+	target   string
+	status   int
+	duration time.Duration
+	bytes    int64
+}
+
+func (body *tracedBody) Read(p []byte) (int, error) {
+	n, err := body.ReadCloser.Read(p)
+	body.bytes += int64(n)
+	return n, err
+}
+
+func (body *tracedBody) Close() error {
+	log.Printf(
+		"netsuite: %s %s %d %s %dB",
+		body.method,
+		body.target,
+		body.status,
+		body.duration.Round(time.Millisecond),
+		body.bytes,
+	)
+
+	return body.ReadCloser.Close()
+}
@@ -0,0 +1,100 @@
+package netsuite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ErrUnsupportedTransform is returned for transforms NetSuite does not offer
+// between two record types, such as a vendor bill into an invoice.
+var ErrUnsupportedTransform = errors.New("unsupported transform")
+
+// transforms lists the record types each record type can be transformed
+// into, by lower case name, mirroring the "Bill", "Fulfill", "Receive" and
+// similar actions of the NetSuite UI.
+var transforms = map[string][]string{
+	"opportunity":               {"estimate", "salesorder", "invoice", "cashsale"},
+	"estimate":                  {"salesorder", "invoice", "cashsale"},
+	"salesorder":                {"invoice", "cashsale", "itemfulfillment", "returnauthorization"},
+	"invoice":                   {"customerpayment", "creditmemo"},
+	"cashsale":                  {"cashrefund"},
+	"returnauthorization":       {"creditmemo", "cashrefund", "itemreceipt"},
+	"purchaseorder":             {"itemreceipt", "vendorbill", "vendorreturnauthorization"},
+	"vendorbill":                {"vendorpayment", "vendorcredit"},
+	"vendorreturnauthorization": {"vendorcredit", "itemfulfillment"},
+	"transferorder":             {"itemfulfillment", "itemreceipt"},
+	"workorder":                 {"assemblybuild", "workorderissue", "workordercompletion", "workorderclose"},
+	"assemblybuild":             {"assemblyunbuild"},
+}
+
+// checkTransform returns ErrUnsupportedTransform if NetSuite does not
+// transform the record type into the other.
+func checkTransform(fromType string, toType string) error {
+	targets, ok := transforms[strings.ToLower(fromType)]
+	if !ok {
+		return fmt.Errorf("%w: %s records cannot be transformed", ErrUnsupportedTransform, fromType)
+	}
+
+	for _, target := range targets {
+		if target == strings.ToLower(toType) {
+			return nil
+		}
+	}
+
+	sorted := append([]string{}, targets...)
+	sort.Strings(sorted)
+
+	return fmt.Errorf(
+		"%w: %s records cannot be transformed into %s, only into %s",
+		ErrUnsupportedTransform,
+		fromType,
+		toType,
+		strings.Join(sorted, ", "),
+	)
+}
+
+// TransformResult describes the outcome of TransformRecord.
+type TransformResult struct {
+	// ID is the internal ID of the new record, taken from the Location
+	// header. It is empty if NetSuite did not return one.
+	ID string `json:"id,omitempty"`
+
+	// JobID is the asynchronous job creating the record, with
+	// WithRespondAsync.
+	JobID string `json:"jobId,omitempty"`
+}
+
+// TransformRecord creates a record of toType from an existing record of
+// fromType, such as an invoice from a sales order. The body sets or overrides
+// fields of the new record and may be nil.
+func (c *Client) TransformRecord(ctx context.Context, fromType string, id string, toType string, body map[string]interface{}) (*TransformResult, error) {
+	if err := checkTransform(fromType, toType); err != nil {
+		return nil, err
+	}
+
+	// Transformable record types are all standard ones, spelled in lower case
+	fromType, toType = strings.ToLower(fromType), strings.ToLower(toType)
+
+	if body == nil {
+		body = map[string]interface{}{}
+	}
+
+	endpoint := fmt.Sprintf(
+		"/record/v1/%s/%s/!transform/%s",
+		url.PathEscape(fromType),
+		url.PathEscape(id),
+		url.PathEscape(toType),
+	)
+
+	written, err := c.sendRecord(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TransformResult{ID: written.id, JobID: written.jobID}, nil
+}
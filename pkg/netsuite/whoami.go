@@ -0,0 +1,101 @@
+package netsuite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// ErrNoIdentity is returned when the access token does not say which user and
+// role it was issued for.
+var ErrNoIdentity = errors.New("access token does not identify a user")
+
+// Identity is the user and role requests to NetSuite are made as.
+type Identity struct {
+	EntityID int    `json:"entityId"`
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email,omitempty"`
+	RoleID   int    `json:"roleId"`
+	Role     string `json:"role,omitempty"`
+}
+
+func (i *Identity) String() string {
+	name := i.Name
+	if name == "" {
+		name = fmt.Sprintf("entity %d", i.EntityID)
+	}
+	if i.Email != "" {
+		name = fmt.Sprintf("%s <%s>", name, i.Email)
+	}
+
+	role := i.Role
+	if role == "" {
+		role = fmt.Sprintf("role %d", i.RoleID)
+	}
+
+	return fmt.Sprintf("%s as %s", name, role)
+}
+
+// WhoAmI returns the user and role the credentials act as. NetSuite has no
+// REST endpoint for it, so the IDs are read from the subject of the access
+// token, a JWT whose subject is "<role ID>;<entity ID>", and their names are
+// looked up with SuiteQL. Names the role cannot read are left empty.
+func (c *Client) WhoAmI(ctx context.Context) (*Identity, error) {
+	token, err := c.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	var claims jwt.RegisteredClaims
+	if _, _, err := jwt.NewParser().ParseUnverified(token.AccessToken, &claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoIdentity, err)
+	}
+
+	identity, err := parseTokenSubject(claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	var entities []struct {
+		Name  string `json:"entityid"`
+		Email string `json:"email"`
+	}
+	if err := c.queryInto(ctx, fmt.Sprintf("SELECT entityid, email FROM entity WHERE id = %d", identity.EntityID), &entities); err == nil && len(entities) > 0 {
+		identity.Name = entities[0].Name
+		identity.Email = entities[0].Email
+	}
+
+	var roles []struct {
+		Name string `json:"name"`
+	}
+	if err := c.queryInto(ctx, fmt.Sprintf("SELECT name FROM role WHERE id = %d", identity.RoleID), &roles); err == nil && len(roles) > 0 {
+		identity.Role = roles[0].Name
+	}
+
+	return identity, nil
+}
+
+// parseTokenSubject reads the role and entity IDs from the subject of an
+// access token.
+func parseTokenSubject(subject string) (*Identity, error) {
+	roleID, entityID, found := strings.Cut(subject, ";")
+	if !found {
+		return nil, fmt.Errorf("%w: unexpected subject %q", ErrNoIdentity, subject)
+	}
+
+	role, err := strconv.Atoi(roleID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unexpected subject %q", ErrNoIdentity, subject)
+	}
+
+	entity, err := strconv.Atoi(entityID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unexpected subject %q", ErrNoIdentity, subject)
+	}
+
+	return &Identity{EntityID: entity, RoleID: role}, nil
+}
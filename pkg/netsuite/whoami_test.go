@@ -0,0 +1,86 @@
+package netsuite
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"golang.org/x/oauth2"
+)
+
+func TestWhoAmI(t *testing.T) {
+	forbidden := `{"title": "Forbidden", "o:errorDetails": [{"detail": "Permission Violation", "o:errorCode": "INSUFFICIENT_PERMISSION"}]}`
+
+	tests := []struct {
+		name   string
+		tables map[string]string
+		want   Identity
+	}{
+		{
+			name: "names found",
+			tables: map[string]string{
+				"entity": `[{"entityid": "Jane Doe", "email": "jane@example.com"}]`,
+				"role":   `[{"name": "Administrator"}]`,
+			},
+			want: Identity{EntityID: 42, Name: "Jane Doe", Email: "jane@example.com", RoleID: 3, Role: "Administrator"},
+		},
+		{
+			name:   "entity unreadable",
+			tables: map[string]string{"role": `[{"name": "Administrator"}]`},
+			want:   Identity{EntityID: 42, RoleID: 3, Role: "Administrator"},
+		},
+		{
+			name:   "nothing readable",
+			tables: map[string]string{},
+			want:   Identity{EntityID: 42, RoleID: 3},
+		},
+	}
+
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{Subject: "3;42"}).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Client{
+				Client: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+					var body struct {
+						Q string `json:"q"`
+					}
+					if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+						t.Fatalf("failed to decode request body: %v", err)
+					}
+
+					statusCode := http.StatusOK
+					rows, ok := tt.tables[SourceTable(body.Q)]
+					responseBody := `{"count": 1, "hasMore": false, "items": ` + rows + `}`
+					if !ok {
+						statusCode = http.StatusForbidden
+						responseBody = forbidden
+					}
+
+					return &http.Response{
+						StatusCode: statusCode,
+						Header:     http.Header{"Content-Type": []string{"application/json"}},
+						Body:       io.NopCloser(strings.NewReader(responseBody)),
+						Request:    req,
+					}, nil
+				})},
+				tokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: accessToken}),
+			}
+
+			got, err := client.WhoAmI(context.Background())
+			if err != nil {
+				t.Fatalf("WhoAmI() error = %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("WhoAmI() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}